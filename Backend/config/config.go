@@ -0,0 +1,23 @@
+// Package config reads settings from the environment: the parser every
+// package uses, and the values more than one package depends on.
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// HoldPickupWindow is how long a copy stays set aside for a ready hold:
+// HOLD_PICKUP_DAYS, default 7, at least 1. The API and the expiry job
+// both use it, so they cannot disagree.
+var HoldPickupWindow = time.Duration(Int("HOLD_PICKUP_DAYS", 7, 1)) * 24 * time.Hour
+
+// Int reads an int setting of at least min, falling back to def when
+// the variable is unset or out of range.
+func Int(key string, def, min int) int {
+	if n, err := strconv.Atoi(os.Getenv(key)); err == nil && n >= min {
+		return n
+	}
+	return def
+}
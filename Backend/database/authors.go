@@ -0,0 +1,105 @@
+package database
+
+import (
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/hasan-kayan/TaskGo/models"
+)
+
+/*───────────────────────────────────────────────────────────────*
+|        Authors: schema, byline backfill and credit links      |
+*───────────────────────────────────────────────────────────────*/
+
+// migrateAuthors creates the authors / book_authors tables. The first
+// time they appear, every existing book (trashed ones included) has
+// its free-text byline turned into author records and links.
+func migrateAuthors(db *gorm.DB) error {
+	fresh := !db.Migrator().HasTable(&models.BookAuthor{})
+	if err := db.AutoMigrate(&models.Author{}, &models.BookAuthor{}); err != nil {
+		return err
+	}
+	if !fresh {
+		return nil
+	}
+
+	var linked int
+	var batch []models.Book
+	err := db.Unscoped().Where("author <> ''").FindInBatches(&batch, 500, func(_ *gorm.DB, _ int) error {
+		return db.Transaction(func(tx *gorm.DB) error {
+			for i := range batch {
+				if err := LinkBylineAuthors(tx, &batch[i]); err != nil {
+					return err
+				}
+			}
+			linked += len(batch)
+			return nil
+		})
+	}).Error
+	if err != nil {
+		return err
+	}
+	if linked > 0 {
+		log.Printf("✅ linked %d existing books to author records", linked)
+	}
+	return nil
+}
+
+// LinkBylineAuthors splits b.Author into names, finds or creates an
+// Author for each (case-insensitive exact match) and links them with
+// the author role.
+func LinkBylineAuthors(tx *gorm.DB, b *models.Book) error {
+	for i, name := range models.SplitAuthorNames(b.Author) {
+		var author models.Author
+		err := tx.Where("LOWER(name) = ?", strings.ToLower(name)).
+			Order("created_at").
+			Limit(1).
+			Find(&author).Error
+		if err != nil {
+			return err
+		}
+		if author.ID == uuid.Nil {
+			author = models.Author{Name: name}
+			if err := tx.Create(&author).Error; err != nil {
+				return err
+			}
+		}
+
+		link := models.BookAuthor{BookID: b.ID, AuthorID: author.ID, Role: models.RoleAuthor, Position: i}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&link).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SyncBylineAuthors moves b's author credits from the names in the old
+// byline to the names in b.Author. Credits that did not come from the
+// old byline – other roles, authors added via PUT /books/:id/authors –
+// are kept.
+func SyncBylineAuthors(tx *gorm.DB, oldByline string, b *models.Book) error {
+	keep := map[string]bool{}
+	for _, name := range models.SplitAuthorNames(b.Author) {
+		keep[strings.ToLower(name)] = true
+	}
+	var dropped []string
+	for _, name := range models.SplitAuthorNames(oldByline) {
+		if !keep[strings.ToLower(name)] {
+			dropped = append(dropped, strings.ToLower(name))
+		}
+	}
+
+	if len(dropped) > 0 {
+		err := tx.Where("book_id = ? AND role = ?", b.ID, models.RoleAuthor).
+			Where("author_id IN (?)", tx.Model(&models.Author{}).Select("id").Where("LOWER(name) IN ?", dropped)).
+			Delete(&models.BookAuthor{}).Error
+		if err != nil {
+			return err
+		}
+	}
+	return LinkBylineAuthors(tx, b)
+}
@@ -0,0 +1,109 @@
+package database
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/hasan-kayan/TaskGo/models"
+)
+
+/*───────────────────────────────────────────────────────────────*
+|        Records derived from a book's free-text fields         |
+*───────────────────────────────────────────────────────────────*/
+
+// Every book created through gorm – single rows, bulk inserts, imports –
+// has its byline credited to Author records and is tagged with its
+// Type. Updates that change either – PUT, PATCH, bulk, merge, revert –
+// re-sync the author credits or the Type tag. The callbacks run inside
+// the write's transaction, like the revision callbacks.
+
+// RegisterCallbacks hooks revision recording and the derived book links
+// into db. Safe to call more than once.
+func RegisterCallbacks(db *gorm.DB) error {
+	if err := RegisterRevisionCallbacks(db); err != nil {
+		return err
+	}
+	cb := db.Callback()
+	if cb.Create().Get("books:link_create") != nil {
+		return nil
+	}
+	if err := cb.Create().After("gorm:create").Register("books:link_create", linkCreatedBooks); err != nil {
+		return err
+	}
+	// the rows as they were are captured by the revision callbacks
+	return cb.Update().After("gorm:update").Register("books:link_update", relinkUpdatedBooks)
+}
+
+// linkCreatedBooks reads the inserted books back and links them.
+func linkCreatedBooks(db *gorm.DB) {
+	if !touchesBooks(db) {
+		return
+	}
+	ids := statementIDs(db)
+	if len(ids) == 0 {
+		return
+	}
+	tx := db.Session(&gorm.Session{NewDB: true})
+	var books []models.Book
+	if err := tx.Unscoped().Where("id IN ?", ids).Find(&books).Error; err != nil {
+		db.AddError(err)
+		return
+	}
+	for i := range books {
+		if err := LinkBylineAuthors(tx, &books[i]); err != nil {
+			db.AddError(err)
+			return
+		}
+		if err := LinkTypeTag(tx, &books[i]); err != nil {
+			db.AddError(err)
+			return
+		}
+	}
+}
+
+// relinkUpdatedBooks compares the books captured before the update with
+// their stored state and re-syncs what their changed fields derive.
+func relinkUpdatedBooks(db *gorm.DB) {
+	if !touchesBooks(db) {
+		return
+	}
+	v, _ := db.InstanceGet(revisionsBeforeKey)
+	before, _ := v.([]models.Book)
+	if len(before) == 0 {
+		return
+	}
+
+	ids := make([]uuid.UUID, len(before))
+	for i := range before {
+		ids[i] = before[i].ID
+	}
+	tx := db.Session(&gorm.Session{NewDB: true})
+	var after []models.Book
+	if err := tx.Unscoped().Where("id IN ?", ids).Find(&after).Error; err != nil {
+		db.AddError(err)
+		return
+	}
+	byID := make(map[uuid.UUID]*models.Book, len(after))
+	for i := range after {
+		byID[after[i].ID] = &after[i]
+	}
+
+	for i := range before {
+		now, ok := byID[before[i].ID]
+		if !ok {
+			continue
+		}
+		if now.Author != before[i].Author {
+			if err := SyncBylineAuthors(tx, before[i].Author, now); err != nil {
+				db.AddError(err)
+				return
+			}
+		}
+		if now.Type != before[i].Type {
+			if err := SyncTypeTag(tx, before[i].Type, now); err != nil {
+				db.AddError(err)
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package database
+
+import (
+	"log"
+
+	"gorm.io/gorm"
+
+	"github.com/hasan-kayan/TaskGo/models"
+)
+
+/*───────────────────────────────────────────────────────────────*
+|          Circulation: copies, members, loans, holds           |
+*───────────────────────────────────────────────────────────────*/
+
+// A copy can be on at most one active loan. Checkout also checks this
+// inside its transaction; the partial index makes it hold even when two
+// checkouts race.
+const activeLoanIndexDDL = `CREATE UNIQUE INDEX IF NOT EXISTS idx_loans_active_copy
+	ON loans(copy_id) WHERE returned_at IS NULL`
+
+// A member queues at most once per book, and a copy is set aside for at
+// most one hold at a time.
+var holdIndexDDL = []string{
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_holds_open_member
+	ON holds(book_id, member_id) WHERE status IN ('waiting', 'ready')`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_holds_ready_copy
+	ON holds(copy_id) WHERE status = 'ready'`,
+}
+
+func migrateCirculation(db *gorm.DB) error {
+	if err := db.AutoMigrate(&models.Copy{}, &models.Member{}, &models.Loan{}, &models.Hold{}); err != nil {
+		return err
+	}
+	for _, ddl := range append([]string{activeLoanIndexDDL}, holdIndexDDL...) {
+		if err := db.Exec(ddl).Error; err != nil {
+			if !IsUniqueViolation(err) {
+				return err
+			}
+			log.Printf("⚠️  duplicate circulation rows – index not created: %v", err)
+		}
+	}
+	return nil
+}
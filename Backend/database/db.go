@@ -58,8 +58,12 @@ func ConnectDB() {
 		log.Fatalf("❌ database connection failed: %v", err)
 	}
 
+	if err := RegisterCallbacks(db); err != nil {
+		log.Fatalf("❌ book callbacks: %v", err)
+	}
+
 	if autoMigrate {
-		if err := db.AutoMigrate(&models.Book{}); err != nil {
+		if err := Migrate(db); err != nil {
 			log.Fatalf("❌ auto-migration failed: %v", err)
 		}
 	}
@@ -68,6 +72,49 @@ func ConnectDB() {
 	log.Printf("✅ database initialised (%s)", dsn)
 }
 
+/*───────────────────────────────────────────────────────────────*
+|                          Migrations                           |
+*───────────────────────────────────────────────────────────────*/
+
+// Migrate brings the schema up to date: GORM AutoMigrate for the models,
+// then the raw-SQL pieces GORM cannot express. Safe to run repeatedly.
+func Migrate(db *gorm.DB) error {
+	fresh := !db.Migrator().HasTable(&models.Publisher{})
+	if err := db.AutoMigrate(&models.Publisher{}, &models.Series{}, &models.Book{}, &models.BookRevision{}); err != nil {
+		return err
+	}
+	if err := setupBookHistory(db); err != nil {
+		return err
+	}
+	if fresh {
+		if err := backfillPublishers(db); err != nil {
+			return err
+		}
+	}
+	if err := migrateAuthors(db); err != nil {
+		return err
+	}
+	if err := migrateTags(db); err != nil {
+		return err
+	}
+	if err := migrateCirculation(db); err != nil {
+		return err
+	}
+	if err := migrateFines(db); err != nil {
+		return err
+	}
+	if err := migrateReviews(db); err != nil {
+		return err
+	}
+	if err := migrateReadingLists(db); err != nil {
+		return err
+	}
+	if err := setupISBNIndex(db); err != nil {
+		return err
+	}
+	return setupSearchIndex(db)
+}
+
 /*───────────────────────────────────────────────────────────────*
 |                         helpers                               |
 *───────────────────────────────────────────────────────────────*/
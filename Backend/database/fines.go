@@ -0,0 +1,96 @@
+package database
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/hasan-kayan/TaskGo/models"
+)
+
+/*───────────────────────────────────────────────────────────────*
+|     Fines: policies per book type, closed days, assessment    |
+*───────────────────────────────────────────────────────────────*/
+
+// migrateFines creates the fine tables and seeds the default policy so
+// every book Type is charged by something.
+func migrateFines(db *gorm.DB) error {
+	if err := db.AutoMigrate(&models.FinePolicy{}, &models.ClosedDay{}, &models.Fine{}); err != nil {
+		return err
+	}
+	def := models.DefaultFinePolicy
+	return db.Where(models.FinePolicy{Type: models.DefaultPolicyType}).FirstOrCreate(&def).Error
+}
+
+// FinePolicyFor returns the policy for a book Type, falling back to the
+// default one.
+func FinePolicyFor(tx *gorm.DB, bookType string) (*models.FinePolicy, error) {
+	var policies []models.FinePolicy
+	err := tx.Where("LOWER(type) IN (LOWER(?), ?)", bookType, models.DefaultPolicyType).Find(&policies).Error
+	if err != nil {
+		return nil, err
+	}
+	policy := models.FinePolicy{Type: models.DefaultPolicyType}
+	for _, p := range policies {
+		if p.Type != models.DefaultPolicyType {
+			return &p, nil
+		}
+		policy = p
+	}
+	return &policy, nil
+}
+
+// AssessFine brings the loan's fine up to date as of until, creating it
+// once the loan costs something. Final fines are left alone. Returns
+// nil when there is nothing to charge.
+func AssessFine(tx *gorm.DB, loan *models.Loan, until time.Time) (*models.Fine, error) {
+	var fine models.Fine
+	if err := tx.Where("loan_id = ?", loan.ID).Limit(1).Find(&fine).Error; err != nil {
+		return nil, err
+	}
+	if fine.Final {
+		return &fine, nil
+	}
+	if loan.ReturnedAt != nil {
+		until = *loan.ReturnedAt
+	}
+
+	var book models.Book
+	tx.Unscoped().Select("type").Limit(1).Find(&book, "id = ?", loan.BookID)
+	policy, err := FinePolicyFor(tx, book.Type)
+	if err != nil {
+		return nil, err
+	}
+	var dates []string
+	err = tx.Model(&models.ClosedDay{}).
+		Where("date > ? AND date <= ?", loan.DueAt.Local().Format(time.DateOnly), until.Local().Format(time.DateOnly)).
+		Pluck("date", &dates).Error
+	if err != nil {
+		return nil, err
+	}
+	closed := make(map[string]bool, len(dates))
+	for _, d := range dates {
+		closed[d] = true
+	}
+
+	days, amount := policy.Assess(loan.DueAt, until, closed)
+	if fine.ID == uuid.Nil && amount == 0 {
+		return nil, nil
+	}
+	fine.PolicyType, fine.OverdueDays, fine.Amount = policy.Type, days, amount
+	fine.Final = loan.ReturnedAt != nil
+	if fine.ID == uuid.Nil {
+		fine.ID, fine.LoanID, fine.MemberID, fine.BookID = uuid.New(), loan.ID, loan.MemberID, loan.BookID
+		err = tx.Create(&fine).Error
+	} else {
+		err = tx.Model(&models.Fine{ID: fine.ID}).
+			Select("policy_type", "overdue_days", "amount", "final").
+			Updates(&fine).Error
+	}
+	if err != nil {
+		return nil, err
+	}
+	fine.Balance = fine.Amount - fine.Paid - fine.Waived
+	return &fine, nil
+}
@@ -0,0 +1,98 @@
+package database
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/hasan-kayan/TaskGo/models"
+)
+
+/*───────────────────────────────────────────────────────────────*
+|              Hold queues: assigning and closing holds         |
+*───────────────────────────────────────────────────────────────*/
+
+// AssignNextHold gives the copy to the oldest waiting hold on its book,
+// if the copy is free: not on loan and not already set aside. The hold
+// must be picked up within window. Returns the hold, or nil when there
+// was nothing to do.
+func AssignNextHold(tx *gorm.DB, copy *models.Copy, now time.Time, window time.Duration) (*models.Hold, error) {
+	var busy int64
+	err := tx.Raw(`SELECT
+		(SELECT COUNT(*) FROM loans WHERE copy_id = ? AND returned_at IS NULL) +
+		(SELECT COUNT(*) FROM holds WHERE copy_id = ? AND status = ?)`,
+		copy.ID, copy.ID, models.HoldReady).Scan(&busy).Error
+	if err != nil || busy > 0 {
+		return nil, err
+	}
+
+	var next models.Hold
+	err = tx.Where("book_id = ? AND status = ?", copy.BookID, models.HoldWaiting).
+		Order("created_at").Order("id").
+		Limit(1).
+		Find(&next).Error
+	if err != nil || next.ID == uuid.Nil {
+		return nil, err
+	}
+
+	expires := now.Add(window)
+	res := tx.Model(&models.Hold{}).
+		Where("id = ? AND status = ?", next.ID, models.HoldWaiting).
+		Updates(map[string]any{"status": models.HoldReady, "copy_id": copy.ID, "ready_at": now, "expires_at": expires})
+	if res.Error != nil || res.RowsAffected == 0 {
+		return nil, res.Error
+	}
+	next.Status, next.CopyID, next.ReadyAt, next.ExpiresAt = models.HoldReady, &copy.ID, &now, &expires
+	return &next, nil
+}
+
+// CloseHolds moves the open holds matched by query to status (fulfilled,
+// cancelled or expired) and hands any copy they had set aside to the
+// next in line. Returns how many holds were closed.
+func CloseHolds(tx *gorm.DB, status string, now time.Time, window time.Duration, query any, args ...any) (int64, error) {
+	var holds []models.Hold
+	err := tx.Where(query, args...).
+		Where("status IN ?", []string{models.HoldWaiting, models.HoldReady}).
+		Order("created_at").Order("id").
+		Find(&holds).Error
+	if err != nil {
+		return 0, err
+	}
+
+	var closed int64
+	for _, h := range holds {
+		// guarded by the old status so a racing close counts once
+		res := tx.Model(&models.Hold{}).
+			Where("id = ? AND status = ?", h.ID, h.Status).
+			Updates(map[string]any{"status": status, "closed_at": now})
+		if res.Error != nil {
+			return closed, res.Error
+		}
+		if res.RowsAffected == 0 {
+			continue
+		}
+		closed++
+		if h.Status == models.HoldReady && h.CopyID != nil {
+			if _, err := AssignNextHold(tx, &models.Copy{ID: *h.CopyID, BookID: h.BookID}, now, window); err != nil {
+				return closed, err
+			}
+		}
+	}
+	return closed, nil
+}
+
+// AssignFreeCopies runs AssignNextHold for every copy of the book, e.g.
+// after a hold is placed or a copy is added.
+func AssignFreeCopies(tx *gorm.DB, bookID uuid.UUID, now time.Time, window time.Duration) error {
+	var copies []models.Copy
+	if err := tx.Where("book_id = ?", bookID).Order("barcode").Find(&copies).Error; err != nil {
+		return err
+	}
+	for i := range copies {
+		if _, err := AssignNextHold(tx, &copies[i], now, window); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package database
+
+import (
+	"errors"
+	"log"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/hasan-kayan/TaskGo/utils"
+)
+
+/*───────────────────────────────────────────────────────────────*
+|                  ISBN canonical form + uniqueness             |
+*───────────────────────────────────────────────────────────────*/
+
+// Live books may not share an ISBN. The index is partial so books
+// without an ISBN – and books sitting in the trash – never collide.
+const isbnIndexDDL = `CREATE UNIQUE INDEX IF NOT EXISTS idx_books_isbn_unique
+	ON books(isbn) WHERE isbn <> '' AND deleted_at IS NULL`
+
+// IsUniqueViolation reports whether err comes from any unique index,
+// with or without gorm's TranslateError enabled.
+func IsUniqueViolation(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return true
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "UNIQUE constraint failed") || // sqlite
+		strings.Contains(msg, "duplicate key value") // postgres
+}
+
+// IsISBNViolation reports whether err comes from the unique ISBN index
+// rather than another unique key such as the primary key. It needs the
+// driver's message, so the connection must not use TranslateError.
+func IsISBNViolation(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "UNIQUE constraint failed: books.isbn") || // sqlite
+		strings.Contains(msg, "idx_books_isbn_unique") // postgres
+}
+
+func setupISBNIndex(db *gorm.DB) error {
+	if err := backfillISBNs(db); err != nil {
+		return err
+	}
+	if err := db.Exec(isbnIndexDDL).Error; err != nil {
+		if !IsUniqueViolation(err) {
+			return err
+		}
+		// old data with duplicates must not stop the service from booting;
+		// inserts stay unguarded until the duplicates are cleaned up
+		log.Printf("⚠️  duplicate ISBNs in books – unique index not created: %v", err)
+	}
+	return nil
+}
+
+// backfillISBNs rewrites rows stored before ISBNs were normalised.
+// Rows with an ISBN that fails the checksum are left untouched.
+func backfillISBNs(db *gorm.DB) error {
+	var rows []models.Book
+	err := db.Unscoped().
+		Select("id", "isbn", "isbn10").
+		Where("isbn <> '' AND (isbn10 IS NULL OR isbn10 = '' OR LENGTH(isbn) <> 13)").
+		Find(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	for _, b := range rows {
+		isbn13, isbn10, err := utils.NormaliseISBN(b.ISBN)
+		if err != nil || (isbn13 == b.ISBN && isbn10 == b.ISBN10) {
+			continue
+		}
+		err = db.Unscoped().Model(&models.Book{}).
+			Where("id = ?", b.ID).
+			UpdateColumns(map[string]any{"isbn": isbn13, "isbn10": isbn10}).Error
+		if err != nil && !IsUniqueViolation(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package database
+
+import (
+	"log"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/hasan-kayan/TaskGo/models"
+)
+
+/*───────────────────────────────────────────────────────────────*
+|              Publishers: one-off free-text backfill           |
+*───────────────────────────────────────────────────────────────*/
+
+// backfillPublishers runs once, when the publishers table is created:
+// each distinct publisher string (compared trimmed and case-folded)
+// becomes a Publisher and its books point at it. Spelling variants
+// that differ by more than case stay separate – fold them with
+// POST /publishers/:id/merge.
+func backfillPublishers(db *gorm.DB) error {
+	var names []string
+	err := db.Unscoped().Model(&models.Book{}).
+		Where("publisher <> '' AND publisher_id IS NULL").
+		Order("created_at").
+		Pluck("publisher", &names).Error
+	if err != nil {
+		return err
+	}
+
+	// first spelling seen wins the display name
+	groups := map[string]string{}
+	var order []string
+	for _, n := range names {
+		key := strings.ToLower(strings.TrimSpace(n))
+		if _, ok := groups[key]; ok || key == "" {
+			continue
+		}
+		groups[key] = strings.TrimSpace(n)
+		order = append(order, key)
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, key := range order {
+			p := models.Publisher{Name: groups[key]}
+			if err := tx.Create(&p).Error; err != nil {
+				return err
+			}
+			err := tx.Unscoped().Model(&models.Book{}).
+				Where("LOWER(TRIM(publisher)) = ? AND publisher_id IS NULL", key).
+				UpdateColumns(map[string]any{"publisher_id": p.ID, "version": gorm.Expr("version + 1")}).Error
+			if err != nil {
+				return err
+			}
+		}
+		if len(order) > 0 {
+			log.Printf("✅ created %d publishers from existing books", len(order))
+		}
+		return nil
+	})
+}
@@ -0,0 +1,17 @@
+package database
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/hasan-kayan/TaskGo/models"
+)
+
+/*───────────────────────────────────────────────────────────────*
+|                  Reading lists and their items                |
+*───────────────────────────────────────────────────────────────*/
+
+// Items carry no foreign key to books: a list keeps its place for a
+// book that is trashed (and may come back). PurgeBook removes them.
+func migrateReadingLists(db *gorm.DB) error {
+	return db.AutoMigrate(&models.ReadingList{}, &models.ReadingListItem{})
+}
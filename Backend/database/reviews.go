@@ -0,0 +1,45 @@
+package database
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/hasan-kayan/TaskGo/models"
+)
+
+/*───────────────────────────────────────────────────────────────*
+|               Reviews and per-book rating totals              |
+*───────────────────────────────────────────────────────────────*/
+
+// migrateReviews creates the review tables. The rating totals are
+// rebuilt from the reviews when their table is new.
+func migrateReviews(db *gorm.DB) error {
+	fresh := !db.Migrator().HasTable(&models.BookRating{})
+	if err := db.AutoMigrate(&models.Review{}, &models.BookRating{}); err != nil {
+		return err
+	}
+	if !fresh {
+		return nil
+	}
+	return db.Exec(`INSERT INTO book_ratings (book_id, count, sum, average)
+		SELECT book_id, COUNT(*), SUM(rating), AVG(rating * 1.0) FROM reviews GROUP BY book_id`).Error
+}
+
+// AdjustRating adds count reviews worth sum stars to the book's totals;
+// removals pass negative numbers.
+func AdjustRating(tx *gorm.DB, bookID uuid.UUID, count, sum int64) error {
+	err := tx.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "book_id"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"count": gorm.Expr("book_ratings.count + ?", count),
+			"sum":   gorm.Expr("book_ratings.sum + ?", sum),
+		}),
+	}).Create(&models.BookRating{BookID: bookID, Count: count, Sum: sum}).Error
+	if err != nil {
+		return err
+	}
+	return tx.Model(&models.BookRating{}).
+		Where("book_id = ?", bookID).
+		Update("average", gorm.Expr("CASE WHEN count > 0 THEN sum * 1.0 / count ELSE 0 END")).Error
+}
@@ -0,0 +1,300 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/hasan-kayan/TaskGo/models"
+)
+
+/*───────────────────────────────────────────────────────────────*
+|                  Book revision history                        |
+*───────────────────────────────────────────────────────────────*/
+
+// Every create, update and delete that touches the books table – single
+// rows, bulk statements, the trash purger – writes one BookRevision per
+// affected book. The callbacks run inside the write's transaction, so a
+// write and its revision land (or roll back) together.
+
+const revisionsBeforeKey = "revisions:before"
+
+type revisionCtxKey int
+
+const (
+	actorKey revisionCtxKey = iota
+	noteKey
+)
+
+// WithActor names who is writing; callbacks read it from the statement
+// context. Writes without one are recorded as "system".
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// WithRevisionNote attaches a free-text note to the revisions written
+// under ctx (e.g. "reverted to revision 3").
+func WithRevisionNote(ctx context.Context, note string) context.Context {
+	return context.WithValue(ctx, noteKey, note)
+}
+
+func actorFrom(ctx context.Context) string {
+	if a, _ := ctx.Value(actorKey).(string); a != "" {
+		return a
+	}
+	return "system"
+}
+
+// RegisterRevisionCallbacks hooks revision recording into db. Safe to
+// call more than once.
+func RegisterRevisionCallbacks(db *gorm.DB) error {
+	cb := db.Callback()
+	if cb.Create().Get("revisions:create") != nil {
+		return nil
+	}
+	if err := cb.Create().After("gorm:create").Register("revisions:create", recordCreated); err != nil {
+		return err
+	}
+	if err := cb.Update().Before("gorm:update").Register("revisions:before_update", captureBooks); err != nil {
+		return err
+	}
+	if err := cb.Update().After("gorm:update").Register("revisions:update", recordChanged); err != nil {
+		return err
+	}
+	if err := cb.Delete().Before("gorm:delete").Register("revisions:before_delete", captureBooks); err != nil {
+		return err
+	}
+	return cb.Delete().After("gorm:delete").Register("revisions:delete", recordChanged)
+}
+
+func touchesBooks(db *gorm.DB) bool {
+	return db.Error == nil && db.Statement.Schema != nil && db.Statement.Table == "books"
+}
+
+// recordCreated reads the inserted rows back and writes their first revision.
+func recordCreated(db *gorm.DB) {
+	if !touchesBooks(db) {
+		return
+	}
+	ids := statementIDs(db)
+	if len(ids) == 0 {
+		return
+	}
+	after, err := loadBookStates(db, ids)
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+	var changed []uuid.UUID
+	for _, id := range ids {
+		if state, ok := after[id]; ok {
+			wrote, err := writeRevision(db, id, models.ActionCreate, nil, state)
+			if err != nil {
+				db.AddError(err)
+				return
+			}
+			if wrote {
+				changed = append(changed, id)
+			}
+		}
+	}
+	advanceHistory(db, changed)
+}
+
+// captureBooks snapshots the rows an update or delete is about to touch,
+// using the statement's own conditions.
+func captureBooks(db *gorm.DB) {
+	if !touchesBooks(db) {
+		return
+	}
+	where, hasWhere := db.Statement.Clauses["WHERE"]
+	ids := statementIDs(db)
+	if !hasWhere && len(ids) == 0 && !db.Statement.AllowGlobalUpdate {
+		return // gorm refuses the write anyway
+	}
+
+	q := db.Session(&gorm.Session{NewDB: true}).Unscoped().Model(&models.Book{})
+	if hasWhere {
+		q = q.Clauses(where.Expression)
+	}
+	if len(ids) > 0 {
+		q = q.Where("id IN ?", ids)
+	}
+	if !db.Statement.Unscoped {
+		q = q.Where("deleted_at IS NULL")
+	}
+	var rows []models.Book
+	if err := q.Find(&rows).Error; err != nil {
+		db.AddError(err)
+		return
+	}
+	db.InstanceSet(revisionsBeforeKey, rows)
+}
+
+// recordChanged diffs the captured rows against their stored state and
+// writes a revision for each book that actually changed.
+func recordChanged(db *gorm.DB) {
+	if !touchesBooks(db) {
+		return
+	}
+	v, ok := db.InstanceGet(revisionsBeforeKey)
+	rows, _ := v.([]models.Book)
+	if !ok || len(rows) == 0 {
+		return
+	}
+
+	ids := make([]uuid.UUID, len(rows))
+	for i := range rows {
+		ids[i] = rows[i].ID
+	}
+	after, err := loadBookStates(db, ids)
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+
+	var changed []uuid.UUID
+	for i := range rows {
+		before := bookState(&rows[i])
+		now, exists := after[rows[i].ID]
+		action := models.ActionUpdate
+		switch {
+		case !exists:
+			action, now = models.ActionPurge, nil
+		case before["deleted_at"] == nil && now["deleted_at"] != nil:
+			action = models.ActionDelete
+		case before["deleted_at"] != nil && now["deleted_at"] == nil:
+			action = models.ActionRestore
+		}
+		wrote, err := writeRevision(db, rows[i].ID, action, before, now)
+		if err != nil {
+			db.AddError(err)
+			return
+		}
+		if wrote {
+			changed = append(changed, rows[i].ID)
+		}
+	}
+	advanceHistory(db, changed)
+}
+
+// advanceHistory moves the temporal history of the changed books on.
+func advanceHistory(db *gorm.DB, changed []uuid.UUID) {
+	if len(changed) == 0 {
+		return
+	}
+	if err := recordHistory(db, changed, db.NowFunc()); err != nil {
+		db.AddError(err)
+	}
+}
+
+// writeRevision appends the next revision of the book unless nothing
+// visible changed (a lost version race, say), and reports whether it did.
+func writeRevision(db *gorm.DB, bookID uuid.UUID, action string, before, after models.JSONObject) (bool, error) {
+	changes := diffStates(before, after)
+	if len(changes) == 0 {
+		return false, nil
+	}
+
+	tx := db.Session(&gorm.Session{NewDB: true})
+	var last int
+	err := tx.Model(&models.BookRevision{}).
+		Where("book_id = ?", bookID).
+		Select("COALESCE(MAX(revision), 0)").
+		Scan(&last).Error
+	if err != nil {
+		return false, err
+	}
+
+	ctx := db.Statement.Context
+	note, _ := ctx.Value(noteKey).(string)
+	// set the ID here: UpdateColumns skips hooks for nested statements too
+	err = tx.Create(&models.BookRevision{
+		ID:       uuid.New(),
+		BookID:   bookID,
+		Revision: last + 1,
+		Action:   action,
+		Actor:    actorFrom(ctx),
+		Note:     note,
+		Changes:  changes,
+		Snapshot: after,
+	}).Error
+	return err == nil, err
+}
+
+// statementIDs collects the primary keys of the statement's model value,
+// whether it is one book or a slice of them.
+func statementIDs(db *gorm.DB) []uuid.UUID {
+	var ids []uuid.UUID
+	add := func(v reflect.Value) {
+		for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return
+			}
+			v = v.Elem()
+		}
+		if !v.CanAddr() {
+			return
+		}
+		if b, ok := v.Addr().Interface().(*models.Book); ok && b.ID != uuid.Nil {
+			ids = append(ids, b.ID)
+		}
+	}
+
+	rv := db.Statement.ReflectValue
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			add(rv.Index(i))
+		}
+	case reflect.Struct:
+		add(rv)
+	}
+	return ids
+}
+
+func loadBookStates(db *gorm.DB, ids []uuid.UUID) (map[uuid.UUID]models.JSONObject, error) {
+	var rows []models.Book
+	err := db.Session(&gorm.Session{NewDB: true}).Unscoped().Where("id IN ?", ids).Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	states := make(map[uuid.UUID]models.JSONObject, len(rows))
+	for i := range rows {
+		states[rows[i].ID] = bookState(&rows[i])
+	}
+	return states, nil
+}
+
+// bookState is the book's JSON representation as a generic object, so
+// states compare the same way whether they came from the DB or a snapshot.
+func bookState(b *models.Book) models.JSONObject {
+	raw, _ := json.Marshal(b)
+	var state models.JSONObject
+	_ = json.Unmarshal(raw, &state)
+	delete(state, "etag") // derived, and unset when hooks are skipped
+	return state
+}
+
+// untrackedFields change on every write and would only add noise.
+var untrackedFields = map[string]bool{
+	"id": true, "created_at": true, "updated_at": true, "version": true,
+}
+
+func diffStates(before, after models.JSONObject) models.FieldChanges {
+	changes := models.FieldChanges{}
+	for k, to := range after {
+		if from := before[k]; !untrackedFields[k] && !reflect.DeepEqual(from, to) {
+			changes[k] = models.FieldChange{From: from, To: to}
+		}
+	}
+	for k, from := range before {
+		if _, ok := after[k]; !ok && !untrackedFields[k] {
+			changes[k] = models.FieldChange{From: from, To: nil}
+		}
+	}
+	return changes
+}
@@ -0,0 +1,81 @@
+package database
+
+import (
+	"log"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+/*───────────────────────────────────────────────────────────────*
+|                 Full-text index (SQLite FTS5)                 |
+*───────────────────────────────────────────────────────────────*/
+
+// SearchIndexEnabled is true once the books_fts index exists.
+//
+// FTS5 is only compiled into go-sqlite3 with the `sqlite_fts5` build tag
+// (see Makefile / Dockerfile). Without it – or on non-SQLite dialects –
+// search falls back to LIKE matching.
+var SearchIndexEnabled bool
+
+// books_fts is an external-content FTS5 table: it stores only the index
+// and reads column values from books via rowid. Triggers keep it in sync
+// with every INSERT / UPDATE / DELETE, whichever code path issues them.
+var searchIndexDDL = []string{
+	`CREATE VIRTUAL TABLE IF NOT EXISTS books_fts USING fts5(
+		title, author, description,
+		content='books', content_rowid='rowid',
+		tokenize='unicode61 remove_diacritics 2'
+	)`,
+	`CREATE TRIGGER IF NOT EXISTS books_fts_ai AFTER INSERT ON books BEGIN
+		INSERT INTO books_fts(rowid, title, author, description)
+		VALUES (new.rowid, new.title, new.author, new.description);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS books_fts_ad AFTER DELETE ON books BEGIN
+		INSERT INTO books_fts(books_fts, rowid, title, author, description)
+		VALUES ('delete', old.rowid, old.title, old.author, old.description);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS books_fts_au AFTER UPDATE ON books BEGIN
+		INSERT INTO books_fts(books_fts, rowid, title, author, description)
+		VALUES ('delete', old.rowid, old.title, old.author, old.description);
+		INSERT INTO books_fts(rowid, title, author, description)
+		VALUES (new.rowid, new.title, new.author, new.description);
+	END`,
+	// AutoMigrate may rebuild the books table (new rowids, triggers gone),
+	// so re-index from scratch on every boot.
+	`INSERT INTO books_fts(books_fts) VALUES ('rebuild')`,
+}
+
+func setupSearchIndex(db *gorm.DB) error {
+	if db.Dialector.Name() != "sqlite" {
+		SearchIndexEnabled = false
+		return nil
+	}
+
+	for _, stmt := range searchIndexDDL {
+		if err := db.Exec(stmt).Error; err != nil {
+			if strings.Contains(err.Error(), "no such module: fts5") {
+				log.Println("⚠️  FTS5 not compiled in (build with -tags sqlite_fts5) – using LIKE search")
+				SearchIndexEnabled = false
+				return dropSearchTriggers(db)
+			}
+			return err
+		}
+	}
+
+	SearchIndexEnabled = true
+	return nil
+}
+
+// dropSearchTriggers removes the sync triggers a build with FTS5 left
+// behind. Without the module they would fail every write to books.
+// The books_fts table itself cannot be dropped without the module and
+// is harmless; it is rebuilt when FTS5 comes back.
+func dropSearchTriggers(db *gorm.DB) error {
+	for _, name := range []string{"books_fts_ai", "books_fts_ad", "books_fts_au"} {
+		if err := db.Exec("DROP TRIGGER IF EXISTS " + name).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package database
+
+import (
+	"log"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/hasan-kayan/TaskGo/models"
+)
+
+/*───────────────────────────────────────────────────────────────*
+|            Tags: schema, Type backfill and Type tags          |
+*───────────────────────────────────────────────────────────────*/
+
+// migrateTags creates the tags / book_tags tables. The first time they
+// appear, every existing book (trashed ones included) is tagged with
+// its free-text Type.
+func migrateTags(db *gorm.DB) error {
+	fresh := !db.Migrator().HasTable(&models.BookTag{})
+	if err := db.AutoMigrate(&models.Tag{}, &models.BookTag{}); err != nil {
+		return err
+	}
+	if !fresh {
+		return nil
+	}
+
+	var tagged int
+	var batch []models.Book
+	err := db.Unscoped().Where("type <> ''").FindInBatches(&batch, 500, func(_ *gorm.DB, _ int) error {
+		return db.Transaction(func(tx *gorm.DB) error {
+			for i := range batch {
+				if err := LinkTypeTag(tx, &batch[i]); err != nil {
+					return err
+				}
+			}
+			tagged += len(batch)
+			return nil
+		})
+	}).Error
+	if err != nil {
+		return err
+	}
+	if tagged > 0 {
+		log.Printf("✅ tagged %d existing books from their type", tagged)
+	}
+	return nil
+}
+
+// LinkTypeTag tags b with its free-text Type, creating the tag when no
+// tag with the same slug exists yet.
+func LinkTypeTag(tx *gorm.DB, b *models.Book) error {
+	slug := models.TagSlug(b.Type)
+	if slug == "" {
+		return nil
+	}
+
+	created := models.Tag{Name: strings.TrimSpace(b.Type)}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&created).Error; err != nil {
+		return err
+	}
+	var tag models.Tag // fresh struct – First would otherwise filter on created.ID
+	if err := tx.First(&tag, "slug = ?", slug).Error; err != nil {
+		return err
+	}
+
+	link := models.BookTag{BookID: b.ID, TagID: tag.ID}
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&link).Error
+}
+
+// SyncTypeTag moves b from the tag of its old Type to the tag of b.Type.
+// Other tags are kept.
+func SyncTypeTag(tx *gorm.DB, oldType string, b *models.Book) error {
+	old := models.TagSlug(oldType)
+	if old == models.TagSlug(b.Type) {
+		return nil
+	}
+	if old != "" {
+		err := tx.Where("book_id = ?", b.ID).
+			Where("tag_id IN (?)", tx.Model(&models.Tag{}).Select("id").Where("slug = ?", old)).
+			Delete(&models.BookTag{}).Error
+		if err != nil {
+			return err
+		}
+	}
+	return LinkTypeTag(tx, b)
+}
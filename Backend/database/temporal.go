@@ -0,0 +1,132 @@
+package database
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/hasan-kayan/TaskGo/models"
+)
+
+/*───────────────────────────────────────────────────────────────*
+|             Temporal book history (as_of queries)             |
+*───────────────────────────────────────────────────────────────*/
+
+// book_history holds every state a book row has been in, each valid
+// over [valid_from, valid_to); the current state has valid_to NULL.
+// Its book columns mirror models.Book – new Book fields are added here
+// on the next migration – so an as_of query can run the usual /books
+// filters and sorts against it. The revision callbacks append to it in
+// the same transaction as the write.
+const bookHistoryDDL = `CREATE TABLE IF NOT EXISTS book_history (
+	history_id INTEGER PRIMARY KEY,
+	valid_from datetime NOT NULL,
+	valid_to   datetime
+)`
+
+var bookHistoryIndexes = []string{
+	`CREATE INDEX IF NOT EXISTS idx_book_history_id_from ON book_history(id, valid_from)`,
+	`CREATE INDEX IF NOT EXISTS idx_book_history_valid ON book_history(valid_from, valid_to)`,
+}
+
+func setupBookHistory(db *gorm.DB) error {
+	fresh := !db.Migrator().HasTable("book_history")
+	if err := db.Exec(bookHistoryDDL).Error; err != nil {
+		return err
+	}
+
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(&models.Book{}); err != nil {
+		return err
+	}
+	// exact names – sqlite's HasColumn would find "id" in "history_id"
+	existing, err := db.Migrator().ColumnTypes("book_history")
+	if err != nil {
+		return err
+	}
+	have := map[string]bool{}
+	for _, col := range existing {
+		have[col.Name()] = true
+	}
+	for _, f := range stmt.Schema.Fields {
+		if f.DBName == "" || have[f.DBName] {
+			continue
+		}
+		ddl := "ALTER TABLE book_history ADD COLUMN ? " + db.Dialector.DataTypeOf(f)
+		if err := db.Exec(ddl, clause.Column{Name: f.DBName}).Error; err != nil {
+			return err
+		}
+	}
+	for _, ddl := range bookHistoryIndexes {
+		if err := db.Exec(ddl).Error; err != nil {
+			return err
+		}
+	}
+
+	if fresh {
+		return seedBookHistory(db)
+	}
+	return nil
+}
+
+// seedBookHistory starts the history of books stored before it existed:
+// the live state from the last update on, and for trashed books the
+// trashed state from the moment of deletion.
+func seedBookHistory(db *gorm.DB) error {
+	cols := bookColumns(db)
+	live := strings.Replace(cols, db.Statement.Quote("deleted_at"), "NULL", 1)
+	err := db.Exec("INSERT INTO book_history (" + cols + ", valid_from, valid_to) SELECT " +
+		live + ", updated_at, deleted_at FROM books").Error
+	if err != nil {
+		return err
+	}
+	res := db.Exec("INSERT INTO book_history (" + cols + ", valid_from) SELECT " +
+		cols + ", deleted_at FROM books WHERE deleted_at IS NOT NULL")
+	if res.Error == nil {
+		var n int64
+		db.Table("book_history").Count(&n)
+		if n > 0 {
+			log.Printf("✅ seeded book history with %d row(s)", n)
+		}
+	}
+	return res.Error
+}
+
+// recordHistory closes the open history rows of the given books and
+// appends their current state. Purged books only get closed.
+func recordHistory(db *gorm.DB, ids []uuid.UUID, at time.Time) error {
+	tx := db.Session(&gorm.Session{NewDB: true})
+	err := tx.Exec("UPDATE book_history SET valid_to = ? WHERE id IN ? AND valid_to IS NULL", at, ids).Error
+	if err != nil {
+		return err
+	}
+	cols := bookColumns(db)
+	return tx.Exec("INSERT INTO book_history ("+cols+", valid_from) SELECT "+cols+", ? FROM books WHERE id IN ?",
+		at, ids).Error
+}
+
+// BooksAsOf returns db reading books as they were at t: a query on the
+// result sees a "books" table holding each book's state at that instant.
+// Books that were in the trash at t are hidden by the usual soft-delete
+// scope; Unscoped() shows them.
+func BooksAsOf(db *gorm.DB, t time.Time) *gorm.DB {
+	snapshot := db.Session(&gorm.Session{NewDB: true}).
+		Table("book_history").
+		Select(bookColumns(db)).
+		Where("valid_from <= ? AND (valid_to IS NULL OR valid_to > ?)", t, t)
+	return db.Table("(?) AS books", snapshot)
+}
+
+func bookColumns(db *gorm.DB) string {
+	stmt := &gorm.Statement{DB: db}
+	_ = stmt.Parse(&models.Book{})
+	cols := make([]string, 0, len(stmt.Schema.DBNames))
+	for _, name := range stmt.Schema.DBNames {
+		cols = append(cols, stmt.Quote(name))
+	}
+	return strings.Join(cols, ", ")
+}
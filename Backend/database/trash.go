@@ -0,0 +1,120 @@
+package database
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/hasan-kayan/TaskGo/models"
+)
+
+/*───────────────────────────────────────────────────────────────*
+|      Trash: soft delete, restore, and purge with dependents   |
+*───────────────────────────────────────────────────────────────*/
+
+// TrashBook soft-deletes book, guarded by book.Version, and bumps the
+// version so cached ETags go stale. trashed is false when the book
+// changed since it was read.
+func TrashBook(db *gorm.DB, book *models.Book) (trashed bool, err error) {
+	return setDeletedAt(db.Session(&gorm.Session{NewDB: true}), book, gorm.DeletedAt{Time: db.NowFunc(), Valid: true})
+}
+
+// RestoreBook takes book out of the trash like TrashBook put it in.
+func RestoreBook(db *gorm.DB, book *models.Book) (restored bool, err error) {
+	return setDeletedAt(db.Session(&gorm.Session{NewDB: true}).Unscoped(), book, gorm.DeletedAt{})
+}
+
+func setDeletedAt(db *gorm.DB, book *models.Book, deletedAt gorm.DeletedAt) (bool, error) {
+	res := db.Model(book).
+		Where("version = ?", book.Version).
+		UpdateColumns(map[string]any{"deleted_at": deletedAt, "version": gorm.Expr("version + 1")})
+	if res.Error != nil || res.RowsAffected == 0 {
+		return false, res.Error
+	}
+	book.DeletedAt = deletedAt
+	book.Version++
+	book.ETag = book.EntityTag()
+	return true, nil
+}
+
+// ErrBookInCirculation refuses to purge a book with loans or fines:
+// they are the library's circulation record and outlive the catalogue.
+var ErrBookInCirculation = errors.New("book has loans or fines on record")
+
+// bookDependents are the tables whose rows only exist for their book.
+// Revisions and book_history are not among them: history and as_of
+// queries keep answering for purged books.
+var bookDependents = []any{
+	&models.BookAuthor{},
+	&models.BookTag{},
+	&models.Review{},
+	&models.BookRating{},
+	&models.Hold{},
+	&models.Copy{},
+}
+
+// PurgeBook permanently deletes book, trashed or not, together with its
+// credits, tags, reviews, rating totals, holds, copies and reading-list
+// items, in one transaction. The delete is guarded by book.Version:
+// purged is false when the book changed since it was read. Books with
+// loans or fines are refused with ErrBookInCirculation.
+func PurgeBook(db *gorm.DB, book *models.Book) (purged bool, err error) {
+	// a fresh statement – the caller's db may carry the conditions it
+	// found the book with; the context (and with it the actor) is kept
+	db = db.Session(&gorm.Session{NewDB: true})
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var records int64
+		err := tx.Raw(`SELECT
+			(SELECT COUNT(*) FROM loans WHERE book_id = ?) +
+			(SELECT COUNT(*) FROM fines WHERE book_id = ?)`, book.ID, book.ID).Scan(&records).Error
+		if err != nil {
+			return err
+		}
+		if records > 0 {
+			return ErrBookInCirculation
+		}
+
+		res := tx.Unscoped().Where("version = ?", book.Version).Delete(book)
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		purged = true
+
+		for _, dep := range bookDependents {
+			if err := tx.Where("book_id = ?", book.ID).Delete(dep).Error; err != nil {
+				return err
+			}
+		}
+		return removeFromReadingLists(tx, book)
+	})
+	return purged, err
+}
+
+// removeFromReadingLists drops the book's list items and closes the
+// gaps they leave, so positions stay 1..n.
+func removeFromReadingLists(tx *gorm.DB, book *models.Book) error {
+	// lock the lists first, as the list handlers do, so the positions
+	// read below stay current
+	err := tx.Model(&models.ReadingList{}).
+		Where("id IN (?)", tx.Model(&models.ReadingListItem{}).Select("list_id").Where("book_id = ?", book.ID)).
+		Update("updated_at", tx.NowFunc()).Error
+	if err != nil {
+		return err
+	}
+	var items []models.ReadingListItem
+	if err := tx.Where("book_id = ?", book.ID).Find(&items).Error; err != nil {
+		return err
+	}
+	for _, item := range items {
+		if err := tx.Delete(&item).Error; err != nil {
+			return err
+		}
+		err := tx.Model(&models.ReadingListItem{}).
+			Where("list_id = ? AND position > ?", item.ListID, item.Position).
+			Update("position", gorm.Expr("position - 1")).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package enrichment
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// maxCacheEntries bounds each provider's cache; expired entries are
+// swept first, and the whole cache is dropped if that is not enough.
+const maxCacheEntries = 10000
+
+type cacheEntry struct {
+	meta    *Metadata // nil for a cached ErrNotFound
+	expires time.Time
+}
+
+type cachedProvider struct {
+	Provider
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+// Cached remembers p's answers – including "not found" – for ttl.
+// Failures and timeouts are not cached, so the next lookup retries.
+func Cached(p Provider, ttl time.Duration) Provider {
+	if ttl <= 0 {
+		return p
+	}
+	return &cachedProvider{Provider: p, ttl: ttl, entries: map[string]cacheEntry{}}
+}
+
+func (c *cachedProvider) Lookup(ctx context.Context, isbn13 string) (*Metadata, error) {
+	now := time.Now()
+	c.mu.Lock()
+	e, ok := c.entries[isbn13]
+	c.mu.Unlock()
+	if ok && now.Before(e.expires) {
+		if e.meta == nil {
+			return nil, ErrNotFound
+		}
+		m := *e.meta
+		return &m, nil
+	}
+
+	m, err := c.Provider.Lookup(ctx, isbn13)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= maxCacheEntries {
+		for k, e := range c.entries {
+			if !now.Before(e.expires) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= maxCacheEntries {
+			c.entries = map[string]cacheEntry{}
+		}
+	}
+	entry := cacheEntry{expires: now.Add(c.ttl)}
+	if m != nil {
+		stored := *m
+		entry.meta = &stored
+	}
+	c.entries[isbn13] = entry
+	return m, err
+}
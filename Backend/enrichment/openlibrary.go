@@ -0,0 +1,138 @@
+package enrichment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	DefaultOpenLibraryURL   = "https://openlibrary.org"
+	DefaultOpenLibraryCover = "https://covers.openlibrary.org"
+
+	// responses larger than this are not metadata we want
+	maxOpenLibraryBody = 1 << 20
+)
+
+// OpenLibrary reads the Open Library edition API (GET /isbn/{isbn}.json)
+// from BaseURL, or from any server that speaks it. When the edition has
+// no description, the work it belongs to is asked as well.
+type OpenLibrary struct {
+	BaseURL   string
+	CoversURL string
+	Client    *http.Client
+}
+
+// NewOpenLibrary talks to baseURL with the public cover server.
+func NewOpenLibrary(baseURL string) *OpenLibrary {
+	return &OpenLibrary{
+		BaseURL:   strings.TrimRight(baseURL, "/"),
+		CoversURL: DefaultOpenLibraryCover,
+		Client:    http.DefaultClient,
+	}
+}
+
+func (o *OpenLibrary) Name() string { return "openlibrary" }
+
+// olEdition is the part of an edition record we read.
+type olEdition struct {
+	Title         string   `json:"title"`
+	Publishers    []string `json:"publishers"`
+	NumberOfPages int      `json:"number_of_pages"`
+	PublishDate   string   `json:"publish_date"`
+	Description   olText   `json:"description"`
+	Covers        []int    `json:"covers"`
+	Works         []struct {
+		Key string `json:"key"`
+	} `json:"works"`
+}
+
+// olText is a description, sent either as a plain string or as
+// {"type": "/type/text", "value": "…"}.
+type olText string
+
+func (t *olText) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*t = olText(s)
+		return nil
+	}
+	var typed struct {
+		Value string `json:"value"`
+	}
+	if err := json.Unmarshal(data, &typed); err != nil {
+		return err
+	}
+	*t = olText(typed.Value)
+	return nil
+}
+
+var yearPattern = regexp.MustCompile(`\b\d{4}\b`)
+
+func (o *OpenLibrary) Lookup(ctx context.Context, isbn13 string) (*Metadata, error) {
+	var ed olEdition
+	if err := o.get(ctx, "/isbn/"+isbn13+".json", &ed); err != nil {
+		return nil, err
+	}
+
+	m := &Metadata{
+		Title:       ed.Title,
+		Description: strings.TrimSpace(string(ed.Description)),
+		Pages:       ed.NumberOfPages,
+		Source:      o.Name(),
+	}
+	if len(ed.Publishers) > 0 {
+		m.Publisher = ed.Publishers[0]
+	}
+	if y := yearPattern.FindString(ed.PublishDate); y != "" {
+		m.Year, _ = strconv.Atoi(y)
+	}
+	if len(ed.Covers) > 0 && ed.Covers[0] > 0 {
+		m.CoverImageURL = fmt.Sprintf("%s/b/id/%d-L.jpg", strings.TrimRight(o.CoversURL, "/"), ed.Covers[0])
+	}
+
+	// descriptions usually live on the work, not the edition
+	if m.Description == "" && len(ed.Works) > 0 && strings.HasPrefix(ed.Works[0].Key, "/works/") {
+		var work struct {
+			Description olText `json:"description"`
+		}
+		if err := o.get(ctx, ed.Works[0].Key+".json", &work); err == nil {
+			m.Description = strings.TrimSpace(string(work.Description))
+		} else if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+	return m, nil
+}
+
+// get decodes the JSON at path into v; 404 is ErrNotFound.
+func (o *OpenLibrary) get(ctx context.Context, path string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, o.BaseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	client := o.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("GET %s: %s", path, resp.Status)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, maxOpenLibraryBody)).Decode(v)
+}
@@ -0,0 +1,191 @@
+package enrichment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hasan-kayan/TaskGo/config"
+)
+
+/*───────────────────────────────────────────────────────────────*
+|                Book metadata providers by ISBN                |
+*───────────────────────────────────────────────────────────────*/
+
+// ErrNotFound means a provider has no record for the ISBN.
+var ErrNotFound = errors.New("no metadata for this ISBN")
+
+// Metadata is what a provider knows about an edition. Zero values are
+// unknown, never "clear this field".
+type Metadata struct {
+	Title         string `json:"title,omitempty"`
+	Description   string `json:"description,omitempty"`
+	Publisher     string `json:"publisher,omitempty"`
+	Pages         int    `json:"pages,omitempty"`
+	Year          int    `json:"year,omitempty"`
+	CoverImageURL string `json:"cover_image_url,omitempty"`
+
+	// Source names the provider(s) the values came from.
+	Source string `json:"source,omitempty"`
+}
+
+// Provider looks an edition up by its canonical ISBN-13. It returns
+// ErrNotFound (possibly wrapped) when it has nothing for the ISBN.
+type Provider interface {
+	Name() string
+	Lookup(ctx context.Context, isbn13 string) (*Metadata, error)
+}
+
+/* ───────────── per-provider timeout ───────────── */
+
+type timeoutProvider struct {
+	Provider
+	timeout time.Duration
+}
+
+// WithTimeout bounds every lookup of p to d, on top of any deadline the
+// caller's context already has.
+func WithTimeout(p Provider, d time.Duration) Provider {
+	if d <= 0 {
+		return p
+	}
+	return &timeoutProvider{Provider: p, timeout: d}
+}
+
+func (t *timeoutProvider) Lookup(ctx context.Context, isbn13 string) (*Metadata, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+	return t.Provider.Lookup(ctx, isbn13)
+}
+
+/* ───────────── chain ───────────── */
+
+type chain []Provider
+
+// Chain asks every provider in order and fills each field from the
+// first one that knows it. A provider that fails or times out is
+// skipped as long as another one answers; ErrNotFound is returned only
+// when none has a record.
+func Chain(providers ...Provider) Provider {
+	if len(providers) == 1 {
+		return providers[0]
+	}
+	return chain(providers)
+}
+
+func (c chain) Name() string {
+	names := make([]string, len(c))
+	for i, p := range c {
+		names[i] = p.Name()
+	}
+	return strings.Join(names, ",")
+}
+
+func (c chain) Lookup(ctx context.Context, isbn13 string) (*Metadata, error) {
+	var (
+		out     *Metadata
+		sources []string
+		failure error
+	)
+	for _, p := range c {
+		m, err := p.Lookup(ctx, isbn13)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			failure = errors.Join(failure, fmt.Errorf("%s: %w", p.Name(), err))
+			continue
+		}
+		if out == nil {
+			out = &Metadata{}
+		}
+		if fill(out, m) {
+			sources = append(sources, m.Source)
+		}
+	}
+	switch {
+	case out != nil:
+		out.Source = strings.Join(sources, ",")
+		return out, nil
+	case failure != nil:
+		return nil, failure
+	}
+	return nil, ErrNotFound
+}
+
+// fill copies the fields dst lacks from src and reports whether any
+// were taken.
+func fill(dst, src *Metadata) bool {
+	took := false
+	str := func(d *string, s string) {
+		if *d == "" && s != "" {
+			*d, took = s, true
+		}
+	}
+	num := func(d *int, s int) {
+		if *d == 0 && s > 0 {
+			*d, took = s, true
+		}
+	}
+	str(&dst.Title, src.Title)
+	str(&dst.Description, src.Description)
+	str(&dst.Publisher, src.Publisher)
+	num(&dst.Pages, src.Pages)
+	num(&dst.Year, src.Year)
+	str(&dst.CoverImageURL, src.CoverImageURL)
+	return took
+}
+
+/* ───────────── configuration ───────────── */
+
+// FromEnv builds the provider chain named by ENRICH_PROVIDERS (default
+// "openlibrary"), in that order:
+//
+//	openlibrary  ENRICH_OPENLIBRARY_URL (https://openlibrary.org),
+//	             ENRICH_OPENLIBRARY_COVERS_URL (https://covers.openlibrary.org),
+//	             ENRICH_OPENLIBRARY_TIMEOUT_MS (5000)
+//	fixture      ENRICH_FIXTURE_FILE, a JSON object of ISBN-13 → Metadata
+//
+// Each provider gets its own timeout and a cache of
+// ENRICH_CACHE_MINUTES (default 1440, 0 = no cache). An unknown
+// provider, a fixture that cannot be loaded, or a list naming no
+// provider at all is an error.
+func FromEnv() (Provider, error) {
+	ttl := time.Duration(config.Int("ENRICH_CACHE_MINUTES", 1440, 0)) * time.Minute
+
+	var providers []Provider
+	for _, name := range strings.Split(envString("ENRICH_PROVIDERS", "openlibrary"), ",") {
+		var p Provider
+		switch name = strings.TrimSpace(name); name {
+		case "openlibrary":
+			ol := NewOpenLibrary(envString("ENRICH_OPENLIBRARY_URL", DefaultOpenLibraryURL))
+			ol.CoversURL = envString("ENRICH_OPENLIBRARY_COVERS_URL", DefaultOpenLibraryCover)
+			p = WithTimeout(ol, time.Duration(config.Int("ENRICH_OPENLIBRARY_TIMEOUT_MS", 5000, 0))*time.Millisecond)
+		case "fixture":
+			f, err := LoadFixture(os.Getenv("ENRICH_FIXTURE_FILE"))
+			if err != nil {
+				return nil, fmt.Errorf("enrichment fixture: %w", err)
+			}
+			p = f
+		case "":
+			continue
+		default:
+			return nil, fmt.Errorf("unknown enrichment provider %q", name)
+		}
+		providers = append(providers, Cached(p, ttl))
+	}
+	if len(providers) == 0 {
+		return nil, errors.New("ENRICH_PROVIDERS names no provider")
+	}
+	return Chain(providers...), nil
+}
+
+func envString(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
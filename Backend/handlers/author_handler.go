@@ -0,0 +1,360 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/hasan-kayan/TaskGo/utils"
+)
+
+// authorBookCount counts the live books an author is credited on.
+const authorBookCount = `(SELECT COUNT(DISTINCT ba.book_id) FROM book_authors ba
+	JOIN books b ON b.id = ba.book_id
+	WHERE ba.author_id = authors.id AND b.deleted_at IS NULL) AS book_count`
+
+var contributorRoles = map[string]bool{
+	models.RoleAuthor:      true,
+	models.RoleEditor:      true,
+	models.RoleTranslator:  true,
+	models.RoleIllustrator: true,
+}
+
+/* ────────────────────────────────────────────────────────── *
+   GET /authors  ─ list, ?name= filter, alphabetical
+ * ────────────────────────────────────────────────────────── */
+
+func GetAuthors(c *gin.Context) {
+	limit, offset, err := parseLimitOffset(c)
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	db := database.DB.Model(&models.Author{})
+	if q := c.Query("name"); q != "" {
+		db = db.Where("LOWER(name) LIKE ?", "%"+strings.ToLower(q)+"%")
+	}
+	db = db.Session(&gorm.Session{})
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not list authors")
+		return
+	}
+
+	authors := []models.Author{}
+	err = db.Select("authors.*, " + authorBookCount).
+		Order("LOWER(name)").Order("id").
+		Limit(limit).Offset(offset).
+		Find(&authors).Error
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not list authors")
+		return
+	}
+
+	meta := &PageMeta{Total: total, Limit: limit, Offset: offset, Sort: "name"}
+	utils.JSONSuccessMeta(c, http.StatusOK, authors, meta)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   GET /authors/:id
+ * ────────────────────────────────────────────────────────── */
+
+func GetAuthor(c *gin.Context) {
+	author, ok := loadAuthor(c)
+	if !ok {
+		return
+	}
+	utils.JSONSuccess(c, http.StatusOK, author)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   POST /authors  ─ create
+ * ────────────────────────────────────────────────────────── */
+
+func CreateAuthor(c *gin.Context) {
+	var payload models.Author
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	payload.ID = uuid.New()
+
+	if err := utils.ValidateAuthor(&payload); err != nil {
+		utils.JSONFieldErrors(c, http.StatusUnprocessableEntity, "validation failed", utils.FieldErrors(err))
+		return
+	}
+	if err := database.DB.Create(&payload).Error; err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not create author")
+		return
+	}
+	utils.JSONSuccess(c, http.StatusCreated, payload)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   PUT /authors/:id  ─ full replacement of name / bio / birth_year
+ * ────────────────────────────────────────────────────────── */
+
+func UpdateAuthor(c *gin.Context) {
+	current, ok := loadAuthor(c)
+	if !ok {
+		return
+	}
+
+	var next models.Author
+	if err := c.ShouldBindJSON(&next); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	next.ID, next.CreatedAt = current.ID, current.CreatedAt
+	next.Name = strings.TrimSpace(next.Name)
+
+	if err := utils.ValidateAuthor(&next); err != nil {
+		utils.JSONFieldErrors(c, http.StatusUnprocessableEntity, "validation failed", utils.FieldErrors(err))
+		return
+	}
+	err := database.DB.Model(current).
+		Select("name", "bio", "birth_year").
+		Updates(&next).Error
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not update author")
+		return
+	}
+
+	updated, _ := findAuthor(database.DB, current.ID)
+	utils.JSONSuccess(c, http.StatusOK, updated)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   DELETE /authors/:id  ─ only once no live book credits them
+ * ────────────────────────────────────────────────────────── */
+
+func DeleteAuthor(c *gin.Context) {
+	author, ok := loadAuthor(c)
+	if !ok {
+		return
+	}
+
+	// BookCount only counts live books
+	if author.BookCount > 0 {
+		utils.JSONError(c, http.StatusConflict,
+			fmt.Sprintf("author is credited on %d book(s); re-credit them first", author.BookCount))
+		return
+	}
+
+	// credits on trashed books go with the author
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("author_id = ?", author.ID).Delete(&models.BookAuthor{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(author).Error
+	})
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not delete author")
+		return
+	}
+	utils.JSONSuccess(c, http.StatusOK, models.MessageResponse{Message: "author deleted"})
+}
+
+/* ────────────────────────────────────────────────────────── *
+   GET /authors/:id/books  ─ the author's books, ?role= filter
+ * ────────────────────────────────────────────────────────── */
+
+// GetAuthorBooks pages like GET /books and accepts the same filters.
+func GetAuthorBooks(c *gin.Context) {
+	author, ok := loadAuthor(c)
+	if !ok {
+		return
+	}
+	page, err := parsePageRequest(c, defaultBookSort)
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	credited := database.DB.Model(&models.BookAuthor{}).Select("book_id").Where("author_id = ?", author.ID)
+	if role := c.Query("role"); role != "" {
+		if !contributorRoles[role] {
+			utils.JSONError(c, http.StatusBadRequest, "role must be author, editor, translator or illustrator")
+			return
+		}
+		credited = credited.Where("role = ?", role)
+	}
+
+	db := applyBookFilters(database.DB, c).Where("books.id IN (?)", credited)
+	books, meta, err := fetchBookPage(db, page)
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not list books")
+		return
+	}
+	utils.JSONSuccessMeta(c, http.StatusOK, books, meta)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   GET|PUT /books/:id/authors  ─ a book's credits
+ * ────────────────────────────────────────────────────────── */
+
+// CreditInput is one entry of the PUT /books/:id/authors body; list
+// order becomes the credit order.
+type CreditInput struct {
+	AuthorID uuid.UUID `json:"author_id"`
+	Role     string    `json:"role"`
+}
+
+func GetBookAuthors(c *gin.Context) {
+	book, ok := loadBookParam(c)
+	if !ok {
+		return
+	}
+
+	credits, err := bookCredits(database.DB, book.ID)
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not load authors")
+		return
+	}
+	utils.JSONSuccess(c, http.StatusOK, credits)
+}
+
+// SetBookAuthors replaces every credit of the book. The free-text
+// byline (Book.Author) is left as it is.
+func SetBookAuthors(c *gin.Context) {
+	book, ok := loadBookParam(c)
+	if !ok {
+		return
+	}
+
+	var input []CreditInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "body must be a JSON array of {author_id, role}")
+		return
+	}
+
+	fields := map[string]string{}
+	seen := map[CreditInput]bool{}
+	for i, in := range input {
+		if in.Role == "" {
+			input[i].Role, in.Role = models.RoleAuthor, models.RoleAuthor
+		}
+		switch {
+		case !contributorRoles[in.Role]:
+			fields[fmt.Sprintf("[%d].role", i)] = "must be author, editor, translator or illustrator"
+		case seen[in]:
+			fields[fmt.Sprintf("[%d]", i)] = "duplicate credit"
+		}
+		seen[in] = true
+	}
+	if len(fields) == 0 {
+		fields = missingAuthors(database.DB, input)
+	}
+	if len(fields) > 0 {
+		utils.JSONFieldErrors(c, http.StatusUnprocessableEntity, "validation failed", fields)
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("book_id = ?", book.ID).Delete(&models.BookAuthor{}).Error; err != nil {
+			return err
+		}
+		for i, in := range input {
+			link := models.BookAuthor{BookID: book.ID, AuthorID: in.AuthorID, Role: in.Role, Position: i}
+			if err := tx.Create(&link).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not save authors")
+		return
+	}
+
+	credits, _ := bookCredits(database.DB, book.ID)
+	utils.JSONSuccess(c, http.StatusOK, credits)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   helpers
+ * ────────────────────────────────────────────────────────── */
+
+// loadAuthor parses :id and fetches the author, writing 400/404 itself.
+func loadAuthor(c *gin.Context) (*models.Author, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "invalid UUID")
+		return nil, false
+	}
+	author, err := findAuthor(database.DB, id)
+	if err != nil {
+		utils.JSONError(c, http.StatusNotFound, "author not found")
+		return nil, false
+	}
+	return author, true
+}
+
+func findAuthor(db *gorm.DB, id uuid.UUID) (*models.Author, error) {
+	var author models.Author
+	err := db.Model(&models.Author{}).
+		Select("authors.*, "+authorBookCount).
+		First(&author, "authors.id = ?", id).Error
+	return &author, err
+}
+
+// loadBookParam parses :id and fetches the live book, writing 400/404 itself.
+func loadBookParam(c *gin.Context) (*models.Book, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "invalid UUID")
+		return nil, false
+	}
+	var book models.Book
+	if err := database.DB.First(&book, "id = ?", id).Error; err != nil {
+		utils.JSONError(c, http.StatusNotFound, "book not found")
+		return nil, false
+	}
+	return &book, true
+}
+
+func bookCredits(db *gorm.DB, bookID uuid.UUID) ([]models.BookAuthor, error) {
+	credits := []models.BookAuthor{}
+	err := db.Preload("Author").
+		Where("book_id = ?", bookID).
+		Order("position").Order("role").
+		Find(&credits).Error
+	return credits, err
+}
+
+// missingAuthors reports credits that point at unknown authors.
+func missingAuthors(db *gorm.DB, input []CreditInput) map[string]string {
+	fields := map[string]string{}
+	if len(input) == 0 {
+		return fields
+	}
+
+	ids := make([]uuid.UUID, len(input))
+	for i, in := range input {
+		ids[i] = in.AuthorID
+	}
+	var found []uuid.UUID
+	if err := db.Model(&models.Author{}).Where("id IN ?", ids).Pluck("id", &found).Error; err != nil {
+		fields["_"] = err.Error()
+		return fields
+	}
+
+	known := make(map[uuid.UUID]bool, len(found))
+	for _, id := range found {
+		known[id] = true
+	}
+	for i, in := range input {
+		if !known[in.AuthorID] {
+			fields[fmt.Sprintf("[%d].author_id", i)] = "author not found"
+		}
+	}
+	return fields
+}
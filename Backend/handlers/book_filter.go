@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/hasan-kayan/TaskGo/utils"
+)
+
+/*───────────────────────────────────────────────────────────────*
+|            ?filter= expressions over Book columns             |
+*───────────────────────────────────────────────────────────────*/
+
+type filterKind int
+
+const (
+	filterText filterKind = iota
+	filterInt
+	filterFloat
+	filterTime
+	filterUUID
+)
+
+// filterField is a Book column clients may test in ?filter=. Only
+// nullable fields accept `= null` / `!= null`.
+type filterField struct {
+	column   string
+	kind     filterKind
+	nullable bool
+}
+
+var bookFilterFields = map[string]filterField{
+	"title":        {"title", filterText, false},
+	"author":       {"author", filterText, false},
+	"description":  {"description", filterText, false},
+	"publisher":    {"publisher", filterText, false},
+	"type":         {"type", filterText, false},
+	"isbn":         {"isbn", filterText, false},
+	"isbn10":       {"isbn10", filterText, false},
+	"year":         {"year", filterInt, false},
+	"pages":        {"pages", filterInt, false},
+	"volume":       {"volume", filterFloat, true},
+	"rating":       {bookAverageRating, filterFloat, false},
+	"created_at":   {"created_at", filterTime, false},
+	"updated_at":   {"updated_at", filterTime, false},
+	"publisher_id": {"publisher_id", filterUUID, true},
+	"series_id":    {"series_id", filterUUID, true},
+}
+
+// bookFilter is a compiled ?filter= expression: a WHERE fragment whose
+// values are all bound parameters. Column names only ever come from
+// bookFilterFields.
+type bookFilter struct {
+	sql  string
+	args []any
+}
+
+// parseBookFilter parses and checks src; errors are *utils.FilterError
+// carrying the position of the offending token.
+func parseBookFilter(src string) (*bookFilter, error) {
+	node, err := utils.ParseFilter(src)
+	if err != nil {
+		return nil, err
+	}
+	f := &bookFilter{}
+	if f.sql, err = f.compile(node); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *bookFilter) compile(node utils.FilterNode) (string, error) {
+	switch n := node.(type) {
+	case *utils.FilterLogic:
+		left, err := f.compile(n.Left)
+		if err != nil {
+			return "", err
+		}
+		right, err := f.compile(n.Right)
+		if err != nil {
+			return "", err
+		}
+		return "(" + left + " " + n.Op + " " + right + ")", nil
+	case *utils.FilterNot:
+		inner, err := f.compile(n.Expr)
+		if err != nil {
+			return "", err
+		}
+		return "NOT (" + inner + ")", nil
+	case *utils.FilterCompare:
+		return f.compare(n)
+	}
+	return "", fmt.Errorf("unexpected filter node %T", node)
+}
+
+func (f *bookFilter) compare(n *utils.FilterCompare) (string, error) {
+	name := strings.ToLower(n.Field)
+	field, ok := bookFilterFields[name]
+	if !ok {
+		return "", &utils.FilterError{Pos: n.FieldPos, Msg: fmt.Sprintf("unknown field %q", n.Field)}
+	}
+
+	if n.Value.Kind == utils.FilterNull {
+		if !field.nullable {
+			return "", &utils.FilterError{Pos: n.Value.Pos, Msg: name + " is never null"}
+		}
+		switch n.Op {
+		case "=":
+			return field.column + " IS NULL", nil
+		case "!=":
+			return field.column + " IS NOT NULL", nil
+		}
+		return "", &utils.FilterError{Pos: n.OpPos, Msg: "null can only be compared with = or !="}
+	}
+
+	switch {
+	case n.Op == "~" || n.Op == "!~":
+		if field.kind != filterText {
+			return "", &utils.FilterError{Pos: n.OpPos, Msg: n.Op + " only applies to text fields"}
+		}
+		// case-insensitive substring; % and _ in the value match literally
+		f.args = append(f.args, "%"+escapeLike(strings.ToLower(n.Value.Text))+"%")
+		not := ""
+		if n.Op == "!~" {
+			not = "NOT "
+		}
+		return "LOWER(" + field.column + ") " + not + `LIKE ? ESCAPE '\'`, nil
+	case field.kind == filterUUID && n.Op != "=" && n.Op != "!=":
+		return "", &utils.FilterError{Pos: n.OpPos, Msg: name + " can only be compared with = or !="}
+	}
+
+	v, err := field.parse(n.Value)
+	if err != nil {
+		return "", &utils.FilterError{Pos: n.Value.Pos, Msg: name + " " + err.Error()}
+	}
+	f.args = append(f.args, v)
+	return field.column + " " + n.Op + " ?", nil
+}
+
+// parse turns a literal into an argument of the column's type.
+func (field filterField) parse(v utils.FilterValue) (any, error) {
+	switch field.kind {
+	case filterInt:
+		if n, err := strconv.Atoi(v.Text); err == nil && v.Kind == utils.FilterNumber {
+			return n, nil
+		}
+		return nil, fmt.Errorf("expects a whole number, got %q", v.Text)
+	case filterFloat:
+		if n, err := strconv.ParseFloat(v.Text, 64); err == nil && v.Kind == utils.FilterNumber {
+			return n, nil
+		}
+		return nil, fmt.Errorf("expects a number, got %q", v.Text)
+	case filterTime:
+		// stored timestamps are local time, as for as_of
+		if t, err := time.Parse(time.RFC3339Nano, v.Text); err == nil {
+			return t.Local(), nil
+		}
+		if t, err := time.ParseInLocation(time.DateOnly, v.Text, time.Local); err == nil {
+			return t, nil
+		}
+		return nil, fmt.Errorf("expects a date (YYYY-MM-DD) or RFC 3339 timestamp, got %q", v.Text)
+	case filterUUID:
+		id, err := uuid.Parse(v.Text)
+		if err != nil {
+			return nil, fmt.Errorf("expects a UUID, got %q", v.Text)
+		}
+		return id, nil
+	}
+	return v.Text, nil
+}
+
+// escapeLike makes s match itself inside a LIKE pattern with ESCAPE '\'.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
@@ -1,11 +1,13 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
 	"net/http"
-	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 
 	"github.com/hasan-kayan/TaskGo/database"
 	"github.com/hasan-kayan/TaskGo/models"
@@ -13,33 +15,41 @@ import (
 )
 
 /* ────────────────────────────────────────────────────────── *
-   GET /books  ─ list with optional filters
+   GET /books  ─ paginated list with optional filters
  * ────────────────────────────────────────────────────────── */
 
+// GetBooks supports limit/offset and cursor paging:
+//
+//	?limit=20&offset=40
+//	?limit=20&cursor=<meta.next_cursor>
+//	?sort=-year,title
+//	?as_of=2024-03-01T00:00:00Z   (the catalogue as it was then)
 func GetBooks(c *gin.Context) {
-	var books []models.Book
-	db := database.DB
-
-	// optional query filters
-	if q := c.Query("title"); q != "" {
-		db = db.Where("LOWER(title) LIKE ?", "%"+strings.ToLower(q)+"%")
-	}
-	if q := c.Query("author"); q != "" {
-		db = db.Where("LOWER(author) LIKE ?", "%"+strings.ToLower(q)+"%")
+	page, err := parsePageRequest(c, defaultBookSort)
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
 	}
-	if q := c.Query("year"); q != "" {
-		db = db.Where("year = ?", q)
+	source, err := bookSource(c)
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
 	}
-	if q := c.Query("type"); q != "" {
-		db = db.Where("type = ?", q)
+
+	books, meta, err := fetchBookPage(applyBookFilters(source, c), page)
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not list books")
+		return
 	}
 
-	db.Find(&books)
-	utils.JSONSuccess(c, http.StatusOK, books)
+	if notModified(c, listETag(books, meta)) {
+		return
+	}
+	utils.JSONSuccessMeta(c, http.StatusOK, books, meta)
 }
 
 /* ────────────────────────────────────────────────────────── *
-   GET /books/:id  ─ fetch by UUID
+   GET /books/:id  ─ fetch by UUID (?as_of= for a past state)
  * ────────────────────────────────────────────────────────── */
 
 func GetBook(c *gin.Context) {
@@ -48,13 +58,64 @@ func GetBook(c *gin.Context) {
 		utils.JSONError(c, http.StatusBadRequest, "invalid UUID")
 		return
 	}
+	source, err := bookSource(c)
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var book models.Book
+	if err := source.First(&book, "books.id = ?", id).Error; err != nil {
+		utils.JSONError(c, http.StatusNotFound, "book not found")
+		return
+	}
+
+	// the strong etag tracks the record only, so If-Match works with it;
+	// no-cache keeps caches from serving the live parts below unasked
+	c.Header("Cache-Control", "no-cache")
+	if notModified(c, book.ETag) {
+		return
+	}
+
+	// ratings are live totals, shown for past states too
+	ratings := []models.Book{book}
+	if err := withRatings(database.DB, ratings); err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not load rating")
+		return
+	}
+
+	// availability is live, so a past state is shown without it
+	detail := BookDetail{Book: ratings[0]}
+	if c.Query("as_of") == "" {
+		if detail.Availability, err = bookAvailability(database.DB, book.ID); err != nil {
+			utils.JSONError(c, http.StatusInternalServerError, "could not load availability")
+			return
+		}
+	}
+
+	utils.JSONSuccess(c, http.StatusOK, detail)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   GET /books/isbn/:isbn  ─ fetch by ISBN-10 or ISBN-13
+ * ────────────────────────────────────────────────────────── */
+
+func GetBookByISBN(c *gin.Context) {
+	isbn13, _, err := utils.NormaliseISBN(c.Param("isbn"))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "invalid ISBN")
+		return
+	}
 
 	var book models.Book
-	if err := database.DB.First(&book, "id = ?", id).Error; err != nil {
+	if err := database.DB.First(&book, "isbn = ?", isbn13).Error; err != nil {
 		utils.JSONError(c, http.StatusNotFound, "book not found")
 		return
 	}
 
+	if notModified(c, book.ETag) {
+		return
+	}
 	utils.JSONSuccess(c, http.StatusOK, book)
 }
 
@@ -72,20 +133,38 @@ func CreateBook(c *gin.Context) {
 	if payload.ID == uuid.Nil {
 		payload.ID = uuid.New()
 	}
+	payload.Rating = nil // live review totals, never taken from the client
 
 	if err := utils.ValidateBook(&payload); err != nil {
-		utils.JSONError(c, http.StatusUnprocessableEntity, err.Error())
+		utils.JSONFieldErrors(c, http.StatusUnprocessableEntity, "validation failed", utils.FieldErrors(err))
+		return
+	}
+	if fields := resolveBookRefs(database.DB, &payload); len(fields) > 0 {
+		utils.JSONFieldErrors(c, http.StatusUnprocessableEntity, "validation failed", fields)
 		return
 	}
 
-	database.DB.Create(&payload)
+	if err := actorDB(c).Create(&payload).Error; err != nil {
+		switch {
+		case database.IsISBNViolation(err):
+			utils.JSONError(c, http.StatusConflict, msgISBNTaken)
+		case database.IsUniqueViolation(err):
+			utils.JSONError(c, http.StatusConflict, msgIDTaken)
+		default:
+			utils.JSONError(c, http.StatusInternalServerError, "could not create book")
+		}
+		return
+	}
+	c.Header("ETag", payload.ETag)
 	utils.JSONSuccess(c, http.StatusCreated, payload)
 }
 
 /* ────────────────────────────────────────────────────────── *
-   PUT /books/:id  ─ partial update
+   PUT /books/:id  ─ full replacement
  * ────────────────────────────────────────────────────────── */
 
+// UpdateBook replaces every writable field: anything omitted from the
+// body is reset to its zero value. Use PATCH for partial updates.
 func UpdateBook(c *gin.Context) {
 	bookID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
@@ -99,28 +178,102 @@ func UpdateBook(c *gin.Context) {
 		utils.JSONError(c, http.StatusNotFound, "book not found")
 		return
 	}
+	if ifMatchFailed(c, &current) {
+		return
+	}
 
-	// decode patch payload (all fields optional)
-	var patch models.Book
-	if err := c.ShouldBindJSON(&patch); err != nil {
+	// decode replacement – plain JSON decode, validation happens below
+	var next models.Book
+	if err := json.NewDecoder(c.Request.Body).Decode(&next); err != nil {
 		utils.JSONError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	// apply non-zero fields to the current struct
-	database.DB.Model(&current).Updates(patch)
+	saveBookReplacement(c, &current, &next)
+}
 
-	// validation after merge – required fields must still be set
-	if err := utils.ValidateBook(&current); err != nil {
-		utils.JSONError(c, http.StatusUnprocessableEntity, err.Error())
-		return
+// errVersionConflict means the row changed between being read and written.
+var errVersionConflict = errors.New("version conflict")
+
+// msgISBNTaken answers writes that hit the unique ISBN index.
+const msgISBNTaken = "a book with this ISBN already exists"
+
+// msgIDTaken answers creates whose client-supplied id is in use.
+const msgIDTaken = "a book with this id already exists"
+
+// replaceBook validates next and, only if it passes, writes it over
+// current – zero values included. Server-managed fields are kept and
+// the write only lands if nobody bumped the version in between. On
+// success next holds the stored row.
+func replaceBook(db *gorm.DB, current, next *models.Book) (map[string]string, error) {
+	next.ID = current.ID
+	next.CreatedAt = current.CreatedAt
+	next.DeletedAt = current.DeletedAt
+	next.Version = current.Version + 1
+
+	if err := utils.ValidateBook(next); err != nil {
+		return utils.FieldErrors(err), nil
+	}
+	if fields := resolveBookRefs(db, next); len(fields) > 0 {
+		return fields, nil
+	}
+
+	res := db.Model(current).
+		Where("version = ?", current.Version).
+		Select("*").
+		Omit(readOnlyBookColumns...).
+		Updates(next)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, errVersionConflict
 	}
 
-	utils.JSONSuccess(c, http.StatusOK, current)
+	var saved models.Book
+	if err := db.First(&saved, "id = ?", current.ID).Error; err != nil {
+		return nil, err
+	}
+	*next = saved
+	return nil, nil
+}
+
+// resolveBookRefs checks the records a book points at (publisher,
+// series) and returns field errors for any that are missing.
+func resolveBookRefs(db *gorm.DB, book *models.Book) map[string]string {
+	fields := map[string]string{}
+	for _, check := range []func(*gorm.DB, *models.Book) map[string]string{
+		resolveBookPublisher, resolveBookSeries,
+	} {
+		for k, v := range check(db, book) {
+			fields[k] = v
+		}
+	}
+	return fields
+}
+
+// saveBookReplacement runs replaceBook and renders the outcome.
+func saveBookReplacement(c *gin.Context, current, next *models.Book) {
+	fields, err := replaceBook(actorDB(c), current, next)
+	switch {
+	case len(fields) > 0:
+		utils.JSONFieldErrors(c, http.StatusUnprocessableEntity, "validation failed", fields)
+	case errors.Is(err, errVersionConflict):
+		var latest models.Book
+		database.DB.First(&latest, "id = ?", current.ID)
+		versionConflict(c, latest.ETag)
+	case database.IsISBNViolation(err):
+		utils.JSONError(c, http.StatusConflict, msgISBNTaken)
+	case err != nil:
+		utils.JSONError(c, http.StatusInternalServerError, "could not update book")
+	default:
+		c.Header("ETag", next.ETag)
+		utils.JSONSuccess(c, http.StatusOK, next)
+	}
 }
 
 /* ────────────────────────────────────────────────────────── *
-   DELETE /books/:id
+   DELETE /books/:id  ─ move to trash (?purge=true ⇒ permanent)
  * ────────────────────────────────────────────────────────── */
 
 func DeleteBook(c *gin.Context) {
@@ -130,12 +283,45 @@ func DeleteBook(c *gin.Context) {
 		return
 	}
 
+	// purge also accepts books that are already in the trash
+	purge := c.Query("purge") == "true"
+	db := actorDB(c)
+	if purge {
+		db = db.Unscoped()
+	}
+
 	var book models.Book
-	if err := database.DB.First(&book, "id = ?", bookID).Error; err != nil {
+	if err := db.First(&book, "id = ?", bookID).Error; err != nil {
 		utils.JSONError(c, http.StatusNotFound, "book not found")
 		return
 	}
+	if ifMatchFailed(c, &book) {
+		return
+	}
 
-	database.DB.Delete(&book)
-	utils.JSONSuccess(c, http.StatusOK, models.MessageResponse{Message: "book deleted"})
+	if purge {
+		purged, err := database.PurgeBook(db, &book)
+		switch {
+		case errors.Is(err, database.ErrBookInCirculation):
+			utils.JSONError(c, http.StatusConflict, "book has loans or fines on record and cannot be purged")
+		case err != nil:
+			utils.JSONError(c, http.StatusInternalServerError, "could not delete book")
+		case !purged:
+			versionConflict(c, "")
+		default:
+			utils.JSONSuccess(c, http.StatusOK, models.MessageResponse{Message: "book permanently deleted"})
+		}
+		return
+	}
+
+	trashed, err := database.TrashBook(db, &book)
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not delete book")
+		return
+	}
+	if !trashed {
+		versionConflict(c, "")
+		return
+	}
+	utils.JSONSuccess(c, http.StatusOK, models.MessageResponse{Message: "book moved to trash"})
 }
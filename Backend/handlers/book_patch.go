@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/hasan-kayan/TaskGo/utils"
+)
+
+/* ────────────────────────────────────────────────────────── *
+   PATCH /books/:id  ─ RFC 7396 merge patch / RFC 6902 patch
+ * ────────────────────────────────────────────────────────── */
+
+const (
+	mediaMergePatch = "application/merge-patch+json"
+	mediaJSONPatch  = "application/json-patch+json"
+)
+
+// readOnlyBookFields are JSON members clients may not change; the
+// matching columns are never written by PUT or PATCH.
+var (
+	readOnlyBookFields  = []string{"id", "created_at", "updated_at", "deleted_at", "version", "etag", "isbn10", "rating"}
+	readOnlyBookColumns = []string{"id", "created_at", "deleted_at"}
+)
+
+// PatchBook patches the JSON representation of a book, then validates
+// the complete result before anything is written:
+//
+//	Content-Type: application/merge-patch+json   {"isbn": null, "pages": 0}
+//	Content-Type: application/json-patch+json    [{"op":"replace","path":"/title","value":"…"}]
+//
+// Plain application/json is treated as a merge patch.
+func PatchBook(c *gin.Context) {
+	bookID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "invalid UUID")
+		return
+	}
+
+	var current models.Book
+	if err := database.DB.First(&current, "id = ?", bookID).Error; err != nil {
+		utils.JSONError(c, http.StatusNotFound, "book not found")
+		return
+	}
+	if ifMatchFailed(c, &current) {
+		return
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "could not read body")
+		return
+	}
+
+	doc, err := bookDocument(&current)
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not encode book")
+		return
+	}
+	before, _ := bookDocument(&current) // pristine copy – patches mutate doc
+
+	mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	var patched any
+	switch mediaType {
+	case mediaMergePatch, "application/json":
+		var patch any
+		if err := utils.DecodeJSON(body, &patch); err != nil {
+			utils.JSONError(c, http.StatusBadRequest, "malformed merge patch: "+err.Error())
+			return
+		}
+		if _, ok := patch.(map[string]any); !ok {
+			utils.JSONError(c, http.StatusBadRequest, "merge patch must be a JSON object")
+			return
+		}
+		patched = utils.MergePatch(doc, patch)
+
+	case mediaJSONPatch:
+		var ops []utils.PatchOp
+		if err := json.Unmarshal(body, &ops); err != nil {
+			utils.JSONError(c, http.StatusBadRequest, "malformed JSON patch: "+err.Error())
+			return
+		}
+		if patched, err = utils.ApplyJSONPatch(doc, ops); err != nil {
+			utils.JSONError(c, patchErrorStatus(err), err.Error())
+			return
+		}
+
+	default:
+		utils.JSONError(c, http.StatusUnsupportedMediaType,
+			"use "+mediaMergePatch+" or "+mediaJSONPatch)
+		return
+	}
+
+	next, fields := bookFromDocument(before, patched)
+	if len(fields) > 0 {
+		utils.JSONFieldErrors(c, http.StatusUnprocessableEntity, "validation failed", fields)
+		return
+	}
+
+	saveBookReplacement(c, &current, next)
+}
+
+func patchErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, utils.ErrPatchTestFailed):
+		return http.StatusConflict
+	case errors.Is(err, utils.ErrPatchInvalid):
+		return http.StatusBadRequest
+	default:
+		return http.StatusUnprocessableEntity
+	}
+}
+
+/* ────────────────────────────────────────────────────────── *
+   Book ⇄ JSON document helpers
+ * ────────────────────────────────────────────────────────── */
+
+// bookDocument renders book as a generic JSON object with every field
+// present – omitempty members are filled with their zero value so
+// patches can "replace" or "test" them like any other field.
+func bookDocument(book *models.Book) (map[string]any, error) {
+	raw, err := json.Marshal(book)
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]any
+	if err := utils.DecodeJSON(raw, &doc); err != nil {
+		return nil, err
+	}
+
+	t := reflect.TypeOf(*book)
+	for i := 0; i < t.NumField(); i++ {
+		name := jsonFieldName(t.Field(i))
+		if _, ok := doc[name]; name == "" || ok {
+			continue
+		}
+		zero, _ := json.Marshal(reflect.Zero(t.Field(i).Type).Interface())
+		var v any
+		_ = utils.DecodeJSON(zero, &v)
+		doc[name] = v
+	}
+	return doc, nil
+}
+
+func jsonFieldName(f reflect.StructField) string {
+	if !f.IsExported() {
+		return ""
+	}
+	name := strings.SplitN(f.Tag.Get("json"), ",", 2)[0]
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
+
+// bookFromDocument turns a patched document back into a Book, refusing
+// changes to read-only members. before is the unpatched document.
+func bookFromDocument(before map[string]any, patched any) (*models.Book, map[string]string) {
+	result, ok := patched.(map[string]any)
+	if !ok {
+		return nil, map[string]string{"_": "patched document must be a JSON object"}
+	}
+	if fields := readOnlyViolations(before, result); len(fields) > 0 {
+		return nil, fields
+	}
+
+	var next models.Book
+	if fields := decodeBookDocument(result, &next); len(fields) > 0 {
+		return nil, fields
+	}
+	return &next, nil
+}
+
+// decodeBookDocument converts a patched document back into a Book,
+// reporting unknown members and type mismatches per field.
+func decodeBookDocument(doc map[string]any, book *models.Book) map[string]string {
+	known := map[string]bool{}
+	t := reflect.TypeOf(*book)
+	for i := 0; i < t.NumField(); i++ {
+		known[jsonFieldName(t.Field(i))] = true
+	}
+
+	fields := map[string]string{}
+	for name := range doc {
+		if !known[name] {
+			fields[name] = "unknown field"
+		}
+	}
+	if len(fields) > 0 {
+		return fields
+	}
+
+	raw, _ := json.Marshal(doc)
+	if err := json.Unmarshal(raw, book); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return map[string]string{typeErr.Field: "must be of type " + typeErr.Type.String()}
+		}
+		return map[string]string{"_": err.Error()}
+	}
+	return nil
+}
+
+func readOnlyViolations(before, after map[string]any) map[string]string {
+	fields := map[string]string{}
+	for _, name := range readOnlyBookFields {
+		if !utils.JSONEqual(before[name], after[name]) {
+			fields[name] = "is read-only"
+		}
+	}
+	return fields
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/models"
+)
+
+// bookAverageRating is a book row's average stars, 0 when unreviewed.
+const bookAverageRating = "COALESCE((SELECT r.average FROM book_ratings r WHERE r.book_id = books.id), 0)"
+
+/* ────────────────────────────────────────────────────────── *
+   Shared /books filter set
+ * ────────────────────────────────────────────────────────── */
+
+// applyBookFilters narrows db by the query-string filters understood by
+// GET /books. Every endpoint that lists books goes through here so the
+// same URL yields the same set of rows everywhere.
+func applyBookFilters(db *gorm.DB, c *gin.Context) *gorm.DB {
+	if q := c.Query("title"); q != "" {
+		db = db.Where("LOWER(title) LIKE ?", "%"+strings.ToLower(q)+"%")
+	}
+	if q := c.Query("author"); q != "" {
+		// the byline or any credited author's name
+		like := "%" + strings.ToLower(q) + "%"
+		db = db.Where(`(LOWER(author) LIKE ? OR books.id IN (
+			SELECT ba.book_id FROM book_authors ba JOIN authors a ON a.id = ba.author_id
+			WHERE LOWER(a.name) LIKE ?))`, like, like)
+	}
+	if q := c.Query("author_id"); q != "" {
+		db = db.Where("books.id IN (SELECT book_id FROM book_authors WHERE author_id = ?)", q)
+	}
+	if q := c.Query("publisher_id"); q != "" {
+		// the publisher and all of its imprints
+		db = db.Where("books.publisher_id IN ("+publisherTree+")", q)
+	}
+	if q := c.Query("series"); q != "" {
+		db = db.Where("books.series_id = ?", q)
+	}
+	if q := c.Query("year"); q != "" {
+		year, _ := strconv.Atoi(q) // validated by checkBookFilters
+		db = db.Where("year = ?", year)
+	}
+	if q := c.Query("type"); q != "" {
+		db = db.Where("type = ?", q)
+	}
+	if q := c.Query("tags"); q != "" {
+		db = applyTagFilter(db, q, c.Query("tag_mode"))
+	}
+	if q := c.Query("min_rating"); q != "" {
+		// validated by checkBookFilters; an unreviewed book has no rating
+		stars, _ := strconv.ParseFloat(q, 64)
+		db = db.Where("books.id IN (SELECT book_id FROM book_ratings WHERE count > 0 AND average >= ?)", stars)
+	}
+	if q := c.Query("filter"); q != "" {
+		// validated by checkBookFilters
+		if f, err := parseBookFilter(q); err == nil {
+			db = db.Where(f.sql, f.args...)
+		}
+	}
+	return db
+}
+
+// checkBookFilters rejects filter values applyBookFilters cannot use.
+func checkBookFilters(c *gin.Context) error {
+	if q := c.Query("year"); q != "" {
+		if _, err := strconv.Atoi(q); err != nil {
+			return errors.New("year must be an integer")
+		}
+	}
+	if q := c.Query("tag_mode"); q != "" && q != "any" && q != "all" {
+		return errors.New("tag_mode must be any or all")
+	}
+	if q := c.Query("min_rating"); q != "" {
+		if n, err := strconv.ParseFloat(q, 64); err != nil || n < 0 || n > 5 {
+			return errors.New("min_rating must be a number from 0 to 5")
+		}
+	}
+	if q := c.Query("filter"); q != "" {
+		if _, err := parseBookFilter(q); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// withRatings fills Rating on each book from the running totals; books
+// without reviews get a zero rating.
+func withRatings(db *gorm.DB, books []models.Book) error {
+	if len(books) == 0 {
+		return nil
+	}
+	ids := make([]uuid.UUID, len(books))
+	for i := range books {
+		ids[i] = books[i].ID
+	}
+	var ratings []models.BookRating
+	err := db.Session(&gorm.Session{NewDB: true}).Where("book_id IN ?", ids).Find(&ratings).Error
+	if err != nil {
+		return err
+	}
+	byBook := make(map[uuid.UUID]models.BookRating, len(ratings))
+	for _, r := range ratings {
+		byBook[r.BookID] = r
+	}
+	for i := range books {
+		r := byBook[books[i].ID]
+		books[i].Rating = &r
+	}
+	return nil
+}
+
+// bookSource is the table GET /books and GET /books/:id read from: the
+// live books, or their state at ?as_of=<RFC 3339> from the temporal
+// history. Only book columns are historical; credits, tags and other
+// links are matched as they are now.
+func bookSource(c *gin.Context) (*gorm.DB, error) {
+	raw := c.Query("as_of")
+	if raw == "" {
+		return database.DB, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, raw)
+	if err != nil {
+		return nil, errors.New("as_of must be an RFC 3339 timestamp")
+	}
+	// stored timestamps are local time; compare like with like
+	return database.BooksAsOf(database.DB, t.Local()), nil
+}
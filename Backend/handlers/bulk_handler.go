@@ -0,0 +1,289 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/hasan-kayan/TaskGo/config"
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/hasan-kayan/TaskGo/utils"
+)
+
+/* ────────────────────────────────────────────────────────── *
+   POST /books/bulk  ─ many create/update/delete ops, one call
+ * ────────────────────────────────────────────────────────── */
+
+// BULK_MAX_OPERATIONS caps a single request (default 1000). A bulk call
+// counts once against the per-IP rate limiter.
+var bulkMaxOperations = config.Int("BULK_MAX_OPERATIONS", 1000, 1)
+
+const (
+	bulkAtomic     = "atomic"
+	bulkBestEffort = "best_effort"
+)
+
+// BulkRequest is the body of POST /books/bulk.
+//
+//	{
+//	  "mode": "atomic",                      // or "best_effort"
+//	  "operations": [
+//	    {"op": "create", "book": {"title": "…", "author": "…"}},
+//	    {"op": "update", "id": "…", "if_match": "\"…\"", "book": {"pages": 0}},
+//	    {"op": "delete", "id": "…"}
+//	  ]
+//	}
+//
+// "update" applies "book" as a JSON merge patch, exactly like PATCH.
+type BulkRequest struct {
+	Mode       string          `json:"mode"`
+	Operations []BulkOperation `json:"operations"`
+}
+
+type BulkOperation struct {
+	Op      string          `json:"op"`
+	ID      string          `json:"id,omitempty"`
+	IfMatch string          `json:"if_match,omitempty"`
+	Book    json.RawMessage `json:"book,omitempty"`
+}
+
+// BulkResult reports the outcome of one operation; Status mirrors the
+// HTTP status the equivalent single-item call would have returned.
+type BulkResult struct {
+	Index  int               `json:"index"`
+	Op     string            `json:"op"`
+	ID     string            `json:"id,omitempty"`
+	Status int               `json:"status"`
+	Error  string            `json:"error,omitempty"`
+	Fields map[string]string `json:"fields,omitempty"`
+	Book   *models.Book      `json:"book,omitempty"`
+}
+
+func (r BulkResult) ok() bool { return r.Status < 400 }
+
+// BulkResponse summarises the batch. In atomic mode Committed is false
+// whenever any operation failed, and nothing was written.
+type BulkResponse struct {
+	Mode      string       `json:"mode"`
+	Committed bool         `json:"committed"`
+	Succeeded int          `json:"succeeded"`
+	Failed    int          `json:"failed"`
+	Results   []BulkResult `json:"results"`
+}
+
+// errBulkRollback aborts the atomic transaction after all ops ran.
+var errBulkRollback = errors.New("bulk rollback")
+
+func BulkBooks(c *gin.Context) {
+	var req BulkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.Mode == "" {
+		req.Mode = bulkAtomic
+	}
+	if req.Mode != bulkAtomic && req.Mode != bulkBestEffort {
+		utils.JSONError(c, http.StatusBadRequest, "mode must be atomic or best_effort")
+		return
+	}
+	if len(req.Operations) == 0 {
+		utils.JSONError(c, http.StatusBadRequest, "operations must not be empty")
+		return
+	}
+	if len(req.Operations) > bulkMaxOperations {
+		utils.JSONError(c, http.StatusRequestEntityTooLarge,
+			fmt.Sprintf("at most %d operations per request", bulkMaxOperations))
+		return
+	}
+
+	resp := BulkResponse{Mode: req.Mode, Results: make([]BulkResult, len(req.Operations))}
+	db := actorDB(c)
+
+	if req.Mode == bulkAtomic {
+		// one transaction; every op still runs so the client gets the
+		// full error report, then we roll back if anything failed
+		err := db.Transaction(func(tx *gorm.DB) error {
+			failed := false
+			for i, op := range req.Operations {
+				resp.Results[i] = runBulkOperation(tx, i, op)
+				failed = failed || !resp.Results[i].ok()
+			}
+			if failed {
+				return errBulkRollback
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, errBulkRollback) {
+			utils.JSONError(c, http.StatusInternalServerError, "bulk transaction failed")
+			return
+		}
+		resp.Committed = err == nil
+	} else {
+		// each op in its own transaction so a failure never leaves a
+		// half-applied item behind
+		for i, op := range req.Operations {
+			_ = db.Transaction(func(tx *gorm.DB) error {
+				resp.Results[i] = runBulkOperation(tx, i, op)
+				if !resp.Results[i].ok() {
+					return errBulkRollback
+				}
+				return nil
+			})
+		}
+		resp.Committed = true
+	}
+
+	for _, r := range resp.Results {
+		if r.ok() {
+			resp.Succeeded++
+		} else {
+			resp.Failed++
+		}
+	}
+	if !resp.Committed {
+		// report what would have happened, but make clear nothing did
+		for i := range resp.Results {
+			resp.Results[i].Book = nil
+		}
+		utils.JSONErrorData(c, http.StatusUnprocessableEntity, "bulk operation rolled back – see data.results", resp)
+		return
+	}
+	utils.JSONSuccess(c, http.StatusOK, resp)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   Single operations (run inside the caller's transaction)
+ * ────────────────────────────────────────────────────────── */
+
+func runBulkOperation(tx *gorm.DB, index int, op BulkOperation) BulkResult {
+	res := BulkResult{Index: index, Op: op.Op, ID: op.ID}
+	switch op.Op {
+	case "create":
+		bulkCreate(tx, op, &res)
+	case "update":
+		bulkUpdate(tx, op, &res)
+	case "delete":
+		bulkDelete(tx, op, &res)
+	default:
+		res.Status, res.Error = http.StatusBadRequest, "op must be create, update or delete"
+	}
+	return res
+}
+
+func bulkCreate(tx *gorm.DB, op BulkOperation, res *BulkResult) {
+	var book models.Book
+	if err := json.Unmarshal(op.Book, &book); err != nil {
+		res.Status, res.Error = http.StatusBadRequest, "invalid book: "+err.Error()
+		return
+	}
+	if book.ID == uuid.Nil {
+		book.ID = uuid.New()
+	}
+	book.Rating = nil // live review totals, never taken from the client
+	res.ID = book.ID.String()
+
+	if err := utils.ValidateBook(&book); err != nil {
+		res.Status, res.Error, res.Fields = http.StatusUnprocessableEntity, "validation failed", utils.FieldErrors(err)
+		return
+	}
+	if fields := resolveBookRefs(tx, &book); len(fields) > 0 {
+		res.Status, res.Error, res.Fields = http.StatusUnprocessableEntity, "validation failed", fields
+		return
+	}
+	if err := tx.Create(&book).Error; err != nil {
+		switch {
+		case database.IsISBNViolation(err):
+			res.Status, res.Error = http.StatusConflict, msgISBNTaken
+		case database.IsUniqueViolation(err):
+			res.Status, res.Error = http.StatusConflict, msgIDTaken
+		default:
+			res.Status, res.Error = http.StatusInternalServerError, "could not create book"
+		}
+		return
+	}
+	res.Status, res.Book = http.StatusCreated, &book
+}
+
+// loadForBulk fetches the target of an update/delete and checks if_match.
+func loadForBulk(tx *gorm.DB, op BulkOperation, res *BulkResult) *models.Book {
+	id, err := uuid.Parse(op.ID)
+	if err != nil {
+		res.Status, res.Error = http.StatusBadRequest, "invalid UUID"
+		return nil
+	}
+	var book models.Book
+	if err := tx.First(&book, "id = ?", id).Error; err != nil {
+		res.Status, res.Error = http.StatusNotFound, "book not found"
+		return nil
+	}
+	if op.IfMatch != "" && !utils.ETagMatches(op.IfMatch, book.ETag, false) {
+		res.Status, res.Error = http.StatusPreconditionFailed, "if_match does not match current etag"
+		return nil
+	}
+	return &book
+}
+
+func bulkUpdate(tx *gorm.DB, op BulkOperation, res *BulkResult) {
+	current := loadForBulk(tx, op, res)
+	if current == nil {
+		return
+	}
+
+	var patch any
+	if err := utils.DecodeJSON(op.Book, &patch); err != nil {
+		res.Status, res.Error = http.StatusBadRequest, "invalid book patch"
+		return
+	}
+	if _, ok := patch.(map[string]any); !ok {
+		res.Status, res.Error = http.StatusBadRequest, "book must be a JSON object"
+		return
+	}
+
+	doc, err := bookDocument(current)
+	if err != nil {
+		res.Status, res.Error = http.StatusInternalServerError, "could not encode book"
+		return
+	}
+	before, _ := bookDocument(current)
+	next, fields := bookFromDocument(before, utils.MergePatch(doc, patch))
+	if len(fields) == 0 {
+		fields, err = replaceBook(tx, current, next)
+	}
+
+	switch {
+	case len(fields) > 0:
+		res.Status, res.Error, res.Fields = http.StatusUnprocessableEntity, "validation failed", fields
+	case errors.Is(err, errVersionConflict):
+		res.Status, res.Error = http.StatusPreconditionFailed, "book was modified concurrently"
+	case database.IsISBNViolation(err):
+		res.Status, res.Error = http.StatusConflict, msgISBNTaken
+	case err != nil:
+		res.Status, res.Error = http.StatusInternalServerError, "could not update book"
+	default:
+		res.Status, res.Book = http.StatusOK, next
+	}
+}
+
+func bulkDelete(tx *gorm.DB, op BulkOperation, res *BulkResult) {
+	book := loadForBulk(tx, op, res)
+	if book == nil {
+		return
+	}
+
+	trashed, err := database.TrashBook(tx, book)
+	switch {
+	case err != nil:
+		res.Status, res.Error = http.StatusInternalServerError, "could not delete book"
+	case !trashed:
+		res.Status, res.Error = http.StatusPreconditionFailed, "book was modified concurrently"
+	default:
+		res.Status = http.StatusOK
+	}
+}
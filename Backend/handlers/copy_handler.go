@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/hasan-kayan/TaskGo/config"
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/hasan-kayan/TaskGo/utils"
+)
+
+// copyOnLoan flags copies that have an active loan.
+const copyOnLoan = `EXISTS (SELECT 1 FROM loans l
+	WHERE l.copy_id = copies.id AND l.returned_at IS NULL) AS on_loan`
+
+const msgBarcodeTaken = "a copy with this barcode already exists"
+
+// Availability summarises the copies of a book for GET /books/:id.
+// Copies set aside for a ready hold are neither on loan nor available.
+type Availability struct {
+	Copies    int64 `json:"copies"`
+	OnLoan    int64 `json:"on_loan"`
+	OnHold    int64 `json:"on_hold,omitempty"`
+	Available int64 `json:"available"`
+	Waiting   int64 `json:"holds_waiting,omitempty"`
+}
+
+// BookDetail is a book plus its live circulation figures.
+type BookDetail struct {
+	models.Book
+	Availability *Availability `json:"availability,omitempty"`
+}
+
+/* ────────────────────────────────────────────────────────── *
+   GET|POST /books/:id/copies  ─ a book's physical copies
+ * ────────────────────────────────────────────────────────── */
+
+func GetBookCopies(c *gin.Context) {
+	book, ok := loadBookParam(c)
+	if !ok {
+		return
+	}
+
+	copies := []models.Copy{}
+	err := database.DB.Model(&models.Copy{}).
+		Select("copies.*, "+copyOnLoan).
+		Where("book_id = ?", book.ID).
+		Order("barcode").
+		Find(&copies).Error
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not list copies")
+		return
+	}
+	utils.JSONSuccess(c, http.StatusOK, copies)
+}
+
+func CreateCopy(c *gin.Context) {
+	book, ok := loadBookParam(c)
+	if !ok {
+		return
+	}
+
+	var payload models.Copy
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	payload.ID = uuid.New()
+	payload.BookID = book.ID
+	payload.Barcode = strings.TrimSpace(payload.Barcode)
+
+	if err := utils.ValidateCopy(&payload); err != nil {
+		utils.JSONFieldErrors(c, http.StatusUnprocessableEntity, "validation failed", utils.FieldErrors(err))
+		return
+	}
+	// a new copy goes straight to the first waiting hold, if any
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&payload).Error; err != nil {
+			return err
+		}
+		_, err := database.AssignNextHold(tx, &payload, time.Now(), config.HoldPickupWindow)
+		return err
+	})
+	if err != nil {
+		if database.IsUniqueViolation(err) {
+			utils.JSONError(c, http.StatusConflict, msgBarcodeTaken)
+			return
+		}
+		utils.JSONError(c, http.StatusInternalServerError, "could not create copy")
+		return
+	}
+	utils.JSONSuccess(c, http.StatusCreated, payload)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   GET /copies/:id  ─ fetch; GET /copies?barcode= ─ scanner lookup
+ * ────────────────────────────────────────────────────────── */
+
+func GetCopy(c *gin.Context) {
+	item, ok := loadCopy(c)
+	if !ok {
+		return
+	}
+	utils.JSONSuccess(c, http.StatusOK, item)
+}
+
+// GetCopyByBarcode answers GET /copies?barcode=, the desk's scanner lookup.
+func GetCopyByBarcode(c *gin.Context) {
+	barcode := strings.TrimSpace(c.Query("barcode"))
+	if barcode == "" {
+		utils.JSONError(c, http.StatusBadRequest, "barcode is required")
+		return
+	}
+	var item models.Copy
+	err := database.DB.Model(&models.Copy{}).
+		Select("copies.*, "+copyOnLoan).
+		First(&item, "barcode = ?", barcode).Error
+	if err != nil {
+		utils.JSONError(c, http.StatusNotFound, "copy not found")
+		return
+	}
+	utils.JSONSuccess(c, http.StatusOK, item)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   PUT /copies/:id  ─ barcode / condition / location
+ * ────────────────────────────────────────────────────────── */
+
+func UpdateCopy(c *gin.Context) {
+	current, ok := loadCopy(c)
+	if !ok {
+		return
+	}
+
+	var next models.Copy
+	if err := c.ShouldBindJSON(&next); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	next.ID, next.BookID = current.ID, current.BookID
+	next.Barcode = strings.TrimSpace(next.Barcode)
+	if next.Condition == "" {
+		next.Condition = models.ConditionGood
+	}
+
+	if err := utils.ValidateCopy(&next); err != nil {
+		utils.JSONFieldErrors(c, http.StatusUnprocessableEntity, "validation failed", utils.FieldErrors(err))
+		return
+	}
+	err := database.DB.Model(&models.Copy{ID: current.ID}).
+		Select("barcode", "condition", "location").
+		Updates(&next).Error
+	if err != nil {
+		if database.IsUniqueViolation(err) {
+			utils.JSONError(c, http.StatusConflict, msgBarcodeTaken)
+			return
+		}
+		utils.JSONError(c, http.StatusInternalServerError, "could not update copy")
+		return
+	}
+
+	updated, _ := findCopy(database.DB, current.ID)
+	utils.JSONSuccess(c, http.StatusOK, updated)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   DELETE /copies/:id  ─ withdraw; not while on loan or on hold
+ * ────────────────────────────────────────────────────────── */
+
+func DeleteCopy(c *gin.Context) {
+	item, ok := loadCopy(c)
+	if !ok {
+		return
+	}
+	if item.OnLoan {
+		utils.JSONError(c, http.StatusConflict, "copy is on loan; return it first")
+		return
+	}
+	var held int64
+	database.DB.Model(&models.Hold{}).Where("copy_id = ? AND status = ?", item.ID, models.HoldReady).Count(&held)
+	if held > 0 {
+		utils.JSONError(c, http.StatusConflict, "copy is held for a member; cancel the hold first")
+		return
+	}
+
+	if err := database.DB.Delete(&models.Copy{ID: item.ID}).Error; err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not delete copy")
+		return
+	}
+	utils.JSONSuccess(c, http.StatusOK, models.MessageResponse{Message: "copy deleted"})
+}
+
+/* ────────────────────────────────────────────────────────── *
+   helpers
+ * ────────────────────────────────────────────────────────── */
+
+func loadCopy(c *gin.Context) (*models.Copy, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "invalid UUID")
+		return nil, false
+	}
+	item, err := findCopy(database.DB, id)
+	if err != nil {
+		utils.JSONError(c, http.StatusNotFound, "copy not found")
+		return nil, false
+	}
+	return item, true
+}
+
+func findCopy(db *gorm.DB, id uuid.UUID) (*models.Copy, error) {
+	var item models.Copy
+	err := db.Model(&models.Copy{}).
+		Select("copies.*, "+copyOnLoan).
+		First(&item, "copies.id = ?", id).Error
+	return &item, err
+}
+
+// bookAvailability counts the book's copies, how many are out or set
+// aside, and how many holds are queued.
+func bookAvailability(db *gorm.DB, bookID uuid.UUID) (*Availability, error) {
+	var a Availability
+	err := db.Model(&models.Copy{}).
+		Select("COUNT(*) AS copies, COALESCE(SUM(CASE WHEN "+
+			"EXISTS (SELECT 1 FROM loans l WHERE l.copy_id = copies.id AND l.returned_at IS NULL) "+
+			"THEN 1 ELSE 0 END), 0) AS on_loan, COALESCE(SUM(CASE WHEN "+
+			"EXISTS (SELECT 1 FROM holds h WHERE h.copy_id = copies.id AND h.status = ?) "+
+			"THEN 1 ELSE 0 END), 0) AS on_hold", models.HoldReady).
+		Where("book_id = ?", bookID).
+		Scan(&a).Error
+	if err != nil {
+		return &a, err
+	}
+	a.Available = a.Copies - a.OnLoan - a.OnHold
+	err = db.Model(&models.Hold{}).
+		Where("book_id = ? AND status = ?", bookID, models.HoldWaiting).
+		Count(&a.Waiting).Error
+	return &a, err
+}
@@ -0,0 +1,532 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/hasan-kayan/TaskGo/config"
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/hasan-kayan/TaskGo/utils"
+)
+
+const (
+	defaultDuplicateScore = 0.85
+	// bylines must be at least this alike before titles are compared
+	duplicateAuthorMatch = 0.8
+	// title weighs more than byline in a pair's score
+	duplicateTitleWeight = 0.7
+)
+
+// DuplicatePair is two books that look like the same record. Reason is
+// "isbn" for equivalent ISBNs (score 1) or "title_author".
+type DuplicatePair struct {
+	A           uuid.UUID `json:"a"`
+	B           uuid.UUID `json:"b"`
+	Score       float64   `json:"score"`
+	Reason      string    `json:"reason"`
+	TitleScore  float64   `json:"title_score,omitempty"`
+	AuthorScore float64   `json:"author_score,omitempty"`
+}
+
+// DuplicateCluster is a group of books linked by candidate pairs. The
+// books come oldest first; SuggestedTarget is the oldest.
+type DuplicateCluster struct {
+	Score           float64         `json:"score"` // best pair in the cluster
+	SuggestedTarget uuid.UUID       `json:"suggested_target"`
+	Books           []models.Book   `json:"books"`
+	Pairs           []DuplicatePair `json:"pairs"`
+}
+
+/* ────────────────────────────────────────────────────────── *
+   GET /books/duplicates  ─ candidate clusters, ?min_score=0.85
+ * ────────────────────────────────────────────────────────── */
+
+// GetBookDuplicates pairs live books whose ISBNs are equivalent, or
+// whose normalised bylines and titles are close enough, and reports the
+// connected groups best match first.
+func GetBookDuplicates(c *gin.Context) {
+	limit, offset, err := parseLimitOffset(c)
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	minScore := defaultDuplicateScore
+	if raw := c.Query("min_score"); raw != "" {
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil || n <= 0 || n > 1 {
+			utils.JSONError(c, http.StatusBadRequest, "min_score must be a number above 0 and at most 1")
+			return
+		}
+		minScore = n
+	}
+
+	var books []models.Book
+	if err := database.DB.Order("created_at").Order("id").Find(&books).Error; err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not load books")
+		return
+	}
+
+	clusters := duplicateClusters(books, minScore)
+	total := int64(len(clusters))
+	clusters = clusters[min(offset, len(clusters)):]
+	clusters = clusters[:min(limit, len(clusters))]
+	meta := &PageMeta{Total: total, Limit: limit, Offset: offset, Sort: "-score"}
+	utils.JSONSuccessMeta(c, http.StatusOK, clusters, meta)
+}
+
+// dupKey is what a book is compared by, computed once per book.
+type dupKey struct {
+	isbn, title, mainTitle, author string
+	series                         *uuid.UUID
+	volume                         *float64
+}
+
+func duplicateClusters(books []models.Book, minScore float64) []DuplicateCluster {
+	keys := make([]dupKey, len(books))
+	byISBN := map[string][]int{}
+	// only books whose bylines share a name-word prefix are compared by
+	// title, so buckets stay small; books without a byline share one
+	// by the start of their main title instead
+	byName := map[string][]int{}
+	for i, b := range books {
+		k := dupKey{
+			title:     utils.NormaliseTitle(b.Title),
+			mainTitle: utils.MainTitle(b.Title),
+			author:    utils.NormaliseAuthor(b.Author),
+			series:    b.SeriesID,
+			volume:    b.Volume,
+		}
+		if isbn13, _, err := utils.NormaliseISBN(b.ISBN); err == nil {
+			k.isbn = isbn13
+			byISBN[isbn13] = append(byISBN[isbn13], i)
+		}
+		keys[i] = k
+		names := utils.NameKeys(b.Author)
+		if len(names) == 0 {
+			names = []string{"title:" + firstRunes(k.mainTitle, 3)}
+		}
+		for _, n := range names {
+			byName[n] = append(byName[n], i)
+		}
+	}
+
+	pairs := map[[2]int]DuplicatePair{}
+	for _, group := range byISBN {
+		for x, i := range group {
+			for _, j := range group[x+1:] {
+				pairs[[2]int{i, j}] = DuplicatePair{A: books[i].ID, B: books[j].ID, Score: 1, Reason: "isbn"}
+			}
+		}
+	}
+	// a pair sharing several keys is scored once
+	compared := map[[2]int]bool{}
+	for _, group := range byName {
+		for x, i := range group {
+			for _, j := range group[x+1:] {
+				if _, done := pairs[[2]int{i, j}]; done || compared[[2]int{i, j}] {
+					continue
+				}
+				compared[[2]int{i, j}] = true
+				if p, ok := scorePair(keys[i], keys[j], minScore); ok {
+					p.A, p.B = books[i].ID, books[j].ID
+					pairs[[2]int{i, j}] = p
+				}
+			}
+		}
+	}
+
+	// union-find over the pairs
+	parent := make([]int, len(books))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	for ij := range pairs {
+		a, b := find(ij[0]), find(ij[1])
+		// the oldest book is the root, so it leads the cluster
+		parent[max(a, b)] = min(a, b)
+	}
+
+	byRoot := map[int]*DuplicateCluster{}
+	var roots []int
+	for ij, p := range pairs {
+		root := find(ij[0])
+		cl := byRoot[root]
+		if cl == nil {
+			cl = &DuplicateCluster{}
+			byRoot[root] = cl
+			roots = append(roots, root)
+		}
+		cl.Pairs = append(cl.Pairs, p)
+		cl.Score = max(cl.Score, p.Score)
+	}
+	for i := range books {
+		if cl := byRoot[find(i)]; cl != nil {
+			cl.Books = append(cl.Books, books[i])
+		}
+	}
+
+	sort.Ints(roots)
+	out := make([]DuplicateCluster, 0, len(roots))
+	for _, root := range roots {
+		cl := byRoot[root]
+		cl.SuggestedTarget = cl.Books[0].ID
+		sort.Slice(cl.Pairs, func(i, j int) bool {
+			if cl.Pairs[i].Score != cl.Pairs[j].Score {
+				return cl.Pairs[i].Score > cl.Pairs[j].Score
+			}
+			return cl.Pairs[i].A.String()+cl.Pairs[i].B.String() < cl.Pairs[j].A.String()+cl.Pairs[j].B.String()
+		})
+		out = append(out, *cl)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score > out[j].Score
+		}
+		return out[i].Books[0].CreatedAt.Before(out[j].Books[0].CreatedAt)
+	})
+	return out
+}
+
+// scorePair compares two books by byline and title. The title score is
+// the better of the full titles and the titles without subtitles, so
+// "Clean Code" matches "Clean Code: A Handbook of Agile Craftsmanship".
+// Books with different ISBNs, or different volumes of one series, are
+// distinct editions or parts however alike they read.
+func scorePair(a, b dupKey, minScore float64) (DuplicatePair, bool) {
+	if a.isbn != "" && b.isbn != "" && a.isbn != b.isbn {
+		return DuplicatePair{}, false
+	}
+	if a.series != nil && b.series != nil && *a.series == *b.series &&
+		a.volume != nil && b.volume != nil && *a.volume != *b.volume {
+		return DuplicatePair{}, false
+	}
+	author := utils.Similarity(a.author, b.author)
+	if author < duplicateAuthorMatch {
+		return DuplicatePair{}, false
+	}
+	title := max(utils.Similarity(a.title, b.title), utils.Similarity(a.mainTitle, b.mainTitle))
+	score := round3(duplicateTitleWeight*title + (1-duplicateTitleWeight)*author)
+	if score < minScore {
+		return DuplicatePair{}, false
+	}
+	return DuplicatePair{Score: score, Reason: "title_author", TitleScore: round3(title), AuthorScore: round3(author)}, true
+}
+
+// firstRunes is s cut to at most n runes.
+func firstRunes(s string, n int) string {
+	r := []rune(s)
+	return string(r[:min(n, len(r))])
+}
+
+func round3(f float64) float64 { return math.Round(f*1000) / 1000 }
+
+/* ────────────────────────────────────────────────────────── *
+   POST /books/merge  ─ fold duplicates into one book
+ * ────────────────────────────────────────────────────────── */
+
+// BookMergeRequest names the surviving book, the duplicates folded
+// into it, and optionally which book's value wins per field.
+type BookMergeRequest struct {
+	TargetID  uuid.UUID            `json:"target_id" binding:"required"`
+	SourceIDs []uuid.UUID          `json:"source_ids" binding:"required"`
+	Fields    map[string]uuid.UUID `json:"fields"`
+}
+
+// BookMergeResult is the merged book and how many related rows moved
+// to it, per kind.
+type BookMergeResult struct {
+	Book   *models.Book     `json:"book"`
+	Merged int              `json:"merged"`
+	Moved  map[string]int64 `json:"moved"`
+}
+
+// mergeField is a group of book columns that wins or loses together.
+type mergeField struct {
+	name  string
+	empty func(b *models.Book) bool
+	take  func(dst, src *models.Book)
+}
+
+var bookMergeFields = []mergeField{
+	{"title", func(b *models.Book) bool { return b.Title == "" }, func(d, s *models.Book) { d.Title = s.Title }},
+	{"author", func(b *models.Book) bool { return b.Author == "" }, func(d, s *models.Book) { d.Author = s.Author }},
+	{"year", func(b *models.Book) bool { return b.Year == 0 }, func(d, s *models.Book) { d.Year = s.Year }},
+	{"isbn", func(b *models.Book) bool { return b.ISBN == "" }, func(d, s *models.Book) { d.ISBN, d.ISBN10 = s.ISBN, s.ISBN10 }},
+	{"description", func(b *models.Book) bool { return b.Description == "" }, func(d, s *models.Book) { d.Description = s.Description }},
+	{"cover_image_url", func(b *models.Book) bool { return b.CoverImageURL == "" }, func(d, s *models.Book) { d.CoverImageURL = s.CoverImageURL }},
+	{"publisher", func(b *models.Book) bool { return b.Publisher == "" && b.PublisherID == nil }, func(d, s *models.Book) {
+		d.Publisher, d.PublisherID = s.Publisher, s.PublisherID
+	}},
+	{"type", func(b *models.Book) bool { return b.Type == "" }, func(d, s *models.Book) { d.Type = s.Type }},
+	{"pages", func(b *models.Book) bool { return b.Pages == 0 }, func(d, s *models.Book) { d.Pages = s.Pages }},
+	{"series", func(b *models.Book) bool { return b.SeriesID == nil }, func(d, s *models.Book) { d.SeriesID, d.Volume = s.SeriesID, s.Volume }},
+}
+
+// mergedRelations are the kinds of rows a merge re-points.
+var mergedRelations = []string{"credits", "tags", "copies", "loans", "fines", "holds", "reviews", "list_items"}
+
+// errMergeInvalid rolls a merge back when the merged book fails validation.
+var errMergeInvalid = errors.New("merged book is invalid")
+
+// MergeBooks folds the source books into the target in one transaction.
+// Each field keeps the target's value unless Fields names another book,
+// or the target's is empty and a source has one (first source wins).
+// Credits, tags, copies, loans, fines, holds, reviews and reading-list
+// entries move to the target – where a member or list already has the
+// target, the source's row is dropped – then the sources are trashed.
+// If-Match applies to the target.
+func MergeBooks(c *gin.Context) {
+	var req BookMergeRequest
+	if err := c.ShouldBindJSON(&req); err != nil || len(req.SourceIDs) == 0 {
+		utils.JSONError(c, http.StatusBadRequest, "body must be {\"target_id\": \"…\", \"source_ids\": [\"…\"]}")
+		return
+	}
+
+	var target models.Book
+	if err := database.DB.First(&target, "id = ?", req.TargetID).Error; err != nil {
+		utils.JSONError(c, http.StatusNotFound, "book not found")
+		return
+	}
+	if ifMatchFailed(c, &target) {
+		return
+	}
+
+	fields := map[string]string{}
+	var found []models.Book
+	if err := database.DB.Where("id IN ?", req.SourceIDs).Find(&found).Error; err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not load books")
+		return
+	}
+	byID := map[uuid.UUID]*models.Book{target.ID: &target}
+	for i := range found {
+		byID[found[i].ID] = &found[i]
+	}
+	sources := make([]*models.Book, 0, len(req.SourceIDs))
+	seen := map[uuid.UUID]bool{}
+	for i, id := range req.SourceIDs {
+		key := fmt.Sprintf("source_ids[%d]", i)
+		switch {
+		case id == target.ID:
+			fields[key] = "cannot merge a book into itself"
+		case seen[id]:
+			fields[key] = "duplicate id"
+		case byID[id] == nil:
+			fields[key] = "book not found"
+		default:
+			sources = append(sources, byID[id])
+		}
+		seen[id] = true
+	}
+	known := map[string]bool{}
+	for _, f := range bookMergeFields {
+		known[f.name] = true
+	}
+	for name, id := range req.Fields {
+		switch {
+		case !known[name]:
+			fields["fields."+name] = "unknown field"
+		case id != target.ID && !seen[id]:
+			fields["fields."+name] = "must be the target or one of the sources"
+		}
+	}
+	if len(fields) > 0 {
+		utils.JSONFieldErrors(c, http.StatusUnprocessableEntity, "validation failed", fields)
+		return
+	}
+
+	next := target
+	for _, f := range bookMergeFields {
+		if id, ok := req.Fields[f.name]; ok {
+			f.take(&next, byID[id])
+			continue
+		}
+		for _, s := range sources {
+			if !f.empty(&next) {
+				break
+			}
+			f.take(&next, s)
+		}
+	}
+
+	result := BookMergeResult{Merged: len(sources), Moved: map[string]int64{}}
+	for _, kind := range mergedRelations {
+		result.Moved[kind] = 0
+	}
+	var invalid map[string]string
+	now := time.Now()
+	err := actorDB(c).Transaction(func(tx *gorm.DB) error {
+		for _, s := range sources {
+			if err := moveBookRelations(tx, s.ID, target.ID, now, result.Moved); err != nil {
+				return err
+			}
+			// trashed first, so the target can take over its ISBN
+			trashed, err := database.TrashBook(tx, s)
+			if err != nil {
+				return err
+			}
+			if !trashed {
+				return errVersionConflict
+			}
+		}
+
+		var err error
+		if invalid, err = replaceBook(tx, &target, &next); err != nil {
+			return err
+		}
+		if len(invalid) > 0 {
+			return errMergeInvalid
+		}
+		// moved copies may be free for the target's waiting holds
+		return database.AssignFreeCopies(tx, target.ID, now, config.HoldPickupWindow)
+	})
+	switch {
+	case errors.Is(err, errMergeInvalid):
+		utils.JSONFieldErrors(c, http.StatusUnprocessableEntity, "validation failed", invalid)
+	case errors.Is(err, errVersionConflict):
+		var latest models.Book
+		database.DB.First(&latest, "id = ?", target.ID)
+		versionConflict(c, latest.ETag)
+	case database.IsISBNViolation(err):
+		utils.JSONError(c, http.StatusConflict, msgISBNTaken)
+	case err != nil:
+		utils.JSONError(c, http.StatusInternalServerError, "could not merge books")
+	default:
+		result.Book = &next
+		c.Header("ETag", next.ETag)
+		utils.JSONSuccess(c, http.StatusOK, result)
+	}
+}
+
+// moveBookRelations re-points everything that hangs off book from at
+// book to, adding the counts to moved. Rows that would collide with one
+// the target already has (same credit, tag, open hold, reviewer or
+// list) are dropped instead; revisions and history stay with from.
+func moveBookRelations(tx *gorm.DB, from, to uuid.UUID, now time.Time, moved map[string]int64) error {
+	// credits keep their order, after the target's own
+	var next int
+	err := tx.Model(&models.BookAuthor{}).Select("COALESCE(MAX(position) + 1, 0)").Where("book_id = ?", to).Scan(&next).Error
+	if err != nil {
+		return err
+	}
+	res := tx.Exec(`UPDATE book_authors SET book_id = ?, position = position + ?
+		WHERE book_id = ? AND NOT EXISTS (SELECT 1 FROM book_authors t
+			WHERE t.book_id = ? AND t.author_id = book_authors.author_id AND t.role = book_authors.role)`,
+		to, next, from, to)
+	if res.Error != nil {
+		return res.Error
+	}
+	moved["credits"] += res.RowsAffected
+
+	res = tx.Exec(`UPDATE book_tags SET book_id = ?
+		WHERE book_id = ? AND NOT EXISTS (SELECT 1 FROM book_tags t
+			WHERE t.book_id = ? AND t.tag_id = book_tags.tag_id)`,
+		to, from, to)
+	if res.Error != nil {
+		return res.Error
+	}
+	moved["tags"] += res.RowsAffected
+	for _, leftover := range []any{&models.BookAuthor{}, &models.BookTag{}} {
+		if err := tx.Where("book_id = ?", from).Delete(leftover).Error; err != nil {
+			return err
+		}
+	}
+
+	for kind, model := range map[string]any{"copies": &models.Copy{}, "loans": &models.Loan{}, "fines": &models.Fine{}} {
+		res := tx.Model(model).Where("book_id = ?", from).Update("book_id", to)
+		if res.Error != nil {
+			return res.Error
+		}
+		moved[kind] += res.RowsAffected
+	}
+
+	// a member queued for both keeps their place for the target
+	queued := tx.Model(&models.Hold{}).Select("member_id").
+		Where("book_id = ? AND status IN ?", to, []string{models.HoldWaiting, models.HoldReady})
+	_, err = database.CloseHolds(tx, models.HoldCancelled, now, config.HoldPickupWindow, "book_id = ? AND member_id IN (?)", from, queued)
+	if err != nil {
+		return err
+	}
+	res = tx.Model(&models.Hold{}).Where("book_id = ?", from).Update("book_id", to)
+	if res.Error != nil {
+		return res.Error
+	}
+	moved["holds"] += res.RowsAffected
+
+	// a member who reviewed both keeps the target review
+	reviewers := tx.Model(&models.Review{}).Select("member_id").Where("book_id = ?", to)
+	if err := tx.Where("book_id = ? AND member_id IN (?)", from, reviewers).Delete(&models.Review{}).Error; err != nil {
+		return err
+	}
+	var totals struct{ N, Sum int64 }
+	err = tx.Model(&models.Review{}).Select("COUNT(*) AS n, COALESCE(SUM(rating), 0) AS sum").
+		Where("book_id = ?", from).Scan(&totals).Error
+	if err != nil {
+		return err
+	}
+	if totals.N > 0 {
+		res = tx.Model(&models.Review{}).Where("book_id = ?", from).Update("book_id", to)
+		if res.Error != nil {
+			return res.Error
+		}
+		moved["reviews"] += res.RowsAffected
+		if err := database.AdjustRating(tx, to, totals.N, totals.Sum); err != nil {
+			return err
+		}
+	}
+	if err := tx.Where("book_id = ?", from).Delete(&models.BookRating{}).Error; err != nil {
+		return err
+	}
+
+	return moveReadingListItems(tx, from, to, moved)
+}
+
+// moveReadingListItems swaps from for to on reading lists. A list that
+// already has to loses from's entry, and the gap it leaves is closed.
+func moveReadingListItems(tx *gorm.DB, from, to uuid.UUID, moved map[string]int64) error {
+	var items []models.ReadingListItem
+	if err := tx.Where("book_id = ?", from).Find(&items).Error; err != nil {
+		return err
+	}
+	for _, item := range items {
+		var both int64
+		err := tx.Model(&models.ReadingListItem{}).Where("list_id = ? AND book_id = ?", item.ListID, to).Count(&both).Error
+		if err != nil {
+			return err
+		}
+		if both > 0 {
+			if err := tx.Delete(&item).Error; err != nil {
+				return err
+			}
+			err = tx.Model(&models.ReadingListItem{}).
+				Where("list_id = ? AND position > ?", item.ListID, item.Position).
+				Update("position", gorm.Expr("position - 1")).Error
+		} else {
+			err = tx.Model(&item).Update("book_id", to).Error
+			moved["list_items"]++
+		}
+		if err != nil {
+			return err
+		}
+		if err := touchReadingList(tx, item.ListID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/enrichment"
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/hasan-kayan/TaskGo/utils"
+)
+
+// enricher answers POST /books/:id/enrich. main builds it from the
+// ENRICH_* variables (see enrichment.FromEnv); until then enrichment
+// is unavailable.
+var enricher enrichment.Provider
+
+// SetEnricher installs the metadata provider: main's, or a fixture in
+// tests.
+func SetEnricher(p enrichment.Provider) { enricher = p }
+
+// EnrichmentResult is what POST /books/:id/enrich found. Book is the
+// stored book once applied, or the book as it would be otherwise.
+type EnrichmentResult struct {
+	Source  string              `json:"source"`
+	Applied bool                `json:"applied"`
+	Changes models.FieldChanges `json:"changes"`
+	Book    *models.Book        `json:"book"`
+}
+
+// enrichField fills one empty book field from the metadata and reports
+// the change, or returns ok=false when there is nothing to fill.
+type enrichField struct {
+	name string
+	fill func(b *models.Book, m *enrichment.Metadata) (change models.FieldChange, ok bool)
+}
+
+var enrichFields = []enrichField{
+	{"description", func(b *models.Book, m *enrichment.Metadata) (models.FieldChange, bool) {
+		return fillString(&b.Description, m.Description)
+	}},
+	{"publisher", func(b *models.Book, m *enrichment.Metadata) (models.FieldChange, bool) {
+		if b.PublisherID != nil {
+			return models.FieldChange{}, false // a linked record names it
+		}
+		return fillString(&b.Publisher, m.Publisher)
+	}},
+	{"pages", func(b *models.Book, m *enrichment.Metadata) (models.FieldChange, bool) {
+		return fillInt(&b.Pages, m.Pages)
+	}},
+	{"year", func(b *models.Book, m *enrichment.Metadata) (models.FieldChange, bool) {
+		return fillInt(&b.Year, m.Year)
+	}},
+	{"cover_image_url", func(b *models.Book, m *enrichment.Metadata) (models.FieldChange, bool) {
+		return fillString(&b.CoverImageURL, m.CoverImageURL)
+	}},
+}
+
+func fillString(dst *string, v string) (models.FieldChange, bool) {
+	v = strings.TrimSpace(v)
+	if *dst != "" || v == "" {
+		return models.FieldChange{}, false
+	}
+	change := models.FieldChange{From: *dst, To: v}
+	*dst = v
+	return change, true
+}
+
+func fillInt(dst *int, v int) (models.FieldChange, bool) {
+	if *dst != 0 || v <= 0 {
+		return models.FieldChange{}, false
+	}
+	change := models.FieldChange{From: *dst, To: v}
+	*dst = v
+	return change, true
+}
+
+/* ────────────────────────────────────────────────────────── *
+   POST /books/:id/enrich  ─ fill empty fields by ISBN
+ * ────────────────────────────────────────────────────────── */
+
+// EnrichBook looks the book's ISBN up and proposes values for its empty
+// description, publisher, pages, year and cover URL – fields that are
+// already set are never overwritten. Without ?apply=true nothing is
+// written; the response is a preview of the changes. fields=pages,year
+// limits which fields are filled. Applying honours If-Match.
+func EnrichBook(c *gin.Context) {
+	bookID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "invalid UUID")
+		return
+	}
+	wanted := map[string]bool{}
+	if raw := c.Query("fields"); raw != "" {
+		known := map[string]bool{}
+		for _, f := range enrichFields {
+			known[f.name] = true
+		}
+		for _, name := range strings.Split(raw, ",") {
+			name = strings.TrimSpace(name)
+			if !known[name] {
+				utils.JSONError(c, http.StatusBadRequest, "fields must be description, publisher, pages, year or cover_image_url")
+				return
+			}
+			wanted[name] = true
+		}
+	}
+
+	var current models.Book
+	if err := database.DB.First(&current, "id = ?", bookID).Error; err != nil {
+		utils.JSONError(c, http.StatusNotFound, "book not found")
+		return
+	}
+	if current.ISBN == "" {
+		utils.JSONError(c, http.StatusUnprocessableEntity, "book has no ISBN to look up")
+		return
+	}
+
+	if enricher == nil {
+		utils.JSONError(c, http.StatusServiceUnavailable, "metadata enrichment is not configured")
+		return
+	}
+	meta, err := enricher.Lookup(c.Request.Context(), current.ISBN)
+	switch {
+	case errors.Is(err, enrichment.ErrNotFound):
+		utils.JSONError(c, http.StatusNotFound, "no metadata found for this ISBN")
+		return
+	case err != nil:
+		utils.JSONError(c, http.StatusBadGateway, "metadata lookup failed")
+		return
+	}
+
+	next := current
+	result := EnrichmentResult{Source: meta.Source, Changes: models.FieldChanges{}}
+	for _, f := range enrichFields {
+		if len(wanted) > 0 && !wanted[f.name] {
+			continue
+		}
+		before := next
+		change, ok := f.fill(&next, meta)
+		if !ok {
+			continue
+		}
+		// a value the book would reject (e.g. a malformed cover URL) is dropped
+		if err := utils.ValidateBook(&next); err != nil {
+			if _, bad := utils.FieldErrors(err)[f.name]; bad {
+				next = before
+				continue
+			}
+		}
+		result.Changes[f.name] = change
+	}
+
+	if c.Query("apply") != "true" || len(result.Changes) == 0 {
+		result.Book = &next
+		utils.JSONSuccess(c, http.StatusOK, result)
+		return
+	}
+	if ifMatchFailed(c, &current) {
+		return
+	}
+
+	fields, err := replaceBook(actorDB(c), &current, &next)
+	switch {
+	case len(fields) > 0:
+		utils.JSONFieldErrors(c, http.StatusUnprocessableEntity, "validation failed", fields)
+	case errors.Is(err, errVersionConflict):
+		var latest models.Book
+		database.DB.First(&latest, "id = ?", current.ID)
+		versionConflict(c, latest.ETag)
+	case err != nil:
+		utils.JSONError(c, http.StatusInternalServerError, "could not update book")
+	default:
+		result.Applied, result.Book = true, &next
+		c.Header("ETag", next.ETag)
+		utils.JSONSuccess(c, http.StatusOK, result)
+	}
+}
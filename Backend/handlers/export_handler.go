@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/hasan-kayan/TaskGo/utils"
+)
+
+/* ────────────────────────────────────────────────────────── *
+   GET /books/export  ─ streamed CSV / NDJSON / JSON download
+ * ────────────────────────────────────────────────────────── */
+
+const exportBatchSize = 500
+
+// exportColumns double as import headers, so an export can be fed
+// straight back into POST /books/import.
+var exportColumns = []string{
+	"id", "title", "author", "year", "isbn", "isbn10", "description",
+	"cover_image_url", "publisher", "type", "pages", "created_at", "updated_at",
+}
+
+// bookExporter writes one format; begin/end frame the document and
+// flush pushes anything buffered after each batch.
+type bookExporter interface {
+	begin() error
+	write(b *models.Book) error
+	flush()
+	end() error
+}
+
+// ExportBooks honours the GetBooks filters and sort, and walks the
+// result with keyset batches so memory stays flat however big the
+// catalogue is.
+func ExportBooks(c *gin.Context) {
+	format := c.DefaultQuery("format", "csv")
+
+	keys, err := parseSort(c.Query("sort"), defaultBookSort)
+	if err == nil {
+		err = checkBookFilters(c)
+	}
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var (
+		exp         bookExporter
+		contentType string
+	)
+	switch format {
+	case "csv":
+		exp, contentType = &csvExporter{w: csv.NewWriter(c.Writer)}, "text/csv; charset=utf-8"
+	case "ndjson":
+		exp, contentType = &ndjsonExporter{enc: json.NewEncoder(c.Writer)}, "application/x-ndjson"
+	case "json":
+		exp, contentType = &jsonArrayExporter{w: c.Writer}, "application/json"
+	default:
+		utils.JSONError(c, http.StatusBadRequest, "format must be csv, ndjson or json")
+		return
+	}
+
+	// exports can outlive the server-wide WriteTimeout
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	filename := fmt.Sprintf("books-%s.%s", time.Now().UTC().Format("20060102-150405"), format)
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Status(http.StatusOK)
+
+	filtered := applyBookFilters(database.DB, c).Session(&gorm.Session{})
+	if err := streamBooks(filtered, keys, exp, c.Writer.Flush); err != nil {
+		// headers are gone – all we can do is cut the download short
+		log.Printf("❌ export aborted: %v", err)
+		c.Abort()
+	}
+}
+
+func streamBooks(db *gorm.DB, keys []sortKey, exp bookExporter, flush func()) error {
+	if err := exp.begin(); err != nil {
+		return err
+	}
+
+	var last *models.Book
+	for {
+		q := orderBy(db, keys, false).Limit(exportBatchSize)
+		if last != nil {
+			q = seekAfter(q, keys, sortValues(keys, last), false)
+		}
+
+		var batch []models.Book
+		if err := q.Find(&batch).Error; err != nil {
+			return err
+		}
+		if err := withRatings(db, batch); err != nil {
+			return err
+		}
+		for i := range batch {
+			if err := exp.write(&batch[i]); err != nil {
+				return err
+			}
+		}
+		exp.flush()
+		flush()
+
+		if len(batch) < exportBatchSize {
+			break
+		}
+		last = &batch[len(batch)-1]
+	}
+
+	if err := exp.end(); err != nil {
+		return err
+	}
+	flush()
+	return nil
+}
+
+// sortValues extracts the typed keyset values (plus id) of b.
+func sortValues(keys []sortKey, b *models.Book) []any {
+	values := make([]any, 0, len(keys)+1)
+	for _, k := range keys {
+		f := bookSortFields[k.name]
+		v, _ := f.value(f.key(b))
+		values = append(values, v)
+	}
+	return append(values, b.ID)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   Formats
+ * ────────────────────────────────────────────────────────── */
+
+type csvExporter struct{ w *csv.Writer }
+
+func (e *csvExporter) begin() error {
+	return e.w.Write(exportColumns)
+}
+
+func (e *csvExporter) write(b *models.Book) error {
+	return e.w.Write([]string{
+		b.ID.String(), b.Title, b.Author, strconv.Itoa(b.Year), b.ISBN, b.ISBN10, b.Description,
+		b.CoverImageURL, b.Publisher, b.Type, strconv.Itoa(b.Pages),
+		b.CreatedAt.UTC().Format(time.RFC3339), b.UpdatedAt.UTC().Format(time.RFC3339),
+	})
+}
+
+func (e *csvExporter) flush() { e.w.Flush() }
+
+func (e *csvExporter) end() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+type ndjsonExporter struct{ enc *json.Encoder }
+
+func (e *ndjsonExporter) begin() error               { return nil }
+func (e *ndjsonExporter) write(b *models.Book) error { return e.enc.Encode(b) }
+func (e *ndjsonExporter) flush()                     {}
+func (e *ndjsonExporter) end() error                 { return nil }
+
+type jsonArrayExporter struct {
+	w     io.Writer
+	count int
+}
+
+func (e *jsonArrayExporter) begin() error {
+	_, err := io.WriteString(e.w, "[")
+	return err
+}
+
+func (e *jsonArrayExporter) write(b *models.Book) error {
+	raw, err := json.Marshal(b)
+	if err != nil {
+		return err
+	}
+	if e.count > 0 {
+		if _, err := io.WriteString(e.w, ","); err != nil {
+			return err
+		}
+	}
+	e.count++
+	_, err = e.w.Write(raw)
+	return err
+}
+
+func (e *jsonArrayExporter) flush() {}
+
+func (e *jsonArrayExporter) end() error {
+	_, err := io.WriteString(e.w, "]")
+	return err
+}
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/hasan-kayan/TaskGo/utils"
+)
+
+// FacetValue is one bucket of a facet and the number of books in it.
+type FacetValue struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// BookFacets holds the number of books matching the filters and, per
+// facet, its buckets: largest first, except decades (newest first) and
+// page ranges (shortest first).
+type BookFacets struct {
+	Total  int64                   `json:"total"`
+	Facets map[string][]FacetValue `json:"facets"`
+}
+
+// bookFacet groups books by an SQL expression over a books row. Rows
+// where the expression is NULL, or an empty text, are not counted.
+type bookFacet struct {
+	expr  string
+	text  bool   // expr is a text column that may hold ''
+	order string // bucket order; empty = by count
+}
+
+// pageBuckets are the page-count ranges of the "pages" facet, in order.
+var pageBuckets = []struct {
+	label    string
+	from, to int // to = 0: open-ended
+}{
+	{"1-99", 1, 99},
+	{"100-199", 100, 199},
+	{"200-299", 200, 299},
+	{"300-499", 300, 499},
+	{"500+", 500, 0},
+}
+
+var bookFacets = map[string]bookFacet{
+	"type":      {expr: "books.type", text: true},
+	"author":    {expr: "books.author", text: true},
+	"publisher": {expr: "books.publisher", text: true},
+	// year 0 means unknown
+	"decade": {
+		expr:  "CASE WHEN books.year > 0 THEN (books.year / 10) * 10 END",
+		order: "value DESC",
+	},
+	"pages": {expr: pagesBucketExpr(), order: pagesBucketOrder()},
+}
+
+// facetNames is the response order when ?facets= is not given.
+var facetNames = []string{"type", "author", "publisher", "decade", "pages"}
+
+/* ────────────────────────────────────────────────────────── *
+   GET /books/facets  ─ counts per type, author, publisher,
+                        decade and page bucket
+ * ────────────────────────────────────────────────────────── */
+
+// GetBookFacets takes the GET /books filters, so the counts are those of
+// the list the client is looking at:
+//
+//	?type=Programming&facets=author,decade
+//	?limit=10     (at most 10 buckets per facet)
+func GetBookFacets(c *gin.Context) {
+	if err := checkBookFilters(c); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	limit, _, err := parseLimitOffset(c)
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	names, err := parseFacetNames(c.Query("facets"))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	source, err := bookSource(c)
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	db := applyBookFilters(source.Model(&models.Book{}), c).Session(&gorm.Session{})
+	out := BookFacets{Facets: make(map[string][]FacetValue, len(names))}
+	if err := db.Count(&out.Total).Error; err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not count books")
+		return
+	}
+	for _, name := range names {
+		if out.Facets[name], err = countFacet(db, bookFacets[name], limit); err != nil {
+			utils.JSONError(c, http.StatusInternalServerError, "could not count books")
+			return
+		}
+	}
+	utils.JSONSuccess(c, http.StatusOK, out)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   helpers
+ * ────────────────────────────────────────────────────────── */
+
+func countFacet(db *gorm.DB, f bookFacet, limit int) ([]FacetValue, error) {
+	order := "count DESC, value"
+	if f.order != "" {
+		order = f.order
+	}
+	q := db.Select(f.expr + " AS value, COUNT(*) AS count").Where(f.expr + " IS NOT NULL")
+	if f.text {
+		q = q.Where(f.expr + " <> ''")
+	}
+	values := []FacetValue{}
+	err := q.Group(f.expr).
+		Order(order).
+		Limit(limit).
+		Scan(&values).Error
+	return values, err
+}
+
+func parseFacetNames(raw string) ([]string, error) {
+	if raw == "" {
+		return facetNames, nil
+	}
+	var names []string
+	seen := map[string]bool{}
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		if _, ok := bookFacets[name]; !ok {
+			return nil, fmt.Errorf("unknown facet %q; use %s", name, strings.Join(facetNames, ", "))
+		}
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// pagesBucketExpr labels a books row with its pageBuckets range; books
+// without a page count get NULL.
+func pagesBucketExpr() string {
+	var b strings.Builder
+	b.WriteString("CASE")
+	for _, r := range pageBuckets {
+		if r.to == 0 {
+			fmt.Fprintf(&b, " WHEN books.pages >= %d THEN '%s'", r.from, r.label)
+		} else {
+			fmt.Fprintf(&b, " WHEN books.pages BETWEEN %d AND %d THEN '%s'", r.from, r.to, r.label)
+		}
+	}
+	b.WriteString(" END")
+	return b.String()
+}
+
+// pagesBucketOrder sorts the page buckets from shortest to longest
+// rather than by their labels. It repeats the grouped expression, since
+// an output alias may not appear inside an ORDER BY expression.
+func pagesBucketOrder() string {
+	var b strings.Builder
+	b.WriteString("CASE " + pagesBucketExpr())
+	for i, r := range pageBuckets {
+		fmt.Fprintf(&b, " WHEN '%s' THEN %d", r.label, i)
+	}
+	b.WriteString(" END")
+	return b.String()
+}
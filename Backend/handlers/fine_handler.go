@@ -0,0 +1,328 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/hasan-kayan/TaskGo/utils"
+)
+
+// fineBalance is the unpaid, unwaived part of a fine.
+const fineBalance = "amount - paid - waived"
+
+// MemberFines is the body of GET /members/:id/fines.
+type MemberFines struct {
+	Balance int64         `json:"balance"` // total still owed
+	Fines   []models.Fine `json:"fines"`
+}
+
+// PaymentRequest is the body of POST /fines/:id/pay.
+type PaymentRequest struct {
+	Amount int64 `json:"amount"`
+}
+
+// WaiverRequest is the body of POST /fines/:id/waive. Without an
+// amount the whole balance is waived.
+type WaiverRequest struct {
+	Amount *int64 `json:"amount"`
+	Reason string `json:"reason"`
+}
+
+/* ────────────────────────────────────────────────────────── *
+   GET /members/:id/fines  ─ ?outstanding=true for unpaid only
+ * ────────────────────────────────────────────────────────── */
+
+func GetMemberFines(c *gin.Context) {
+	member, ok := loadMember(c)
+	if !ok {
+		return
+	}
+
+	db := database.DB.Where("member_id = ?", member.ID)
+	switch c.Query("outstanding") {
+	case "", "false":
+	case "true":
+		db = db.Where(fineBalance + " > 0")
+	default:
+		utils.JSONError(c, http.StatusBadRequest, "outstanding must be true or false")
+		return
+	}
+
+	out := MemberFines{Fines: []models.Fine{}}
+	if err := db.Order("created_at DESC").Order("id").Find(&out.Fines).Error; err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not list fines")
+		return
+	}
+	out.Balance, _ = memberBalance(database.DB, member.ID)
+	utils.JSONSuccess(c, http.StatusOK, out)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   GET /fines/:id
+ * ────────────────────────────────────────────────────────── */
+
+func GetFine(c *gin.Context) {
+	fine, ok := loadFine(c)
+	if !ok {
+		return
+	}
+	utils.JSONSuccess(c, http.StatusOK, fine)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   POST /fines/:id/pay  ─ full or part payment
+ * ────────────────────────────────────────────────────────── */
+
+func PayFine(c *gin.Context) {
+	fine, ok := loadFine(c)
+	if !ok {
+		return
+	}
+
+	var req PaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if msg := checkFineAmount(req.Amount, fine.Balance); msg != "" {
+		utils.JSONFieldErrors(c, http.StatusUnprocessableEntity, "validation failed", map[string]string{"amount": msg})
+		return
+	}
+
+	settleFine(c, fine, map[string]any{"paid": gorm.Expr("paid + ?", req.Amount)}, req.Amount)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   POST /fines/:id/waive  ─ forgive the balance (or part of it)
+ * ────────────────────────────────────────────────────────── */
+
+func WaiveFine(c *gin.Context) {
+	fine, ok := loadFine(c)
+	if !ok {
+		return
+	}
+
+	var req WaiverRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	amount := fine.Balance
+	if req.Amount != nil {
+		amount = *req.Amount
+	}
+	fields := map[string]string{}
+	if msg := checkFineAmount(amount, fine.Balance); msg != "" {
+		fields["amount"] = msg
+	}
+	req.Reason = strings.TrimSpace(req.Reason)
+	if req.Reason == "" {
+		fields["reason"] = "is required"
+	}
+	if len(fields) > 0 {
+		utils.JSONFieldErrors(c, http.StatusUnprocessableEntity, "validation failed", fields)
+		return
+	}
+
+	settleFine(c, fine, map[string]any{"waived": gorm.Expr("waived + ?", amount), "waiver_reason": req.Reason}, amount)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   GET /fine-policies  ─ PUT|DELETE /fine-policies/:type
+ * ────────────────────────────────────────────────────────── */
+
+// GetFinePolicies lists the policies; "*" is the default for book
+// types without their own.
+func GetFinePolicies(c *gin.Context) {
+	policies := []models.FinePolicy{}
+	if err := database.DB.Order("type").Find(&policies).Error; err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not list fine policies")
+		return
+	}
+	utils.JSONSuccess(c, http.StatusOK, policies)
+}
+
+// PutFinePolicy creates or replaces the policy for a book type. New
+// rates apply to fines from their next assessment; final fines keep
+// theirs.
+func PutFinePolicy(c *gin.Context) {
+	var policy models.FinePolicy
+	if err := c.ShouldBindJSON(&policy); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	policy.Type = strings.TrimSpace(c.Param("type"))
+	if err := utils.ValidateFinePolicy(&policy); err != nil {
+		utils.JSONFieldErrors(c, http.StatusUnprocessableEntity, "validation failed", utils.FieldErrors(err))
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		// one policy per type, whatever its case
+		var existing models.FinePolicy
+		if err := tx.Where("LOWER(type) = LOWER(?)", policy.Type).Limit(1).Find(&existing).Error; err != nil {
+			return err
+		}
+		if existing.Type != "" {
+			policy.Type, policy.CreatedAt = existing.Type, existing.CreatedAt
+		}
+		return tx.Save(&policy).Error
+	})
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not save fine policy")
+		return
+	}
+	utils.JSONSuccess(c, http.StatusOK, policy)
+}
+
+func DeleteFinePolicy(c *gin.Context) {
+	kind := strings.TrimSpace(c.Param("type"))
+	if kind == models.DefaultPolicyType {
+		utils.JSONError(c, http.StatusConflict, "the default policy cannot be deleted")
+		return
+	}
+	res := database.DB.Where("LOWER(type) = LOWER(?)", kind).Delete(&models.FinePolicy{})
+	if res.Error != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not delete fine policy")
+		return
+	}
+	if res.RowsAffected == 0 {
+		utils.JSONError(c, http.StatusNotFound, "fine policy not found")
+		return
+	}
+	utils.JSONSuccess(c, http.StatusOK, models.MessageResponse{Message: "fine policy deleted"})
+}
+
+/* ────────────────────────────────────────────────────────── *
+   GET /closed-days  ─ PUT|DELETE /closed-days/:date
+ * ────────────────────────────────────────────────────────── */
+
+// GetClosedDays lists the days fines skip, ?from= / ?to= (YYYY-MM-DD)
+// inclusive.
+func GetClosedDays(c *gin.Context) {
+	db := database.DB
+	for key, op := range map[string]string{"from": ">=", "to": "<="} {
+		if q := c.Query(key); q != "" {
+			if _, err := time.Parse(time.DateOnly, q); err != nil {
+				utils.JSONError(c, http.StatusBadRequest, key+" must be a date in the form YYYY-MM-DD")
+				return
+			}
+			db = db.Where("date "+op+" ?", q)
+		}
+	}
+
+	days := []models.ClosedDay{}
+	if err := db.Order("date").Find(&days).Error; err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not list closed days")
+		return
+	}
+	utils.JSONSuccess(c, http.StatusOK, days)
+}
+
+func PutClosedDay(c *gin.Context) {
+	var day models.ClosedDay
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&day); err != nil {
+			utils.JSONError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	day.Date = c.Param("date")
+	day.Name = strings.TrimSpace(day.Name)
+	if err := utils.ValidateClosedDay(&day); err != nil {
+		utils.JSONFieldErrors(c, http.StatusUnprocessableEntity, "validation failed", utils.FieldErrors(err))
+		return
+	}
+
+	if err := database.DB.Save(&day).Error; err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not save closed day")
+		return
+	}
+	utils.JSONSuccess(c, http.StatusOK, day)
+}
+
+func DeleteClosedDay(c *gin.Context) {
+	res := database.DB.Delete(&models.ClosedDay{}, "date = ?", c.Param("date"))
+	if res.Error != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not delete closed day")
+		return
+	}
+	if res.RowsAffected == 0 {
+		utils.JSONError(c, http.StatusNotFound, "closed day not found")
+		return
+	}
+	utils.JSONSuccess(c, http.StatusOK, models.MessageResponse{Message: "closed day deleted"})
+}
+
+/* ────────────────────────────────────────────────────────── *
+   helpers
+ * ────────────────────────────────────────────────────────── */
+
+// checkFineAmount explains why amount cannot be paid or waived against
+// balance, or returns "".
+func checkFineAmount(amount, balance int64) string {
+	switch {
+	case amount <= 0:
+		return "must be greater than 0"
+	case amount > balance:
+		return fmt.Sprintf("must not exceed the balance of %d", balance)
+	}
+	return ""
+}
+
+// settleFine applies a payment or waiver. The update is guarded by the
+// balance, so racing requests cannot take it below zero.
+func settleFine(c *gin.Context, fine *models.Fine, changes map[string]any, amount int64) {
+	res := database.DB.Model(&models.Fine{}).
+		Where("id = ? AND "+fineBalance+" >= ?", fine.ID, amount).
+		Updates(changes)
+	if res.Error != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not update fine")
+		return
+	}
+	if res.RowsAffected == 0 {
+		utils.JSONError(c, http.StatusConflict, "fine balance changed; reload and retry")
+		return
+	}
+
+	updated, _ := findFine(database.DB, fine.ID)
+	utils.JSONSuccess(c, http.StatusOK, updated)
+}
+
+// memberBalance sums what the member still owes.
+func memberBalance(db *gorm.DB, memberID uuid.UUID) (int64, error) {
+	var owed int64
+	err := db.Model(&models.Fine{}).
+		Select("COALESCE(SUM("+fineBalance+"), 0)").
+		Where("member_id = ?", memberID).
+		Scan(&owed).Error
+	return owed, err
+}
+
+func loadFine(c *gin.Context) (*models.Fine, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "invalid UUID")
+		return nil, false
+	}
+	fine, err := findFine(database.DB, id)
+	if err != nil {
+		utils.JSONError(c, http.StatusNotFound, "fine not found")
+		return nil, false
+	}
+	return fine, true
+}
+
+func findFine(db *gorm.DB, id uuid.UUID) (*models.Fine, error) {
+	var fine models.Fine
+	err := db.First(&fine, "id = ?", id).Error
+	return &fine, err
+}
@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/hasan-kayan/TaskGo/config"
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/hasan-kayan/TaskGo/utils"
+)
+
+// holdPosition numbers waiting holds within their book's queue, first
+// in line being 1; other holds get 0.
+const holdPosition = `CASE WHEN holds.status = 'waiting' THEN (SELECT COUNT(*) FROM holds q
+	WHERE q.book_id = holds.book_id AND q.status = 'waiting'
+	  AND (q.created_at < holds.created_at OR (q.created_at = holds.created_at AND q.id <= holds.id)))
+	ELSE 0 END AS position`
+
+var (
+	errCopyHeld   = errors.New("copy is held for another member")
+	errHoldClosed = errors.New("hold is no longer open")
+)
+
+// HoldRequest is the body of POST /books/:id/holds.
+type HoldRequest struct {
+	MemberID uuid.UUID `json:"member_id"`
+}
+
+/* ────────────────────────────────────────────────────────── *
+   GET /books/:id/holds  ─ the queue; ?status=open|all|<status>
+ * ────────────────────────────────────────────────────────── */
+
+func GetBookHolds(c *gin.Context) {
+	book, ok := loadBookParam(c)
+	if !ok {
+		return
+	}
+	listHolds(c, database.DB.Where("holds.book_id = ?", book.ID))
+}
+
+/* ────────────────────────────────────────────────────────── *
+   POST /books/:id/holds  ─ join the queue
+ * ────────────────────────────────────────────────────────── */
+
+// PlaceHold queues the member for the book. When a copy is free it is
+// set aside for the hold straight away.
+func PlaceHold(c *gin.Context) {
+	book, ok := loadBookParam(c)
+	if !ok {
+		return
+	}
+
+	var req HoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.MemberID == uuid.Nil {
+		utils.JSONFieldErrors(c, http.StatusUnprocessableEntity, "validation failed",
+			map[string]string{"member_id": "is required"})
+		return
+	}
+	if err := database.DB.First(&models.Member{}, "id = ?", req.MemberID).Error; err != nil {
+		utils.JSONFieldErrors(c, http.StatusUnprocessableEntity, "validation failed",
+			map[string]string{"member_id": "member not found"})
+		return
+	}
+
+	var copies int64
+	database.DB.Model(&models.Copy{}).Where("book_id = ?", book.ID).Count(&copies)
+	if copies == 0 {
+		utils.JSONError(c, http.StatusConflict, "book has no copies to hold")
+		return
+	}
+
+	hold := models.Hold{ID: uuid.New(), BookID: book.ID, MemberID: req.MemberID, Status: models.HoldWaiting}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&hold).Error; err != nil {
+			return err
+		}
+		return database.AssignFreeCopies(tx, book.ID, time.Now(), config.HoldPickupWindow)
+	})
+	if database.IsUniqueViolation(err) {
+		utils.JSONError(c, http.StatusConflict, "member already has an open hold on this book")
+		return
+	}
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not place hold")
+		return
+	}
+
+	created, _ := findHold(database.DB, hold.ID)
+	utils.JSONSuccess(c, http.StatusCreated, created)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   GET /holds/:id
+ * ────────────────────────────────────────────────────────── */
+
+func GetHold(c *gin.Context) {
+	hold, ok := loadHold(c)
+	if !ok {
+		return
+	}
+	utils.JSONSuccess(c, http.StatusOK, hold)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   POST /holds/:id/cancel  ─ leave the queue
+ * ────────────────────────────────────────────────────────── */
+
+// CancelHold closes an open hold; a copy it had set aside goes to the
+// next member in line.
+func CancelHold(c *gin.Context) {
+	hold, ok := loadHold(c)
+	if !ok {
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		n, err := database.CloseHolds(tx, models.HoldCancelled, time.Now(), config.HoldPickupWindow, "id = ?", hold.ID)
+		if err == nil && n == 0 {
+			err = errHoldClosed
+		}
+		return err
+	})
+	if errors.Is(err, errHoldClosed) {
+		utils.JSONError(c, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not cancel hold")
+		return
+	}
+
+	updated, _ := findHold(database.DB, hold.ID)
+	utils.JSONSuccess(c, http.StatusOK, updated)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   GET /members/:id/holds  ─ ?status=open|all|<status>
+ * ────────────────────────────────────────────────────────── */
+
+func GetMemberHolds(c *gin.Context) {
+	member, ok := loadMember(c)
+	if !ok {
+		return
+	}
+	listHolds(c, database.DB.Where("holds.member_id = ?", member.ID))
+}
+
+/* ────────────────────────────────────────────────────────── *
+   helpers
+ * ────────────────────────────────────────────────────────── */
+
+// listHolds pages the holds matched by db in queue order. ?status=
+// defaults to open (waiting or ready); "all" lifts the filter.
+func listHolds(c *gin.Context, db *gorm.DB) {
+	limit, offset, err := parseLimitOffset(c)
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	db = db.Model(&models.Hold{})
+	switch status := c.DefaultQuery("status", "open"); status {
+	case "all":
+	case "open":
+		db = db.Where("holds.status IN ?", []string{models.HoldWaiting, models.HoldReady})
+	case models.HoldWaiting, models.HoldReady, models.HoldFulfilled, models.HoldCancelled, models.HoldExpired:
+		db = db.Where("holds.status = ?", status)
+	default:
+		utils.JSONError(c, http.StatusBadRequest,
+			"status must be open, all, waiting, ready, fulfilled, cancelled or expired")
+		return
+	}
+	db = db.Session(&gorm.Session{})
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not list holds")
+		return
+	}
+
+	holds := []models.Hold{}
+	err = db.Select("holds.*, " + holdPosition).
+		Order("holds.created_at").Order("holds.id").
+		Limit(limit).Offset(offset).
+		Find(&holds).Error
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not list holds")
+		return
+	}
+
+	meta := &PageMeta{Total: total, Limit: limit, Offset: offset, Sort: "created_at"}
+	utils.JSONSuccessMeta(c, http.StatusOK, holds, meta)
+}
+
+func loadHold(c *gin.Context) (*models.Hold, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "invalid UUID")
+		return nil, false
+	}
+	hold, err := findHold(database.DB, id)
+	if err != nil {
+		utils.JSONError(c, http.StatusNotFound, "hold not found")
+		return nil, false
+	}
+	return hold, true
+}
+
+func findHold(db *gorm.DB, id uuid.UUID) (*models.Hold, error) {
+	var hold models.Hold
+	err := db.Model(&models.Hold{}).
+		Select("holds.*, "+holdPosition).
+		First(&hold, "holds.id = ?", id).Error
+	return &hold, err
+}
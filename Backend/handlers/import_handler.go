@@ -0,0 +1,383 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/hasan-kayan/TaskGo/utils"
+)
+
+/* ────────────────────────────────────────────────────────── *
+   POST /books/import  ─ CSV / TSV catalogue import
+ * ────────────────────────────────────────────────────────── */
+
+const (
+	importBatchSize = 200  // rows per INSERT transaction
+	importMaxErrors = 1000 // row errors kept in the report
+)
+
+// importColumnAliases maps normalised header names to Book JSON fields.
+// Anything not listed here (or in a client mapping) is ignored.
+var importColumnAliases = map[string]string{
+	"title":            "title",
+	"name":             "title",
+	"author":           "author",
+	"authors":          "author",
+	"year":             "year",
+	"published":        "year",
+	"publication_year": "year",
+	"isbn":             "isbn",
+	"isbn13":           "isbn",
+	"isbn_13":          "isbn",
+	"isbn10":           "isbn",
+	"isbn_10":          "isbn",
+	"description":      "description",
+	"summary":          "description",
+	"cover_image_url":  "cover_image_url",
+	"cover":            "cover_image_url",
+	"cover_url":        "cover_image_url",
+	"publisher":        "publisher",
+	"type":             "type",
+	"genre":            "type",
+	"category":         "type",
+	"pages":            "pages",
+	"page_count":       "pages",
+}
+
+// ImportRowError describes why one data row was rejected. Row is the
+// file line the record starts on (the header is line 1).
+type ImportRowError struct {
+	Row    int               `json:"row"`
+	Error  string            `json:"error,omitempty"`
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// ImportReport is returned for both dry runs and real imports.
+type ImportReport struct {
+	DryRun          bool              `json:"dry_run"`
+	Columns         map[string]string `json:"columns"`
+	IgnoredColumns  []string          `json:"ignored_columns,omitempty"`
+	Rows            int               `json:"rows"`
+	Valid           int               `json:"valid"`
+	Imported        int               `json:"imported"`
+	Failed          int               `json:"failed"`
+	Errors          []ImportRowError  `json:"errors"`
+	ErrorsTruncated bool              `json:"errors_truncated,omitempty"`
+}
+
+func (r *ImportReport) reject(e ImportRowError) {
+	r.Failed++
+	if len(r.Errors) < importMaxErrors {
+		r.Errors = append(r.Errors, e)
+	} else {
+		r.ErrorsTruncated = true
+	}
+}
+
+// ImportBooks streams a CSV/TSV upload row by row; the file is never
+// held in memory. Accepted bodies:
+//
+//	multipart/form-data   fields "mapping" / "dry_run" (optional, before
+//	                      the file) and the file itself in "file"
+//	text/csv, text/tab-separated-values   the raw file as body
+//
+// Query parameters: dry_run=true validates without writing, format=tsv
+// forces tab separation, mapping={"Book Name":"title"} overrides the
+// header → field mapping.
+func ImportBooks(c *gin.Context) {
+	opts := importOptions{
+		dryRun:  c.Query("dry_run") == "true",
+		tsv:     c.Query("format") == "tsv",
+		mapping: map[string]string{},
+	}
+	if err := opts.addMapping(c.Query("mapping")); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	switch mediaType {
+	case "text/csv":
+		runImport(c, c.Request.Body, opts)
+	case "text/tab-separated-values":
+		opts.tsv = true
+		runImport(c, c.Request.Body, opts)
+	case "multipart/form-data":
+		importMultipart(c, opts)
+	default:
+		utils.JSONError(c, http.StatusUnsupportedMediaType,
+			"send multipart/form-data with a \"file\" part, text/csv or text/tab-separated-values")
+	}
+}
+
+type importOptions struct {
+	dryRun  bool
+	tsv     bool
+	mapping map[string]string // normalised header → Book field
+}
+
+func (o *importOptions) addMapping(raw string) error {
+	if raw == "" {
+		return nil
+	}
+	var m map[string]string
+	if err := json.Unmarshal([]byte(raw), &m); err != nil {
+		return errors.New("mapping must be a JSON object of column → field")
+	}
+	for col, field := range m {
+		if !isImportField(field) {
+			return errors.New("mapping targets unknown field " + strconv.Quote(field))
+		}
+		o.mapping[normaliseHeader(col)] = field
+	}
+	return nil
+}
+
+func isImportField(field string) bool {
+	for _, f := range importColumnAliases {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// importMultipart walks the parts in order, so small form fields sent
+// ahead of the file still apply while the file itself is streamed.
+func importMultipart(c *gin.Context, opts importOptions) {
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "malformed multipart body")
+		return
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			utils.JSONError(c, http.StatusBadRequest, `missing "file" part`)
+			return
+		}
+		if err != nil {
+			utils.JSONError(c, http.StatusBadRequest, "malformed multipart body")
+			return
+		}
+
+		switch part.FormName() {
+		case "file":
+			if strings.EqualFold(filepath.Ext(part.FileName()), ".tsv") ||
+				part.Header.Get("Content-Type") == "text/tab-separated-values" {
+				opts.tsv = true
+			}
+			runImport(c, part, opts)
+			return
+		case "mapping":
+			raw, _ := io.ReadAll(io.LimitReader(part, 64<<10))
+			if err := opts.addMapping(string(raw)); err != nil {
+				utils.JSONError(c, http.StatusBadRequest, err.Error())
+				return
+			}
+		case "dry_run":
+			raw, _ := io.ReadAll(io.LimitReader(part, 16))
+			opts.dryRun = opts.dryRun || strings.TrimSpace(string(raw)) == "true"
+		}
+	}
+}
+
+/* ────────────────────────────────────────────────────────── *
+   Row pipeline: header → rows → validate → batched INSERT
+ * ────────────────────────────────────────────────────────── */
+
+func runImport(c *gin.Context, src io.Reader, opts importOptions) {
+	r := csv.NewReader(src)
+	r.FieldsPerRecord = -1 // tolerate ragged rows; missing cells are empty
+	r.ReuseRecord = true
+	r.TrimLeadingSpace = true
+	if opts.tsv {
+		r.Comma = '\t'
+		r.LazyQuotes = true
+	}
+
+	header, err := r.Read()
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "could not read header row")
+		return
+	}
+
+	report := &ImportReport{DryRun: opts.dryRun, Columns: map[string]string{}, Errors: []ImportRowError{}}
+	fields := make([]string, len(header)) // column index → Book field ("" = ignored)
+	seen := map[string]bool{}
+	for i, col := range header {
+		col = strings.TrimPrefix(col, "\ufeff") // Excel BOM
+		key := normaliseHeader(col)
+		field, ok := opts.mapping[key]
+		if !ok {
+			field, ok = importColumnAliases[key]
+		}
+		if !ok || seen[field] {
+			report.IgnoredColumns = append(report.IgnoredColumns, col)
+			continue
+		}
+		seen[field] = true
+		fields[i] = field
+		report.Columns[col] = field
+	}
+	if !seen["title"] || !seen["author"] {
+		utils.JSONFieldErrors(c, http.StatusUnprocessableEntity, "header row must map to title and author",
+			map[string]string{"columns": "found " + strings.Join(header, ", ")})
+		return
+	}
+
+	var (
+		batch     []models.Book
+		batchRows []int
+	)
+	flush := func() {
+		if !opts.dryRun && len(batch) > 0 {
+			insertImportBatch(actorDB(c), batch, batchRows, report)
+		}
+		batch, batchRows = batch[:0], batchRows[:0]
+	}
+
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		report.Rows++
+		if err != nil {
+			var perr *csv.ParseError
+			row := 0
+			if errors.As(err, &perr) {
+				row = perr.StartLine
+			}
+			report.reject(ImportRowError{Row: row, Error: err.Error()})
+			continue
+		}
+		row, _ := r.FieldPos(0) // physical line – quoted cells may span lines
+		if isBlankRecord(record) {
+			report.Rows--
+			continue
+		}
+
+		book, fieldErrs := bookFromRecord(record, fields)
+		if len(fieldErrs) == 0 {
+			if err := utils.ValidateBook(&book); err != nil {
+				fieldErrs = utils.FieldErrors(err)
+			}
+		}
+		if len(fieldErrs) > 0 {
+			report.reject(ImportRowError{Row: row, Fields: fieldErrs})
+			continue
+		}
+
+		report.Valid++
+		batch = append(batch, book)
+		batchRows = append(batchRows, row)
+		if len(batch) >= importBatchSize {
+			flush()
+		}
+	}
+	flush()
+
+	status := http.StatusOK
+	if !opts.dryRun && report.Imported > 0 {
+		status = http.StatusCreated
+	}
+	utils.JSONSuccess(c, status, report)
+}
+
+// insertImportBatch writes a batch in one transaction. If the batch
+// fails as a whole, rows are retried one by one so the report can
+// blame the exact offender(s).
+func insertImportBatch(db *gorm.DB, batch []models.Book, rows []int, report *ImportReport) {
+	err := db.Transaction(func(tx *gorm.DB) error {
+		return tx.Create(&batch).Error
+	})
+	if err == nil {
+		report.Imported += len(batch)
+		return
+	}
+
+	for i := range batch {
+		if err := db.Create(&batch[i]).Error; err != nil {
+			report.Valid--
+			if database.IsISBNViolation(err) {
+				report.reject(ImportRowError{Row: rows[i], Fields: map[string]string{"isbn": "already exists"}})
+				continue
+			}
+			report.reject(ImportRowError{Row: rows[i], Error: "could not save row: " + err.Error()})
+			continue
+		}
+		report.Imported++
+	}
+}
+
+func bookFromRecord(record []string, fields []string) (models.Book, map[string]string) {
+	book := models.Book{ID: uuid.New()}
+	errs := map[string]string{}
+
+	for i, field := range fields {
+		if field == "" || i >= len(record) {
+			continue
+		}
+		val := strings.TrimSpace(record[i])
+		switch field {
+		case "title":
+			book.Title = val
+		case "author":
+			book.Author = val
+		case "isbn":
+			book.ISBN = val
+		case "description":
+			book.Description = val
+		case "cover_image_url":
+			book.CoverImageURL = val
+		case "publisher":
+			book.Publisher = val
+		case "type":
+			book.Type = val
+		case "year", "pages":
+			if val == "" {
+				continue
+			}
+			n, err := strconv.Atoi(val)
+			if err != nil {
+				errs[field] = "must be a whole number"
+				continue
+			}
+			if field == "year" {
+				book.Year = n
+			} else {
+				book.Pages = n
+			}
+		}
+	}
+	return book, errs
+}
+
+func normaliseHeader(h string) string {
+	h = strings.ToLower(strings.TrimSpace(h))
+	return strings.NewReplacer(" ", "_", "-", "_", ".", "_").Replace(h)
+}
+
+func isBlankRecord(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,352 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/hasan-kayan/TaskGo/config"
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/hasan-kayan/TaskGo/utils"
+)
+
+// LOAN_PERIOD_DAYS is how long a checkout or renewal runs (default 21);
+// LOAN_MAX_RENEWALS caps renewals per loan (default 2, 0 disables).
+var (
+	loanPeriod      = time.Duration(config.Int("LOAN_PERIOD_DAYS", 21, 1)) * 24 * time.Hour
+	loanMaxRenewals = config.Int("LOAN_MAX_RENEWALS", 2, 0)
+)
+
+var (
+	errCopyOnLoan    = errors.New("copy is already on loan")
+	errLoanReturned  = errors.New("loan has already been returned")
+	errRenewalsSpent = errors.New("renewal limit reached")
+	errLoanOverdue   = errors.New("loan is overdue; return it first")
+)
+
+// CheckoutRequest is the body of POST /loans. The copy is given by id
+// or by barcode.
+type CheckoutRequest struct {
+	CopyID   *uuid.UUID `json:"copy_id"`
+	Barcode  string     `json:"barcode"`
+	MemberID uuid.UUID  `json:"member_id"`
+}
+
+// ReturnResult is a returned loan plus the hold its copy was set aside
+// for, if anyone was waiting, and the fine it closed with, if late.
+type ReturnResult struct {
+	models.Loan
+	Hold *models.Hold `json:"hold,omitempty"`
+	Fine *models.Fine `json:"fine,omitempty"`
+}
+
+/* ────────────────────────────────────────────────────────── *
+   GET /loans  ─ ?member_id= ?book_id= ?copy_id= ?active= ?overdue=
+ * ────────────────────────────────────────────────────────── */
+
+// GetLoans lists loans, most recent checkout first.
+func GetLoans(c *gin.Context) {
+	db := database.DB
+	for _, key := range []string{"member_id", "book_id", "copy_id"} {
+		if q := c.Query(key); q != "" {
+			db = db.Where("loans."+key+" = ?", q)
+		}
+	}
+	listLoans(c, db)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   GET /loans/:id
+ * ────────────────────────────────────────────────────────── */
+
+func GetLoan(c *gin.Context) {
+	loan, ok := loadLoan(c)
+	if !ok {
+		return
+	}
+	utils.JSONSuccess(c, http.StatusOK, loan)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   POST /loans  ─ checkout
+ * ────────────────────────────────────────────────────────── */
+
+func Checkout(c *gin.Context) {
+	var req CheckoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	item, fields := resolveCheckout(database.DB, &req)
+	if len(fields) > 0 {
+		utils.JSONFieldErrors(c, http.StatusUnprocessableEntity, "validation failed", fields)
+		return
+	}
+
+	now := time.Now()
+	loan := models.Loan{
+		CopyID:       item.ID,
+		BookID:       item.BookID,
+		MemberID:     req.MemberID,
+		CheckedOutAt: now,
+		DueAt:        now.Add(loanPeriod),
+	}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var active int64
+		if err := tx.Model(&models.Loan{}).Where("copy_id = ? AND returned_at IS NULL", item.ID).Count(&active).Error; err != nil {
+			return err
+		}
+		if active > 0 {
+			return errCopyOnLoan
+		}
+		var held models.Hold
+		if err := tx.Where("copy_id = ? AND status = ?", item.ID, models.HoldReady).Limit(1).Find(&held).Error; err != nil {
+			return err
+		}
+		if held.ID != uuid.Nil && held.MemberID != req.MemberID {
+			return errCopyHeld
+		}
+		if err := tx.Create(&loan).Error; err != nil {
+			return err
+		}
+		// the borrower's hold on the book is met by this loan; a copy it
+		// had set aside elsewhere goes to the next in line
+		_, err := database.CloseHolds(tx, models.HoldFulfilled, now, config.HoldPickupWindow,
+			"book_id = ? AND member_id = ?", item.BookID, req.MemberID)
+		return err
+	})
+	if errors.Is(err, errCopyOnLoan) || database.IsUniqueViolation(err) {
+		utils.JSONError(c, http.StatusConflict, errCopyOnLoan.Error())
+		return
+	}
+	if errors.Is(err, errCopyHeld) {
+		utils.JSONError(c, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not check out copy")
+		return
+	}
+
+	created, _ := findLoan(database.DB, loan.ID)
+	utils.JSONSuccess(c, http.StatusCreated, created)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   POST /loans/:id/return
+ * ────────────────────────────────────────────────────────── */
+
+// ReturnLoan closes the loan and, in the same transaction, settles its
+// fine and sets the copy aside for the first waiting hold on its book.
+func ReturnLoan(c *gin.Context) {
+	loan, ok := loadLoan(c)
+	if !ok {
+		return
+	}
+
+	var (
+		hold *models.Hold
+		fine *models.Fine
+	)
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		res := tx.Model(&models.Loan{}).
+			Where("id = ? AND returned_at IS NULL", loan.ID).
+			Update("returned_at", now)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return errLoanReturned
+		}
+		returned := *loan
+		returned.ReturnedAt = &now
+		var err error
+		if fine, err = database.AssessFine(tx, &returned, now); err != nil {
+			return err
+		}
+		hold, err = database.AssignNextHold(tx, &models.Copy{ID: loan.CopyID, BookID: loan.BookID}, now, config.HoldPickupWindow)
+		return err
+	})
+	if !renderLoanWrite(c, err, "could not return loan") {
+		return
+	}
+
+	updated, _ := findLoan(database.DB, loan.ID)
+	utils.JSONSuccess(c, http.StatusOK, ReturnResult{Loan: *updated, Hold: hold, Fine: fine})
+}
+
+/* ────────────────────────────────────────────────────────── *
+   POST /loans/:id/renew  ─ another loan period from today
+ * ────────────────────────────────────────────────────────── */
+
+// RenewLoan sets the due date one loan period from now (never earlier
+// than it already was), up to LOAN_MAX_RENEWALS times. Overdue loans
+// cannot be renewed, so their fine stays tied to the original due date.
+func RenewLoan(c *gin.Context) {
+	loan, ok := loadLoan(c)
+	if !ok {
+		return
+	}
+
+	due := time.Now().Add(loanPeriod)
+	if due.Before(loan.DueAt) {
+		due = loan.DueAt
+	}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		switch {
+		case !loan.Active():
+			return errLoanReturned
+		case loan.Renewals >= loanMaxRenewals:
+			return errRenewalsSpent
+		case loan.Overdue(time.Now()):
+			return errLoanOverdue
+		}
+		// guarded by renewals/returned_at so racing renewals count once
+		res := tx.Model(&models.Loan{}).
+			Where("id = ? AND returned_at IS NULL AND renewals = ?", loan.ID, loan.Renewals).
+			Updates(map[string]any{"due_at": due, "renewals": loan.Renewals + 1})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return errLoanReturned
+		}
+		return nil
+	})
+	if !renderLoanWrite(c, err, "could not renew loan") {
+		return
+	}
+
+	updated, _ := findLoan(database.DB, loan.ID)
+	utils.JSONSuccess(c, http.StatusOK, updated)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   helpers
+ * ────────────────────────────────────────────────────────── */
+
+// listLoans pages the loans matched by db, honouring ?active= and
+// ?overdue=true.
+func listLoans(c *gin.Context, db *gorm.DB) {
+	limit, offset, err := parseLimitOffset(c)
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	db = db.Model(&models.Loan{})
+	switch c.Query("active") {
+	case "":
+	case "true":
+		db = db.Where("loans.returned_at IS NULL")
+	case "false":
+		db = db.Where("loans.returned_at IS NOT NULL")
+	default:
+		utils.JSONError(c, http.StatusBadRequest, "active must be true or false")
+		return
+	}
+	if c.Query("overdue") == "true" {
+		db = db.Where("loans.returned_at IS NULL AND loans.due_at < ?", time.Now())
+	}
+	db = db.Session(&gorm.Session{})
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not list loans")
+		return
+	}
+
+	loans := []models.Loan{}
+	err = db.Preload("Copy", withOnLoan).
+		Order("loans.checked_out_at DESC").Order("loans.id").
+		Limit(limit).Offset(offset).
+		Find(&loans).Error
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not list loans")
+		return
+	}
+
+	meta := &PageMeta{Total: total, Limit: limit, Offset: offset, Sort: "-checked_out_at"}
+	utils.JSONSuccessMeta(c, http.StatusOK, loans, meta)
+}
+
+// resolveCheckout finds the copy and member named by req, reporting
+// anything missing per field.
+func resolveCheckout(db *gorm.DB, req *CheckoutRequest) (*models.Copy, map[string]string) {
+	fields := map[string]string{}
+
+	var item models.Copy
+	switch {
+	case req.CopyID != nil:
+		if err := db.First(&item, "id = ?", *req.CopyID).Error; err != nil {
+			fields["copy_id"] = "copy not found"
+		}
+	case strings.TrimSpace(req.Barcode) != "":
+		if err := db.First(&item, "barcode = ?", strings.TrimSpace(req.Barcode)).Error; err != nil {
+			fields["barcode"] = "copy not found"
+		}
+	default:
+		fields["copy_id"] = "copy_id or barcode is required"
+	}
+	if item.ID != uuid.Nil {
+		var live int64
+		db.Model(&models.Book{}).Where("id = ?", item.BookID).Count(&live)
+		if live == 0 {
+			fields["copy_id"] = "the copy's book is not in the catalogue"
+		}
+	}
+
+	if req.MemberID == uuid.Nil {
+		fields["member_id"] = "is required"
+	} else if err := db.First(&models.Member{}, "id = ?", req.MemberID).Error; err != nil {
+		fields["member_id"] = "member not found"
+	}
+	return &item, fields
+}
+
+// renderLoanWrite answers the loan state errors and reports whether the
+// write succeeded.
+func renderLoanWrite(c *gin.Context, err error, failure string) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, errLoanReturned), errors.Is(err, errRenewalsSpent), errors.Is(err, errLoanOverdue):
+		utils.JSONError(c, http.StatusConflict, err.Error())
+	default:
+		utils.JSONError(c, http.StatusInternalServerError, failure)
+	}
+	return false
+}
+
+func loadLoan(c *gin.Context) (*models.Loan, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "invalid UUID")
+		return nil, false
+	}
+	loan, err := findLoan(database.DB, id)
+	if err != nil {
+		utils.JSONError(c, http.StatusNotFound, "loan not found")
+		return nil, false
+	}
+	return loan, true
+}
+
+func findLoan(db *gorm.DB, id uuid.UUID) (*models.Loan, error) {
+	var loan models.Loan
+	err := db.Preload("Copy", withOnLoan).First(&loan, "id = ?", id).Error
+	return &loan, err
+}
+
+// withOnLoan fills Copy.OnLoan on preloaded copies.
+func withOnLoan(db *gorm.DB) *gorm.DB {
+	return db.Select("copies.*, " + copyOnLoan)
+}
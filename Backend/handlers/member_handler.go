@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/hasan-kayan/TaskGo/config"
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/hasan-kayan/TaskGo/utils"
+)
+
+// memberActiveLoans counts the copies a member currently has out.
+const memberActiveLoans = `(SELECT COUNT(*) FROM loans l
+	WHERE l.member_id = members.id AND l.returned_at IS NULL) AS active_loans`
+
+const msgEmailTaken = "a member with this email already exists"
+
+/* ────────────────────────────────────────────────────────── *
+   GET /members  ─ list, ?name= / ?email= filters, alphabetical
+ * ────────────────────────────────────────────────────────── */
+
+func GetMembers(c *gin.Context) {
+	limit, offset, err := parseLimitOffset(c)
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	db := database.DB.Model(&models.Member{})
+	if q := c.Query("name"); q != "" {
+		db = db.Where("LOWER(name) LIKE ?", "%"+strings.ToLower(q)+"%")
+	}
+	if q := c.Query("email"); q != "" {
+		db = db.Where("email = ?", strings.ToLower(strings.TrimSpace(q)))
+	}
+	db = db.Session(&gorm.Session{})
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not list members")
+		return
+	}
+
+	members := []models.Member{}
+	err = db.Select("members.*, " + memberActiveLoans).
+		Order("LOWER(name)").Order("id").
+		Limit(limit).Offset(offset).
+		Find(&members).Error
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not list members")
+		return
+	}
+
+	meta := &PageMeta{Total: total, Limit: limit, Offset: offset, Sort: "name"}
+	utils.JSONSuccessMeta(c, http.StatusOK, members, meta)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   GET /members/:id
+ * ────────────────────────────────────────────────────────── */
+
+func GetMember(c *gin.Context) {
+	member, ok := loadMember(c)
+	if !ok {
+		return
+	}
+	utils.JSONSuccess(c, http.StatusOK, member)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   POST /members  ─ register
+ * ────────────────────────────────────────────────────────── */
+
+func CreateMember(c *gin.Context) {
+	var payload models.Member
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	payload.ID = uuid.New()
+	payload.Email = strings.ToLower(strings.TrimSpace(payload.Email))
+
+	if err := utils.ValidateMember(&payload); err != nil {
+		utils.JSONFieldErrors(c, http.StatusUnprocessableEntity, "validation failed", utils.FieldErrors(err))
+		return
+	}
+	if err := database.DB.Create(&payload).Error; err != nil {
+		if database.IsUniqueViolation(err) {
+			utils.JSONError(c, http.StatusConflict, msgEmailTaken)
+			return
+		}
+		utils.JSONError(c, http.StatusInternalServerError, "could not create member")
+		return
+	}
+	utils.JSONSuccess(c, http.StatusCreated, payload)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   PUT /members/:id  ─ full replacement of name / email / phone
+ * ────────────────────────────────────────────────────────── */
+
+func UpdateMember(c *gin.Context) {
+	current, ok := loadMember(c)
+	if !ok {
+		return
+	}
+
+	var next models.Member
+	if err := c.ShouldBindJSON(&next); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	next.ID, next.CreatedAt = current.ID, current.CreatedAt
+	next.Name = strings.TrimSpace(next.Name)
+	next.Email = strings.ToLower(strings.TrimSpace(next.Email))
+
+	if err := utils.ValidateMember(&next); err != nil {
+		utils.JSONFieldErrors(c, http.StatusUnprocessableEntity, "validation failed", utils.FieldErrors(err))
+		return
+	}
+	err := database.DB.Model(&models.Member{ID: current.ID}).
+		Select("name", "email", "phone").
+		Updates(&next).Error
+	if err != nil {
+		if database.IsUniqueViolation(err) {
+			utils.JSONError(c, http.StatusConflict, msgEmailTaken)
+			return
+		}
+		utils.JSONError(c, http.StatusInternalServerError, "could not update member")
+		return
+	}
+
+	updated, _ := findMember(database.DB, current.ID)
+	utils.JSONSuccess(c, http.StatusOK, updated)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   DELETE /members/:id  ─ only without active loans or unpaid fines
+ * ────────────────────────────────────────────────────────── */
+
+func DeleteMember(c *gin.Context) {
+	member, ok := loadMember(c)
+	if !ok {
+		return
+	}
+	if member.ActiveLoans > 0 {
+		utils.JSONError(c, http.StatusConflict,
+			fmt.Sprintf("member has %d active loan(s); return them first", member.ActiveLoans))
+		return
+	}
+	if owed, _ := memberBalance(database.DB, member.ID); owed > 0 {
+		utils.JSONError(c, http.StatusConflict,
+			fmt.Sprintf("member owes %d in fines; settle them first", owed))
+		return
+	}
+
+	// past loans and holds stay for the record and keep their member id;
+	// open holds are cancelled so the queue moves on, and reading lists go
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if _, err := database.CloseHolds(tx, models.HoldCancelled, time.Now(), config.HoldPickupWindow,
+			"member_id = ?", member.ID); err != nil {
+			return err
+		}
+		lists := tx.Model(&models.ReadingList{}).Select("id").Where("member_id = ?", member.ID)
+		if err := tx.Where("list_id IN (?)", lists).Delete(&models.ReadingListItem{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("member_id = ?", member.ID).Delete(&models.ReadingList{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Member{ID: member.ID}).Error
+	})
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not delete member")
+		return
+	}
+	utils.JSONSuccess(c, http.StatusOK, models.MessageResponse{Message: "member deleted"})
+}
+
+/* ────────────────────────────────────────────────────────── *
+   GET /members/:id/loans  ─ ?active=true for current loans
+ * ────────────────────────────────────────────────────────── */
+
+func GetMemberLoans(c *gin.Context) {
+	member, ok := loadMember(c)
+	if !ok {
+		return
+	}
+	listLoans(c, database.DB.Where("loans.member_id = ?", member.ID))
+}
+
+/* ────────────────────────────────────────────────────────── *
+   helpers
+ * ────────────────────────────────────────────────────────── */
+
+func loadMember(c *gin.Context) (*models.Member, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "invalid UUID")
+		return nil, false
+	}
+	member, err := findMember(database.DB, id)
+	if err != nil {
+		utils.JSONError(c, http.StatusNotFound, "member not found")
+		return nil, false
+	}
+	return member, true
+}
+
+func findMember(db *gorm.DB, id uuid.UUID) (*models.Member, error) {
+	var member models.Member
+	err := db.Model(&models.Member{}).
+		Select("members.*, "+memberActiveLoans).
+		First(&member, "members.id = ?", id).Error
+	return &member, err
+}
@@ -0,0 +1,399 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/hasan-kayan/TaskGo/models"
+)
+
+/*───────────────────────────────────────────────────────────────*
+|                     Limits & sortable fields                  |
+*───────────────────────────────────────────────────────────────*/
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 200
+	defaultBookSort  = "-created_at"
+	defaultTrashSort = "-deleted_at"
+)
+
+// sortField describes a column clients may sort /books by.
+// key renders a row's value into the cursor, parse turns it back
+// into a typed SQL argument so comparisons behave like the column.
+// A nullable column sorts NULL after every value (before them when
+// descending); its key is "" for NULL.
+type sortField struct {
+	column   string
+	key      func(b *models.Book) string
+	parse    func(s string) (any, error)
+	nullable bool
+}
+
+var bookSortFields = map[string]sortField{
+	"title":      {"title", func(b *models.Book) string { return b.Title }, parseString, false},
+	"author":     {"author", func(b *models.Book) string { return b.Author }, parseString, false},
+	"year":       {"year", func(b *models.Book) string { return strconv.Itoa(b.Year) }, parseInt, false},
+	"pages":      {"pages", func(b *models.Book) string { return strconv.Itoa(b.Pages) }, parseInt, false},
+	"created_at": {"created_at", func(b *models.Book) string { return formatTime(b.CreatedAt) }, parseTime, false},
+	"updated_at": {"updated_at", func(b *models.Book) string { return formatTime(b.UpdatedAt) }, parseTime, false},
+	"deleted_at": {"deleted_at", func(b *models.Book) string { return formatTime(b.DeletedAt.Time) }, parseTime, false},
+	// series groups volumes of the same series (books outside a series
+	// sort last); volume orders them (books without a number first)
+	"series": {"series_id", func(b *models.Book) string { return uuidKey(b.SeriesID) }, parseUUID, true},
+	"volume": {"COALESCE(volume, -1)", func(b *models.Book) string { return volumeKey(b.Volume) }, parseFloat, false},
+	// average stars; unreviewed books count as 0 (needs withRatings)
+	"rating": {bookAverageRating, func(b *models.Book) string { return ratingKey(b.Rating) }, parseFloat, false},
+}
+
+// value turns a key back into its SQL argument; nil for a NULL key.
+func (f sortField) value(s string) (any, error) {
+	if f.nullable && s == "" {
+		return nil, nil
+	}
+	return f.parse(s)
+}
+
+func parseString(s string) (any, error) { return s, nil }
+func parseInt(s string) (any, error)    { return strconv.Atoi(s) }
+func parseTime(s string) (any, error)   { return time.Parse(time.RFC3339Nano, s) }
+func formatTime(t time.Time) string     { return t.Format(time.RFC3339Nano) }
+func parseFloat(s string) (any, error)  { return strconv.ParseFloat(s, 64) }
+func parseUUID(s string) (any, error)   { return uuid.Parse(s) }
+
+func uuidKey(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
+
+func ratingKey(r *models.BookRating) string {
+	if r == nil {
+		return "0"
+	}
+	return strconv.FormatFloat(r.Average, 'g', -1, 64)
+}
+
+func volumeKey(v *float64) string {
+	if v == nil {
+		return "-1"
+	}
+	return strconv.FormatFloat(*v, 'g', -1, 64)
+}
+
+/*───────────────────────────────────────────────────────────────*
+|                         Sort parsing                          |
+*───────────────────────────────────────────────────────────────*/
+
+type sortKey struct {
+	name string
+	desc bool
+}
+
+// parseSort turns "-year,title" into sort keys, rejecting unknown
+// or repeated fields. An empty raw falls back to def.
+func parseSort(raw, def string) ([]sortKey, error) {
+	if strings.TrimSpace(raw) == "" {
+		raw = def
+	}
+
+	var keys []sortKey
+	seen := map[string]bool{}
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		k := sortKey{name: strings.TrimLeft(part, "+-"), desc: strings.HasPrefix(part, "-")}
+		if _, ok := bookSortFields[k.name]; !ok {
+			return nil, fmt.Errorf("unknown sort field %q", k.name)
+		}
+		if seen[k.name] {
+			return nil, fmt.Errorf("duplicate sort field %q", k.name)
+		}
+		seen[k.name] = true
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+func sortString(keys []sortKey) string {
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k.name
+		if k.desc {
+			parts[i] = "-" + k.name
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+/*───────────────────────────────────────────────────────────────*
+|                       Opaque cursors                          |
+*───────────────────────────────────────────────────────────────*/
+
+// cursor is serialised as base64url JSON. Clients must treat it as
+// opaque; it is only valid together with the sort it was issued for.
+type cursor struct {
+	Sort   string   `json:"s"`
+	Values []string `json:"v"`
+	ID     string   `json:"id"`
+	Prev   bool     `json:"p,omitempty"`
+}
+
+func encodeCursor(keys []sortKey, b *models.Book, prev bool) string {
+	cur := cursor{Sort: sortString(keys), ID: b.ID.String(), Prev: prev}
+	for _, k := range keys {
+		cur.Values = append(cur.Values, bookSortFields[k.name].key(b))
+	}
+	raw, _ := json.Marshal(cur)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+var errBadCursor = errors.New("invalid cursor")
+
+func decodeCursor(raw string, keys []sortKey) (*cursor, []any, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, nil, errBadCursor
+	}
+	var cur cursor
+	if err := json.Unmarshal(data, &cur); err != nil {
+		return nil, nil, errBadCursor
+	}
+	if cur.Sort != sortString(keys) {
+		return nil, nil, errors.New("cursor does not match sort")
+	}
+	if len(cur.Values) != len(keys) {
+		return nil, nil, errBadCursor
+	}
+	id, err := uuid.Parse(cur.ID)
+	if err != nil {
+		return nil, nil, errBadCursor
+	}
+
+	values := make([]any, 0, len(keys)+1)
+	for i, k := range keys {
+		v, err := bookSortFields[k.name].value(cur.Values[i])
+		if err != nil {
+			return nil, nil, errBadCursor
+		}
+		values = append(values, v)
+	}
+	return &cur, append(values, id), nil
+}
+
+/*───────────────────────────────────────────────────────────────*
+|                     Keyset query building                     |
+*───────────────────────────────────────────────────────────────*/
+
+// orderBy applies the sort plus the id tie-breaker. reverse flips every
+// direction, which is how the previous page is fetched.
+func orderBy(db *gorm.DB, keys []sortKey, reverse bool) *gorm.DB {
+	for _, k := range keys {
+		dir := "ASC"
+		if k.desc != reverse {
+			dir = "DESC"
+		}
+		f := bookSortFields[k.name]
+		if f.nullable {
+			db = db.Order(f.column + " IS NULL " + dir)
+		}
+		db = db.Order(f.column + " " + dir)
+	}
+	if reverse {
+		return db.Order("id DESC")
+	}
+	return db.Order("id ASC")
+}
+
+// seekAfter restricts db to rows strictly after values in the sort
+// order (or strictly before them when reverse is set):
+//
+//	(a > ?) OR (a = ? AND b > ?) OR (a = ? AND b = ? AND id > ?)
+func seekAfter(db *gorm.DB, keys []sortKey, values []any, reverse bool) *gorm.DB {
+	fields := make([]sortField, 0, len(keys)+1)
+	descs := make([]bool, 0, len(keys)+1)
+	for _, k := range keys {
+		fields = append(fields, bookSortFields[k.name])
+		descs = append(descs, k.desc)
+	}
+	fields = append(fields, sortField{column: "id"})
+	descs = append(descs, false)
+
+	var (
+		ors  []string
+		args []any
+	)
+	for i := range fields {
+		var ands []string
+		for j := 0; j < i; j++ {
+			cond, arg := fields[j].equal(values[j])
+			ands = append(ands, cond)
+			args = append(args, arg...)
+		}
+		cond, arg := fields[i].beyond(values[i], descs[i] != reverse)
+		ands = append(ands, cond)
+		args = append(args, arg...)
+		ors = append(ors, "("+strings.Join(ands, " AND ")+")")
+	}
+	return db.Where(strings.Join(ors, " OR "), args...)
+}
+
+// equal matches rows whose column holds v.
+func (f sortField) equal(v any) (string, []any) {
+	if f.nullable && v == nil {
+		return f.column + " IS NULL", nil
+	}
+	return f.column + " = ?", []any{v}
+}
+
+// beyond matches rows whose column comes strictly after v, or before it
+// when desc is set. NULL counts as greater than every value.
+func (f sortField) beyond(v any, desc bool) (string, []any) {
+	switch {
+	case !f.nullable && desc:
+		return f.column + " < ?", []any{v}
+	case !f.nullable:
+		return f.column + " > ?", []any{v}
+	case v == nil && desc:
+		return f.column + " IS NOT NULL", nil
+	case v == nil:
+		return "1 = 0", nil
+	case desc:
+		return f.column + " < ?", []any{v}
+	default:
+		return "(" + f.column + " > ? OR " + f.column + " IS NULL)", []any{v}
+	}
+}
+
+/*───────────────────────────────────────────────────────────────*
+|                    Page request & execution                   |
+*───────────────────────────────────────────────────────────────*/
+
+type pageRequest struct {
+	limit  int
+	offset int
+	keys   []sortKey
+	cursor *cursor
+	values []any
+}
+
+// parseLimitOffset reads ?limit and ?offset, applying the default and
+// maximum page size.
+func parseLimitOffset(c *gin.Context) (limit, offset int, err error) {
+	limit = defaultPageLimit
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			return 0, 0, errors.New("limit must be a positive integer")
+		}
+		limit = min(n, maxPageLimit)
+	}
+	if raw := c.Query("offset"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			return 0, 0, errors.New("offset must be a non-negative integer")
+		}
+		offset = n
+	}
+	return limit, offset, nil
+}
+
+// parsePageRequest reads limit/offset/cursor/sort; defSort applies when
+// the client sends no sort. Every book list also takes the /books
+// filters, so their values are checked here too.
+func parsePageRequest(c *gin.Context, defSort string) (*pageRequest, error) {
+	limit, offset, err := parseLimitOffset(c)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkBookFilters(c); err != nil {
+		return nil, err
+	}
+	p := &pageRequest{limit: limit, offset: offset}
+
+	keys, err := parseSort(c.Query("sort"), defSort)
+	if err != nil {
+		return nil, err
+	}
+	p.keys = keys
+
+	if raw := c.Query("cursor"); raw != "" {
+		if p.offset > 0 {
+			return nil, errors.New("cursor and offset are mutually exclusive")
+		}
+		if p.cursor, p.values, err = decodeCursor(raw, keys); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// fetchBookPage runs the paginated query against an already filtered db
+// and returns the page plus its metadata (total ignores the page window).
+func fetchBookPage(db *gorm.DB, p *pageRequest) ([]models.Book, *PageMeta, error) {
+	db = db.Session(&gorm.Session{})
+
+	var total int64
+	if err := db.Model(&models.Book{}).Count(&total).Error; err != nil {
+		return nil, nil, err
+	}
+
+	reverse := p.cursor != nil && p.cursor.Prev
+	q := orderBy(db, p.keys, reverse).Limit(p.limit + 1)
+	if p.cursor != nil {
+		q = seekAfter(q, p.keys, p.values, reverse)
+	} else if p.offset > 0 {
+		q = q.Offset(p.offset)
+	}
+
+	books := make([]models.Book, 0, p.limit+1)
+	if err := q.Find(&books).Error; err != nil {
+		return nil, nil, err
+	}
+	if err := withRatings(db, books); err != nil {
+		return nil, nil, err
+	}
+
+	more := len(books) > p.limit
+	if more {
+		books = books[:p.limit]
+	}
+
+	hasNext, hasPrev := more, p.offset > 0 || p.cursor != nil
+	if reverse {
+		for i, j := 0, len(books)-1; i < j; i, j = i+1, j-1 {
+			books[i], books[j] = books[j], books[i]
+		}
+		hasNext, hasPrev = true, more
+	}
+
+	meta := &PageMeta{Total: total, Limit: p.limit, Offset: p.offset, Sort: sortString(p.keys)}
+	if len(books) > 0 {
+		if hasNext {
+			meta.NextCursor = encodeCursor(p.keys, &books[len(books)-1], false)
+		}
+		if hasPrev {
+			meta.PrevCursor = encodeCursor(p.keys, &books[0], true)
+		}
+	}
+	return books, meta, nil
+}
+
+// PageMeta accompanies paginated list responses.
+type PageMeta struct {
+	Total      int64  `json:"total"`
+	Limit      int    `json:"limit"`
+	Offset     int    `json:"offset,omitempty"`
+	Sort       string `json:"sort"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/hasan-kayan/TaskGo/utils"
+)
+
+/* ────────────────────────────────────────────────────────── *
+   Conditional requests (ETag / If-Match / If-None-Match)
+ * ────────────────────────────────────────────────────────── */
+
+// ifMatchFailed answers 412 and returns true when the client sent an
+// If-Match that does not name the book's current ETag. Writes must
+// still compare versions in SQL – this only catches stale clients early.
+func ifMatchFailed(c *gin.Context, book *models.Book) bool {
+	header := c.GetHeader("If-Match")
+	if header == "" || utils.ETagMatches(header, book.ETag, false) {
+		return false
+	}
+	versionConflict(c, book.ETag)
+	return true
+}
+
+// versionConflict is the 412 response for a lost optimistic-lock race.
+func versionConflict(c *gin.Context, currentETag string) {
+	if currentETag != "" {
+		c.Header("ETag", currentETag)
+	}
+	utils.JSONError(c, http.StatusPreconditionFailed, "book was modified by someone else – reload and retry")
+}
+
+// notModified sets the ETag header and, when If-None-Match names it,
+// answers 304 and returns true.
+func notModified(c *gin.Context, etag string) bool {
+	c.Header("ETag", etag)
+	header := c.GetHeader("If-None-Match")
+	if header == "" || !utils.ETagMatches(header, etag, true) {
+		return false
+	}
+	c.Status(http.StatusNotModified)
+	return true
+}
+
+// bodyETag is a weak validator over data as it is rendered, for
+// responses that mix in live values no version tracks.
+func bodyETag(data any) (string, error) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+	sum := sha1.Sum(body)
+	return `W/"` + hex.EncodeToString(sum[:]) + `"`, nil
+}
+
+// listETag derives a weak validator for a page of books from the item
+// tags, their live ratings and the paging metadata. Items are hashed in
+// page order, so a ?sort=rating reshuffle changes it as well.
+func listETag(books []models.Book, meta *PageMeta) string {
+	h := sha1.New()
+	for i := range books {
+		h.Write([]byte(books[i].ETag))
+		if r := books[i].Rating; r != nil {
+			fmt.Fprintf(h, "|%d|%d", r.Count, r.Sum)
+		}
+	}
+	fmt.Fprintf(h, "|%d|%s|%s", meta.Total, meta.NextCursor, meta.PrevCursor)
+	return `W/"` + hex.EncodeToString(h.Sum(nil)) + `"`
+}
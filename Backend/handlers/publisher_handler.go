@@ -0,0 +1,356 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/hasan-kayan/TaskGo/utils"
+)
+
+// publisherBookCount counts live books published under the house itself
+// (not its imprints).
+const publisherBookCount = `(SELECT COUNT(*) FROM books b
+	WHERE b.publisher_id = publishers.id AND b.deleted_at IS NULL) AS book_count`
+
+// publisherTree selects a publisher id plus all its imprints, however
+// deeply nested. Both SQLite and PostgreSQL understand it.
+const publisherTree = `WITH RECURSIVE tree(id) AS (
+		SELECT ?
+		UNION SELECT p.id FROM publishers p JOIN tree ON p.parent_id = tree.id
+	) SELECT id FROM tree`
+
+/* ────────────────────────────────────────────────────────── *
+   GET /publishers  ─ list, ?name= / ?parent_id= / ?top_level=true
+ * ────────────────────────────────────────────────────────── */
+
+func GetPublishers(c *gin.Context) {
+	limit, offset, err := parseLimitOffset(c)
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	db := database.DB.Model(&models.Publisher{})
+	if q := c.Query("name"); q != "" {
+		db = db.Where("LOWER(name) LIKE ?", "%"+strings.ToLower(q)+"%")
+	}
+	if q := c.Query("parent_id"); q != "" {
+		db = db.Where("parent_id = ?", q)
+	}
+	if c.Query("top_level") == "true" {
+		db = db.Where("parent_id IS NULL")
+	}
+	db = db.Session(&gorm.Session{})
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not list publishers")
+		return
+	}
+
+	publishers := []models.Publisher{}
+	err = db.Select("publishers.*, " + publisherBookCount).
+		Order("LOWER(name)").Order("id").
+		Limit(limit).Offset(offset).
+		Find(&publishers).Error
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not list publishers")
+		return
+	}
+
+	meta := &PageMeta{Total: total, Limit: limit, Offset: offset, Sort: "name"}
+	utils.JSONSuccessMeta(c, http.StatusOK, publishers, meta)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   GET /publishers/:id  ─ with its direct imprints
+ * ────────────────────────────────────────────────────────── */
+
+func GetPublisher(c *gin.Context) {
+	publisher, ok := loadPublisher(c)
+	if !ok {
+		return
+	}
+	utils.JSONSuccess(c, http.StatusOK, publisher)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   POST /publishers  ─ create (parent_id makes it an imprint)
+ * ────────────────────────────────────────────────────────── */
+
+func CreatePublisher(c *gin.Context) {
+	var payload models.Publisher
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	payload.ID = uuid.New()
+	payload.Imprints = nil
+
+	if fields := validatePublisher(database.DB, &payload); len(fields) > 0 {
+		utils.JSONFieldErrors(c, http.StatusUnprocessableEntity, "validation failed", fields)
+		return
+	}
+	if err := database.DB.Create(&payload).Error; err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not create publisher")
+		return
+	}
+	utils.JSONSuccess(c, http.StatusCreated, payload)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   PUT /publishers/:id  ─ full replacement of name / parent / website
+ * ────────────────────────────────────────────────────────── */
+
+// UpdatePublisher also renames the display name on every linked book.
+func UpdatePublisher(c *gin.Context) {
+	current, ok := loadPublisher(c)
+	if !ok {
+		return
+	}
+
+	var next models.Publisher
+	if err := c.ShouldBindJSON(&next); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	next.ID, next.CreatedAt = current.ID, current.CreatedAt
+	next.Name = strings.TrimSpace(next.Name)
+
+	if fields := validatePublisher(database.DB, &next); len(fields) > 0 {
+		utils.JSONFieldErrors(c, http.StatusUnprocessableEntity, "validation failed", fields)
+		return
+	}
+
+	err := actorDB(c).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.Publisher{ID: current.ID}).
+			Select("name", "parent_id", "website").
+			Updates(&next).Error
+		if err != nil || next.Name == current.Name {
+			return err
+		}
+		_, err = repointBooks(tx, []uuid.UUID{current.ID}, &next)
+		return err
+	})
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not update publisher")
+		return
+	}
+
+	updated, _ := findPublisher(database.DB, current.ID)
+	utils.JSONSuccess(c, http.StatusOK, updated)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   DELETE /publishers/:id  ─ only without books or imprints
+ * ────────────────────────────────────────────────────────── */
+
+func DeletePublisher(c *gin.Context) {
+	publisher, ok := loadPublisher(c)
+	if !ok {
+		return
+	}
+
+	var books, imprints int64
+	database.DB.Unscoped().Model(&models.Book{}).Where("publisher_id = ?", publisher.ID).Count(&books)
+	database.DB.Model(&models.Publisher{}).Where("parent_id = ?", publisher.ID).Count(&imprints)
+	if books > 0 || imprints > 0 {
+		utils.JSONError(c, http.StatusConflict, fmt.Sprintf(
+			"publisher still has %d book(s) and %d imprint(s); merge it into another publisher instead", books, imprints))
+		return
+	}
+
+	if err := database.DB.Delete(&models.Publisher{ID: publisher.ID}).Error; err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not delete publisher")
+		return
+	}
+	utils.JSONSuccess(c, http.StatusOK, models.MessageResponse{Message: "publisher deleted"})
+}
+
+/* ────────────────────────────────────────────────────────── *
+   POST /publishers/:id/merge  ─ fold duplicates into :id
+ * ────────────────────────────────────────────────────────── */
+
+// MergeRequest lists the duplicate publishers to fold into the target.
+type MergeRequest struct {
+	SourceIDs []uuid.UUID `json:"source_ids" binding:"required"`
+}
+
+// MergeResult reports what a merge moved.
+type MergeResult struct {
+	Publisher     *models.Publisher `json:"publisher"`
+	Merged        int               `json:"merged"`
+	BooksMoved    int64             `json:"books_moved"`
+	ImprintsMoved int64             `json:"imprints_moved"`
+}
+
+// MergePublishers re-points the books (trashed ones too) and imprints
+// of every source at the target, then deletes the sources – all in
+// one transaction. Moved books get a new version, so their ETags change.
+func MergePublishers(c *gin.Context) {
+	target, ok := loadPublisher(c)
+	if !ok {
+		return
+	}
+
+	var req MergeRequest
+	if err := c.ShouldBindJSON(&req); err != nil || len(req.SourceIDs) == 0 {
+		utils.JSONError(c, http.StatusBadRequest, "body must be {\"source_ids\": [\"…\"]}")
+		return
+	}
+
+	fields := map[string]string{}
+	seen := map[uuid.UUID]bool{}
+	for i, id := range req.SourceIDs {
+		switch {
+		case id == target.ID:
+			fields[fmt.Sprintf("source_ids[%d]", i)] = "cannot merge a publisher into itself"
+		case seen[id]:
+			fields[fmt.Sprintf("source_ids[%d]", i)] = "duplicate id"
+		}
+		seen[id] = true
+	}
+	var found []uuid.UUID
+	database.DB.Model(&models.Publisher{}).Where("id IN ?", req.SourceIDs).Pluck("id", &found)
+	known := map[uuid.UUID]bool{}
+	for _, id := range found {
+		known[id] = true
+	}
+	for i, id := range req.SourceIDs {
+		if !known[id] && id != target.ID {
+			fields[fmt.Sprintf("source_ids[%d]", i)] = "publisher not found"
+		}
+	}
+	if len(fields) > 0 {
+		utils.JSONFieldErrors(c, http.StatusUnprocessableEntity, "validation failed", fields)
+		return
+	}
+
+	result := MergeResult{Merged: len(req.SourceIDs)}
+	err := actorDB(c).Transaction(func(tx *gorm.DB) error {
+		// the target may itself be an imprint of a source; lift it to
+		// that source's parent so no cycle (or dangling parent) remains
+		if target.ParentID != nil && seen[*target.ParentID] {
+			var parent models.Publisher
+			if err := tx.First(&parent, "id = ?", *target.ParentID).Error; err != nil {
+				return err
+			}
+			for parent.ParentID != nil && seen[*parent.ParentID] {
+				if err := tx.First(&parent, "id = ?", *parent.ParentID).Error; err != nil {
+					return err
+				}
+			}
+			if err := tx.Model(&models.Publisher{ID: target.ID}).Update("parent_id", parent.ParentID).Error; err != nil {
+				return err
+			}
+		}
+
+		res := tx.Model(&models.Publisher{}).
+			Where("parent_id IN ? AND id NOT IN ?", req.SourceIDs, req.SourceIDs).
+			Update("parent_id", target.ID)
+		if res.Error != nil {
+			return res.Error
+		}
+		result.ImprintsMoved = res.RowsAffected
+
+		moved, err := repointBooks(tx, req.SourceIDs, target)
+		if err != nil {
+			return err
+		}
+		result.BooksMoved = moved
+
+		return tx.Where("id IN ?", req.SourceIDs).Delete(&models.Publisher{}).Error
+	})
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not merge publishers")
+		return
+	}
+
+	result.Publisher, _ = findPublisher(database.DB, target.ID)
+	utils.JSONSuccess(c, http.StatusOK, result)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   helpers
+ * ────────────────────────────────────────────────────────── */
+
+func loadPublisher(c *gin.Context) (*models.Publisher, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "invalid UUID")
+		return nil, false
+	}
+	publisher, err := findPublisher(database.DB, id)
+	if err != nil {
+		utils.JSONError(c, http.StatusNotFound, "publisher not found")
+		return nil, false
+	}
+	return publisher, true
+}
+
+func findPublisher(db *gorm.DB, id uuid.UUID) (*models.Publisher, error) {
+	var publisher models.Publisher
+	err := db.Model(&models.Publisher{}).
+		Select("publishers.*, "+publisherBookCount).
+		Preload("Imprints", func(db *gorm.DB) *gorm.DB { return db.Order("LOWER(name)") }).
+		First(&publisher, "publishers.id = ?", id).Error
+	return &publisher, err
+}
+
+// validatePublisher checks the struct tags, that the parent exists and
+// that it is not the publisher itself or one of its own imprints.
+func validatePublisher(db *gorm.DB, p *models.Publisher) map[string]string {
+	if err := utils.ValidatePublisher(p); err != nil {
+		return utils.FieldErrors(err)
+	}
+	if p.ParentID == nil {
+		return nil
+	}
+
+	var parent models.Publisher
+	if err := db.First(&parent, "id = ?", *p.ParentID).Error; err != nil {
+		return map[string]string{"parent_id": "publisher not found"}
+	}
+	var inTree int64
+	db.Raw("SELECT COUNT(*) FROM ("+publisherTree+") t WHERE t.id = ?", p.ID, parent.ID).Scan(&inTree)
+	if inTree > 0 {
+		return map[string]string{"parent_id": "would make the publisher an imprint of itself"}
+	}
+	return nil
+}
+
+// resolveBookPublisher checks book.PublisherID and copies the
+// publisher's name into the book's display field.
+func resolveBookPublisher(db *gorm.DB, book *models.Book) map[string]string {
+	if book.PublisherID == nil {
+		return nil
+	}
+	var publisher models.Publisher
+	if err := db.First(&publisher, "id = ?", *book.PublisherID).Error; err != nil {
+		return map[string]string{"publisher_id": "publisher not found"}
+	}
+	book.Publisher = publisher.Name
+	return nil
+}
+
+// repointBooks links every book of any publisher in ids to p (name
+// included) and bumps their version so cached ETags go stale.
+func repointBooks(tx *gorm.DB, ids []uuid.UUID, p *models.Publisher) (int64, error) {
+	res := tx.Unscoped().Model(&models.Book{}).
+		Where("publisher_id IN ?", ids).
+		UpdateColumns(map[string]any{
+			"publisher_id": p.ID,
+			"publisher":    p.Name,
+			"version":      gorm.Expr("version + 1"),
+			"updated_at":   time.Now(),
+		})
+	return res.RowsAffected, res.Error
+}
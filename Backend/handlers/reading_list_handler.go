@@ -0,0 +1,479 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/hasan-kayan/TaskGo/utils"
+)
+
+// memberHeader names the member making the request. Private lists are
+// only shown to, and lists only changed by, their owner.
+const memberHeader = "X-Member-ID"
+
+// listItemCount counts the items of a reading list.
+const listItemCount = `(SELECT COUNT(*) FROM reading_list_items i
+	WHERE i.list_id = reading_lists.id) AS item_count`
+
+var (
+	errNotOnList = errors.New("book is not on this list")
+	errBadOrder  = errors.New("must list every book on the list exactly once")
+)
+
+// ReadingListDetail is a list with its items in order.
+type ReadingListDetail struct {
+	models.ReadingList
+	Items []ListItem `json:"items"`
+}
+
+// ListItem is an item plus its book. Book is null and BookDeleted set
+// when the book has been trashed since it was added.
+type ListItem struct {
+	models.ReadingListItem
+	Book        *models.Book `json:"book"`
+	BookDeleted bool         `json:"book_deleted,omitempty"`
+}
+
+// ListItemRequest is the body of POST /lists/:id/items. Position 0 (or
+// none) appends; otherwise later items move down one place.
+type ListItemRequest struct {
+	BookID   uuid.UUID `json:"book_id"`
+	Position int       `json:"position"`
+	Note     string    `json:"note"`
+}
+
+// ListOrderRequest is the body of PUT /lists/:id/items: every book on
+// the list, in the new order.
+type ListOrderRequest struct {
+	BookIDs []uuid.UUID `json:"book_ids"`
+}
+
+/* ────────────────────────────────────────────────────────── *
+   GET /lists  ─ public lists plus the caller's own; ?member_id= ?name=
+ * ────────────────────────────────────────────────────────── */
+
+func GetReadingLists(c *gin.Context) {
+	limit, offset, err := parseLimitOffset(c)
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	db := database.DB.Model(&models.ReadingList{}).
+		Where("reading_lists.public = ? OR reading_lists.member_id = ?", true, viewerID(c))
+	if q := c.Query("member_id"); q != "" {
+		db = db.Where("reading_lists.member_id = ?", q)
+	}
+	if q := c.Query("name"); q != "" {
+		db = db.Where("LOWER(reading_lists.name) LIKE ?", "%"+strings.ToLower(q)+"%")
+	}
+	db = db.Session(&gorm.Session{})
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not list reading lists")
+		return
+	}
+
+	lists := []models.ReadingList{}
+	err = db.Select("reading_lists.*, " + listItemCount).
+		Order("LOWER(reading_lists.name)").Order("reading_lists.id").
+		Limit(limit).Offset(offset).
+		Find(&lists).Error
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not list reading lists")
+		return
+	}
+
+	meta := &PageMeta{Total: total, Limit: limit, Offset: offset, Sort: "name"}
+	utils.JSONSuccessMeta(c, http.StatusOK, lists, meta)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   POST /lists  ─ {member_id, name, description, public}
+ * ────────────────────────────────────────────────────────── */
+
+func CreateReadingList(c *gin.Context) {
+	var payload models.ReadingList
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	payload.ID = uuid.New()
+	payload.Name = strings.TrimSpace(payload.Name)
+
+	fields := map[string]string{}
+	if err := utils.ValidateReadingList(&payload); err != nil {
+		fields = utils.FieldErrors(err)
+	}
+	if payload.MemberID == uuid.Nil {
+		fields["member_id"] = "is required"
+	} else if err := database.DB.First(&models.Member{}, "id = ?", payload.MemberID).Error; err != nil {
+		fields["member_id"] = "member not found"
+	}
+	if len(fields) > 0 {
+		utils.JSONFieldErrors(c, http.StatusUnprocessableEntity, "validation failed", fields)
+		return
+	}
+
+	if err := database.DB.Create(&payload).Error; err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not create reading list")
+		return
+	}
+	utils.JSONSuccess(c, http.StatusCreated, payload)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   GET /lists/:id  ─ with its items and their books
+ * ────────────────────────────────────────────────────────── */
+
+func GetReadingList(c *gin.Context) {
+	list, ok := loadReadingList(c)
+	if !ok {
+		return
+	}
+	renderReadingList(c, http.StatusOK, list)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   PUT /lists/:id  ─ replace name / description / public
+ * ────────────────────────────────────────────────────────── */
+
+func UpdateReadingList(c *gin.Context) {
+	current, ok := loadOwnReadingList(c)
+	if !ok {
+		return
+	}
+
+	var next models.ReadingList
+	if err := c.ShouldBindJSON(&next); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	next.ID, next.MemberID = current.ID, current.MemberID
+	next.Name = strings.TrimSpace(next.Name)
+	if err := utils.ValidateReadingList(&next); err != nil {
+		utils.JSONFieldErrors(c, http.StatusUnprocessableEntity, "validation failed", utils.FieldErrors(err))
+		return
+	}
+
+	err := database.DB.Model(&models.ReadingList{ID: current.ID}).
+		Select("name", "description", "public").
+		Updates(&next).Error
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not update reading list")
+		return
+	}
+
+	updated, _ := findReadingList(database.DB, current.ID)
+	utils.JSONSuccess(c, http.StatusOK, updated)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   DELETE /lists/:id  ─ the list and its items
+ * ────────────────────────────────────────────────────────── */
+
+func DeleteReadingList(c *gin.Context) {
+	list, ok := loadOwnReadingList(c)
+	if !ok {
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("list_id = ?", list.ID).Delete(&models.ReadingListItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.ReadingList{ID: list.ID}).Error
+	})
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not delete reading list")
+		return
+	}
+	utils.JSONSuccess(c, http.StatusOK, models.MessageResponse{Message: "reading list deleted"})
+}
+
+/* ────────────────────────────────────────────────────────── *
+   POST /lists/:id/items  ─ add a book (at a position)
+ * ────────────────────────────────────────────────────────── */
+
+func AddReadingListItem(c *gin.Context) {
+	list, ok := loadOwnReadingList(c)
+	if !ok {
+		return
+	}
+
+	var req ListItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	fields := map[string]string{}
+	if req.BookID == uuid.Nil {
+		fields["book_id"] = "is required"
+	} else if err := database.DB.First(&models.Book{}, "id = ?", req.BookID).Error; err != nil {
+		fields["book_id"] = "book not found"
+	}
+	if req.Position < 0 {
+		fields["position"] = "must be greater than or equal to 0"
+	}
+	if len(fields) > 0 {
+		utils.JSONFieldErrors(c, http.StatusUnprocessableEntity, "validation failed", fields)
+		return
+	}
+
+	item := models.ReadingListItem{ID: uuid.New(), ListID: list.ID, BookID: req.BookID, Note: strings.TrimSpace(req.Note)}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := touchReadingList(tx, list.ID); err != nil {
+			return err
+		}
+		var last int
+		err := tx.Model(&models.ReadingListItem{}).
+			Where("list_id = ?", list.ID).
+			Select("COALESCE(MAX(position), 0)").Scan(&last).Error
+		if err != nil {
+			return err
+		}
+		item.Position = last + 1
+		if req.Position > 0 && req.Position <= last {
+			item.Position = req.Position
+			err := tx.Model(&models.ReadingListItem{}).
+				Where("list_id = ? AND position >= ?", list.ID, item.Position).
+				Update("position", gorm.Expr("position + 1")).Error
+			if err != nil {
+				return err
+			}
+		}
+		return tx.Create(&item).Error
+	})
+	if database.IsUniqueViolation(err) {
+		utils.JSONError(c, http.StatusConflict, "book is already on this list")
+		return
+	}
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not add book to list")
+		return
+	}
+	renderReadingList(c, http.StatusCreated, list)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   PUT /lists/:id/items  ─ reorder: {book_ids: [...]}
+ * ────────────────────────────────────────────────────────── */
+
+func ReorderReadingList(c *gin.Context) {
+	list, ok := loadOwnReadingList(c)
+	if !ok {
+		return
+	}
+
+	var req ListOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := touchReadingList(tx, list.ID); err != nil {
+			return err
+		}
+		var current []uuid.UUID
+		if err := tx.Model(&models.ReadingListItem{}).Where("list_id = ?", list.ID).Pluck("book_id", &current).Error; err != nil {
+			return err
+		}
+		if !samePermutation(current, req.BookIDs) {
+			return errBadOrder
+		}
+		for i, id := range req.BookIDs {
+			err := tx.Model(&models.ReadingListItem{}).
+				Where("list_id = ? AND book_id = ?", list.ID, id).
+				Update("position", i+1).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, errBadOrder) {
+		utils.JSONFieldErrors(c, http.StatusUnprocessableEntity, "validation failed",
+			map[string]string{"book_ids": err.Error()})
+		return
+	}
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not reorder list")
+		return
+	}
+	renderReadingList(c, http.StatusOK, list)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   DELETE /lists/:id/items/:book_id
+ * ────────────────────────────────────────────────────────── */
+
+func RemoveReadingListItem(c *gin.Context) {
+	list, ok := loadOwnReadingList(c)
+	if !ok {
+		return
+	}
+	bookID, err := uuid.Parse(c.Param("book_id"))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "invalid UUID")
+		return
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := touchReadingList(tx, list.ID); err != nil {
+			return err
+		}
+		var item models.ReadingListItem
+		if err := tx.Where("list_id = ? AND book_id = ?", list.ID, bookID).Limit(1).Find(&item).Error; err != nil {
+			return err
+		}
+		if item.ID == uuid.Nil {
+			return errNotOnList
+		}
+		if err := tx.Delete(&item).Error; err != nil {
+			return err
+		}
+		// close the gap so positions stay 1..n
+		return tx.Model(&models.ReadingListItem{}).
+			Where("list_id = ? AND position > ?", list.ID, item.Position).
+			Update("position", gorm.Expr("position - 1")).Error
+	})
+	if errors.Is(err, errNotOnList) {
+		utils.JSONError(c, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not remove book from list")
+		return
+	}
+	renderReadingList(c, http.StatusOK, list)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   helpers
+ * ────────────────────────────────────────────────────────── */
+
+// renderReadingList answers with the list, its items in order and their
+// live books.
+func renderReadingList(c *gin.Context, status int, list *models.ReadingList) {
+	detail, err := readingListDetail(database.DB, list.ID)
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not load reading list")
+		return
+	}
+	utils.JSONSuccess(c, status, detail)
+}
+
+func readingListDetail(db *gorm.DB, id uuid.UUID) (*ReadingListDetail, error) {
+	list, err := findReadingList(db, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []models.ReadingListItem
+	if err := db.Where("list_id = ?", id).Order("position").Order("id").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	ids := make([]uuid.UUID, len(items))
+	for i := range items {
+		ids[i] = items[i].BookID
+	}
+	var books []models.Book
+	if len(ids) > 0 {
+		if err := db.Where("id IN ?", ids).Find(&books).Error; err != nil {
+			return nil, err
+		}
+		if err := withRatings(db, books); err != nil {
+			return nil, err
+		}
+	}
+	byID := make(map[uuid.UUID]*models.Book, len(books))
+	for i := range books {
+		byID[books[i].ID] = &books[i]
+	}
+
+	detail := &ReadingListDetail{ReadingList: *list, Items: make([]ListItem, len(items))}
+	for i, item := range items {
+		book := byID[item.BookID]
+		detail.Items[i] = ListItem{ReadingListItem: item, Book: book, BookDeleted: book == nil}
+	}
+	return detail, nil
+}
+
+// samePermutation reports whether got names each of want exactly once.
+func samePermutation(want, got []uuid.UUID) bool {
+	if len(want) != len(got) {
+		return false
+	}
+	left := make(map[uuid.UUID]int, len(want))
+	for _, id := range want {
+		left[id]++
+	}
+	for _, id := range got {
+		if left[id] == 0 {
+			return false
+		}
+		left[id]--
+	}
+	return true
+}
+
+// touchReadingList bumps the list's updated_at. Item writes call it
+// first: the row lock it takes serialises concurrent edits of one list,
+// so positions read afterwards cannot be stale.
+func touchReadingList(tx *gorm.DB, id uuid.UUID) error {
+	return tx.Model(&models.ReadingList{ID: id}).Update("updated_at", time.Now()).Error
+}
+
+// viewerID is the member named by the X-Member-ID header, or uuid.Nil.
+func viewerID(c *gin.Context) uuid.UUID {
+	id, _ := uuid.Parse(c.GetHeader(memberHeader))
+	return id
+}
+
+// loadReadingList resolves :id; another member's private list is
+// reported as not found.
+func loadReadingList(c *gin.Context) (*models.ReadingList, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "invalid UUID")
+		return nil, false
+	}
+	list, err := findReadingList(database.DB, id)
+	if err != nil || (!list.Public && list.MemberID != viewerID(c)) {
+		utils.JSONError(c, http.StatusNotFound, "reading list not found")
+		return nil, false
+	}
+	return list, true
+}
+
+// loadOwnReadingList is loadReadingList for writes, which only the
+// owner may make.
+func loadOwnReadingList(c *gin.Context) (*models.ReadingList, bool) {
+	list, ok := loadReadingList(c)
+	if !ok {
+		return nil, false
+	}
+	if list.MemberID != viewerID(c) {
+		utils.JSONError(c, http.StatusForbidden, "only the list's owner can change it (send "+memberHeader+")")
+		return nil, false
+	}
+	return list, true
+}
+
+func findReadingList(db *gorm.DB, id uuid.UUID) (*models.ReadingList, error) {
+	var list models.ReadingList
+	err := db.Model(&models.ReadingList{}).
+		Select("reading_lists.*, "+listItemCount).
+		First(&list, "reading_lists.id = ?", id).Error
+	return &list, err
+}
@@ -0,0 +1,248 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/hasan-kayan/TaskGo/utils"
+)
+
+// reviewerName is the reviewing member's name, if they are still
+// registered.
+const reviewerName = `(SELECT m.name FROM members m WHERE m.id = reviews.member_id) AS member_name`
+
+var errReviewChanged = errors.New("review was changed by another request; reload and retry")
+
+/* ────────────────────────────────────────────────────────── *
+   GET /books/:id/reviews  ─ ?sort=-created_at|created_at|-rating|rating
+ * ────────────────────────────────────────────────────────── */
+
+var reviewSorts = map[string]string{
+	"-created_at": "reviews.created_at DESC",
+	"created_at":  "reviews.created_at",
+	"-rating":     "reviews.rating DESC, reviews.created_at DESC",
+	"rating":      "reviews.rating, reviews.created_at DESC",
+}
+
+func GetBookReviews(c *gin.Context) {
+	book, ok := loadBookParam(c)
+	if !ok {
+		return
+	}
+	limit, offset, err := parseLimitOffset(c)
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	sort := c.DefaultQuery("sort", "-created_at")
+	order, ok := reviewSorts[sort]
+	if !ok {
+		utils.JSONError(c, http.StatusBadRequest, "sort must be created_at or rating, optionally prefixed with -")
+		return
+	}
+
+	db := database.DB.Model(&models.Review{}).Where("reviews.book_id = ?", book.ID).Session(&gorm.Session{})
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not list reviews")
+		return
+	}
+
+	reviews := []models.Review{}
+	err = db.Select("reviews.*, " + reviewerName).
+		Order(order).Order("reviews.id").
+		Limit(limit).Offset(offset).
+		Find(&reviews).Error
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not list reviews")
+		return
+	}
+
+	meta := &PageMeta{Total: total, Limit: limit, Offset: offset, Sort: sort}
+	utils.JSONSuccessMeta(c, http.StatusOK, reviews, meta)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   POST /books/:id/reviews  ─ one per member and book
+ * ────────────────────────────────────────────────────────── */
+
+func CreateReview(c *gin.Context) {
+	book, ok := loadBookParam(c)
+	if !ok {
+		return
+	}
+
+	var payload models.Review
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	payload.ID, payload.BookID = uuid.New(), book.ID
+	payload.Text = strings.TrimSpace(payload.Text)
+
+	fields := map[string]string{}
+	if err := utils.ValidateReview(&payload); err != nil {
+		fields = utils.FieldErrors(err)
+	}
+	if payload.MemberID == uuid.Nil {
+		fields["member_id"] = "is required"
+	} else if err := database.DB.First(&models.Member{}, "id = ?", payload.MemberID).Error; err != nil {
+		fields["member_id"] = "member not found"
+	}
+	if len(fields) > 0 {
+		utils.JSONFieldErrors(c, http.StatusUnprocessableEntity, "validation failed", fields)
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&payload).Error; err != nil {
+			return err
+		}
+		return database.AdjustRating(tx, book.ID, 1, int64(payload.Rating))
+	})
+	if database.IsUniqueViolation(err) {
+		utils.JSONError(c, http.StatusConflict, "member has already reviewed this book")
+		return
+	}
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not create review")
+		return
+	}
+
+	created, _ := findReview(database.DB, book.ID, payload.ID)
+	utils.JSONSuccess(c, http.StatusCreated, created)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   GET /books/:id/reviews/:review_id
+ * ────────────────────────────────────────────────────────── */
+
+func GetReview(c *gin.Context) {
+	review, ok := loadReview(c)
+	if !ok {
+		return
+	}
+	utils.JSONSuccess(c, http.StatusOK, review)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   PUT /books/:id/reviews/:review_id  ─ replace rating and text
+ * ────────────────────────────────────────────────────────── */
+
+func UpdateReview(c *gin.Context) {
+	current, ok := loadReview(c)
+	if !ok {
+		return
+	}
+
+	var next models.Review
+	if err := c.ShouldBindJSON(&next); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	next.ID, next.BookID, next.MemberID = current.ID, current.BookID, current.MemberID
+	next.Text = strings.TrimSpace(next.Text)
+	if err := utils.ValidateReview(&next); err != nil {
+		utils.JSONFieldErrors(c, http.StatusUnprocessableEntity, "validation failed", utils.FieldErrors(err))
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		// guarded by the old rating so the totals move by the right delta
+		res := tx.Model(&models.Review{ID: current.ID}).
+			Where("rating = ?", current.Rating).
+			Select("rating", "text").
+			Updates(&next)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return errReviewChanged
+		}
+		return database.AdjustRating(tx, current.BookID, 0, int64(next.Rating-current.Rating))
+	})
+	if !renderReviewWrite(c, err, "could not update review") {
+		return
+	}
+
+	updated, _ := findReview(database.DB, current.BookID, current.ID)
+	utils.JSONSuccess(c, http.StatusOK, updated)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   DELETE /books/:id/reviews/:review_id
+ * ────────────────────────────────────────────────────────── */
+
+func DeleteReview(c *gin.Context) {
+	review, ok := loadReview(c)
+	if !ok {
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("rating = ?", review.Rating).Delete(&models.Review{ID: review.ID})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return errReviewChanged
+		}
+		return database.AdjustRating(tx, review.BookID, -1, -int64(review.Rating))
+	})
+	if !renderReviewWrite(c, err, "could not delete review") {
+		return
+	}
+	utils.JSONSuccess(c, http.StatusOK, models.MessageResponse{Message: "review deleted"})
+}
+
+/* ────────────────────────────────────────────────────────── *
+   helpers
+ * ────────────────────────────────────────────────────────── */
+
+func renderReviewWrite(c *gin.Context, err error, failure string) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, errReviewChanged):
+		utils.JSONError(c, http.StatusConflict, err.Error())
+	default:
+		utils.JSONError(c, http.StatusInternalServerError, failure)
+	}
+	return false
+}
+
+// loadReview resolves /books/:id/reviews/:review_id; the review must
+// belong to that book.
+func loadReview(c *gin.Context) (*models.Review, bool) {
+	bookID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "invalid UUID")
+		return nil, false
+	}
+	id, err := uuid.Parse(c.Param("review_id"))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "invalid UUID")
+		return nil, false
+	}
+	review, err := findReview(database.DB, bookID, id)
+	if err != nil {
+		utils.JSONError(c, http.StatusNotFound, "review not found")
+		return nil, false
+	}
+	return review, true
+}
+
+func findReview(db *gorm.DB, bookID, id uuid.UUID) (*models.Review, error) {
+	var review models.Review
+	err := db.Model(&models.Review{}).
+		Select("reviews.*, "+reviewerName).
+		First(&review, "reviews.id = ? AND reviews.book_id = ?", id, bookID).Error
+	return &review, err
+}
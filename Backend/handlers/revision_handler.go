@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/hasan-kayan/TaskGo/utils"
+)
+
+// actorHeader names the person or system making a write; it ends up in
+// BookRevision.Actor.
+const actorHeader = "X-Actor"
+
+/* ────────────────────────────────────────────────────────── *
+   GET /books/:id/history  ─ revisions, newest first
+ * ────────────────────────────────────────────────────────── */
+
+// GetBookHistory also answers for trashed and purged books, as long as
+// revisions were recorded for them.
+func GetBookHistory(c *gin.Context) {
+	bookID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "invalid UUID")
+		return
+	}
+	limit, offset, err := parseLimitOffset(c)
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	db := database.DB.Model(&models.BookRevision{}).Where("book_id = ?", bookID)
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not load history")
+		return
+	}
+	if total == 0 {
+		var exists int64
+		database.DB.Unscoped().Model(&models.Book{}).Where("id = ?", bookID).Count(&exists)
+		if exists == 0 {
+			utils.JSONError(c, http.StatusNotFound, "book not found")
+			return
+		}
+	}
+
+	revisions := []models.BookRevision{}
+	err = db.Order("revision DESC").Limit(limit).Offset(offset).Find(&revisions).Error
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not load history")
+		return
+	}
+
+	meta := &PageMeta{Total: total, Limit: limit, Offset: offset, Sort: "-revision"}
+	utils.JSONSuccessMeta(c, http.StatusOK, revisions, meta)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   POST /books/:id/revert/:revision  ─ restore an earlier state
+ * ────────────────────────────────────────────────────────── */
+
+// RevertBook writes the book as it was right after the given revision.
+// The revert is itself a new revision, so it can be reverted in turn.
+// Honours If-Match like PUT; trashed books must be restored first.
+func RevertBook(c *gin.Context) {
+	current, ok := loadBookParam(c)
+	if !ok {
+		return
+	}
+	number, err := strconv.Atoi(c.Param("revision"))
+	if err != nil || number < 1 {
+		utils.JSONError(c, http.StatusBadRequest, "revision must be a positive integer")
+		return
+	}
+
+	var rev models.BookRevision
+	err = database.DB.Where("book_id = ? AND revision = ?", current.ID, number).First(&rev).Error
+	if err != nil {
+		utils.JSONError(c, http.StatusNotFound, "revision not found")
+		return
+	}
+	if len(rev.Snapshot) == 0 {
+		utils.JSONError(c, http.StatusUnprocessableEntity, "revision has no state to revert to")
+		return
+	}
+	if ifMatchFailed(c, current) {
+		return
+	}
+
+	var next models.Book
+	if fields := decodeBookDocument(rev.Snapshot, &next); len(fields) > 0 {
+		utils.JSONFieldErrors(c, http.StatusUnprocessableEntity, "revision no longer matches the book schema", fields)
+		return
+	}
+
+	note := fmt.Sprintf("reverted to revision %d", number)
+	c.Request = c.Request.WithContext(database.WithRevisionNote(c.Request.Context(), note))
+	saveBookReplacement(c, current, &next)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   helpers
+ * ────────────────────────────────────────────────────────── */
+
+// actorDB is database.DB carrying the request's actor, for writes whose
+// revisions should say who made them. Requests without X-Actor are
+// recorded as "anonymous".
+func actorDB(c *gin.Context) *gorm.DB {
+	actor := c.GetHeader(actorHeader)
+	if actor == "" {
+		actor = "anonymous"
+	}
+	return database.DB.WithContext(database.WithActor(c.Request.Context(), actor))
+}
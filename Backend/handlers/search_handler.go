@@ -0,0 +1,282 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/hasan-kayan/TaskGo/utils"
+)
+
+/* ────────────────────────────────────────────────────────── *
+   GET /books/search?q=  ─ ranked full-text search
+ * ────────────────────────────────────────────────────────── */
+
+// Column weights for bm25(): title > author > description.
+const (
+	weightTitle       = 10.0
+	weightAuthor      = 5.0
+	weightDescription = 1.0
+
+	// matches are delimited with control characters first and only
+	// turned into <mark> once the text around them is HTML-escaped
+	matchOpen  = "\x02"
+	matchClose = "\x03"
+)
+
+var markup = strings.NewReplacer(matchOpen, "<mark>", matchClose, "</mark>")
+
+// SearchResult is a book plus its relevance score and highlighted
+// fragments: HTML-escaped text with matches wrapped in <mark>…</mark>.
+type SearchResult struct {
+	models.Book
+	Score      float64           `json:"score"`
+	Highlights map[string]string `json:"highlights"`
+}
+
+// searchRow is the raw scan target; highlight columns are folded into
+// SearchResult.Highlights afterwards.
+type searchRow struct {
+	models.Book
+	Score         float64
+	HlTitle       string
+	HlAuthor      string
+	HlDescription string
+}
+
+// SearchBooks accepts the FTS5 query syntax when the index is available:
+//
+//	q=clean code          both terms, any column
+//	q="clean code"        exact phrase
+//	q=archit*             prefix match
+//	q=author:martin       column filter
+func SearchBooks(c *gin.Context) {
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" {
+		utils.JSONError(c, http.StatusBadRequest, "query parameter q is required")
+		return
+	}
+
+	limit, offset, err := parseLimitOffset(c)
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	search := searchLike
+	if database.SearchIndexEnabled {
+		search = searchFTS
+	}
+
+	results, total, err := search(database.DB, q, limit, offset)
+	if errors.Is(err, errBadSearchQuery) {
+		utils.JSONError(c, http.StatusBadRequest, "invalid search query")
+		return
+	}
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not search books")
+		return
+	}
+
+	meta := &PageMeta{Total: total, Limit: limit, Offset: offset, Sort: "relevance"}
+	utils.JSONSuccessMeta(c, http.StatusOK, results, meta)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   FTS5 implementation
+ * ────────────────────────────────────────────────────────── */
+
+// errBadSearchQuery marks a q that FTS5 could not parse.
+var errBadSearchQuery = errors.New("invalid search query")
+
+// ftsQueryError wraps err in errBadSearchQuery when FTS5 rejected the
+// query text itself: bad syntax or an unknown column filter.
+func ftsQueryError(err error) error {
+	msg := err.Error()
+	if strings.Contains(msg, "fts5:") || strings.Contains(msg, "unterminated string") ||
+		strings.Contains(msg, "no such column") {
+		return fmt.Errorf("%w: %v", errBadSearchQuery, err)
+	}
+	return err
+}
+
+func searchFTS(db *gorm.DB, q string, limit, offset int) ([]SearchResult, int64, error) {
+	base := db.Table("books_fts").
+		Joins("JOIN books ON books.rowid = books_fts.rowid").
+		Where("books_fts MATCH ?", q).
+		Where("books.deleted_at IS NULL"). // raw table – no soft-delete scope
+		Session(&gorm.Session{})
+
+	var total int64
+	if err := base.Count(&total).Error; err != nil {
+		return nil, 0, ftsQueryError(err)
+	}
+
+	var rows []searchRow
+	err := base.Select(
+		"books.*, "+
+			"bm25(books_fts, ?, ?, ?) AS score, "+
+			"highlight(books_fts, 0, ?, ?) AS hl_title, "+
+			"highlight(books_fts, 1, ?, ?) AS hl_author, "+
+			"snippet(books_fts, 2, ?, ?, '…', 16) AS hl_description",
+		weightTitle, weightAuthor, weightDescription,
+		matchOpen, matchClose, matchOpen, matchClose, matchOpen, matchClose,
+	).
+		Order("score"). // bm25 is lower-is-better
+		Order("books.id").
+		Limit(limit).
+		Offset(offset).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, 0, ftsQueryError(err)
+	}
+
+	results := make([]SearchResult, len(rows))
+	for i, r := range rows {
+		r.Book.ETag = r.Book.EntityTag() // Scan skips AfterFind
+		results[i] = SearchResult{
+			Book:  r.Book,
+			Score: -r.Score, // expose higher-is-better
+			Highlights: map[string]string{
+				"title":       highlightHTML(r.HlTitle),
+				"author":      highlightHTML(r.HlAuthor),
+				"description": highlightHTML(r.HlDescription),
+			},
+		}
+	}
+	return results, total, nil
+}
+
+/* ────────────────────────────────────────────────────────── *
+   LIKE fallback (no FTS5 / other dialects)
+ * ────────────────────────────────────────────────────────── */
+
+var termPattern = regexp.MustCompile(`"([^"]+)"|(\S+)`)
+
+// searchTerms splits q into phrases and words, dropping FTS operators
+// the fallback cannot honour (trailing *, column prefixes).
+func searchTerms(q string) []string {
+	var terms []string
+	for _, m := range termPattern.FindAllStringSubmatch(q, -1) {
+		term := m[1]
+		if term == "" {
+			term = m[2]
+			if i := strings.IndexByte(term, ':'); i >= 0 {
+				term = term[i+1:]
+			}
+			term = strings.Trim(term, "*")
+		}
+		if term = strings.ToLower(strings.TrimSpace(term)); term != "" {
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+func searchLike(db *gorm.DB, q string, limit, offset int) ([]SearchResult, int64, error) {
+	terms := searchTerms(q)
+	if len(terms) == 0 {
+		return []SearchResult{}, 0, nil
+	}
+
+	var (
+		score     []string
+		scoreArgs []any
+	)
+	for _, t := range terms {
+		like := "%" + t + "%"
+		db = db.Where("(LOWER(title) LIKE ? OR LOWER(author) LIKE ? OR LOWER(description) LIKE ?)", like, like, like)
+		score = append(score,
+			"(CASE WHEN LOWER(title) LIKE ? THEN ? ELSE 0 END)",
+			"(CASE WHEN LOWER(author) LIKE ? THEN ? ELSE 0 END)",
+			"(CASE WHEN LOWER(description) LIKE ? THEN ? ELSE 0 END)",
+		)
+		scoreArgs = append(scoreArgs, like, weightTitle, like, weightAuthor, like, weightDescription)
+	}
+	base := db.Model(&models.Book{}).Session(&gorm.Session{})
+
+	var total int64
+	if err := base.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var rows []searchRow
+	err := base.Select("books.*, ("+strings.Join(score, " + ")+") AS score", scoreArgs...).
+		Order("score DESC").
+		Order("title ASC").
+		Order("books.id").
+		Limit(limit).
+		Offset(offset).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	results := make([]SearchResult, len(rows))
+	for i, r := range rows {
+		r.Book.ETag = r.Book.EntityTag() // Scan skips AfterFind
+		results[i] = SearchResult{
+			Book:  r.Book,
+			Score: r.Score,
+			Highlights: map[string]string{
+				"title":       highlightHTML(highlightTerms(r.Title, terms)),
+				"author":      highlightHTML(highlightTerms(r.Author, terms)),
+				"description": highlightHTML(highlightTerms(snippetAround(r.Description, terms, 80), terms)),
+			},
+		}
+	}
+	return results, total, nil
+}
+
+// highlightHTML escapes text delimited with matchOpen / matchClose and
+// marks the matches up, so book fields can never inject markup.
+func highlightHTML(text string) string {
+	return markup.Replace(html.EscapeString(text))
+}
+
+// highlightTerms delimits every case-insensitive occurrence of terms in text.
+func highlightTerms(text string, terms []string) string {
+	quoted := make([]string, len(terms))
+	for i, t := range terms {
+		quoted[i] = regexp.QuoteMeta(t)
+	}
+	re := regexp.MustCompile("(?i)" + strings.Join(quoted, "|"))
+	return re.ReplaceAllString(text, matchOpen+"$0"+matchClose)
+}
+
+// snippetAround trims text to roughly width runes centred on the first
+// matching term, marking cut ends with an ellipsis.
+func snippetAround(text string, terms []string, width int) string {
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+
+	lower := []rune(strings.ToLower(text))
+	at := 0
+	for _, t := range terms {
+		if i := strings.Index(string(lower), t); i >= 0 {
+			at = len([]rune(string(lower)[:i]))
+			break
+		}
+	}
+
+	start := max(0, at-width/2)
+	end := min(len(runes), start+width)
+	out := string(runes[start:end])
+	if start > 0 {
+		out = "…" + out
+	}
+	if end < len(runes) {
+		out += "…"
+	}
+	return out
+}
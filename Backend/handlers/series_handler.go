@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/hasan-kayan/TaskGo/utils"
+)
+
+// seriesBookCount counts the live volumes of a series.
+const seriesBookCount = `(SELECT COUNT(*) FROM books b
+	WHERE b.series_id = series.id AND b.deleted_at IS NULL) AS book_count`
+
+// SeriesDetail is a series plus its volumes in reading order.
+type SeriesDetail struct {
+	models.Series
+	Volumes []models.Book `json:"volumes"`
+}
+
+/* ────────────────────────────────────────────────────────── *
+   GET /series  ─ list, ?name= filter, alphabetical
+ * ────────────────────────────────────────────────────────── */
+
+func GetSeriesList(c *gin.Context) {
+	limit, offset, err := parseLimitOffset(c)
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	db := database.DB.Model(&models.Series{})
+	if q := c.Query("name"); q != "" {
+		db = db.Where("LOWER(name) LIKE ?", "%"+strings.ToLower(q)+"%")
+	}
+	db = db.Session(&gorm.Session{})
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not list series")
+		return
+	}
+
+	list := []models.Series{}
+	err = db.Select("series.*, " + seriesBookCount).
+		Order("LOWER(name)").Order("id").
+		Limit(limit).Offset(offset).
+		Find(&list).Error
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not list series")
+		return
+	}
+
+	meta := &PageMeta{Total: total, Limit: limit, Offset: offset, Sort: "name"}
+	utils.JSONSuccessMeta(c, http.StatusOK, list, meta)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   GET /series/:id  ─ with volumes in order
+ * ────────────────────────────────────────────────────────── */
+
+// GetSeries lists volumes by number; unnumbered books come last, by title.
+func GetSeries(c *gin.Context) {
+	series, ok := loadSeries(c)
+	if !ok {
+		return
+	}
+
+	volumes := []models.Book{}
+	err := database.DB.Where("series_id = ?", series.ID).
+		Order("volume IS NULL").Order("volume").Order("title").
+		Find(&volumes).Error
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not load volumes")
+		return
+	}
+	utils.JSONSuccess(c, http.StatusOK, SeriesDetail{Series: *series, Volumes: volumes})
+}
+
+/* ────────────────────────────────────────────────────────── *
+   POST /series  ─ create
+ * ────────────────────────────────────────────────────────── */
+
+func CreateSeries(c *gin.Context) {
+	var payload models.Series
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	payload.ID = uuid.New()
+
+	if err := utils.ValidateSeries(&payload); err != nil {
+		utils.JSONFieldErrors(c, http.StatusUnprocessableEntity, "validation failed", utils.FieldErrors(err))
+		return
+	}
+	if err := database.DB.Create(&payload).Error; err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not create series")
+		return
+	}
+	utils.JSONSuccess(c, http.StatusCreated, payload)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   PUT /series/:id  ─ full replacement of name / description
+ * ────────────────────────────────────────────────────────── */
+
+func UpdateSeries(c *gin.Context) {
+	current, ok := loadSeries(c)
+	if !ok {
+		return
+	}
+
+	var next models.Series
+	if err := c.ShouldBindJSON(&next); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	next.ID, next.CreatedAt = current.ID, current.CreatedAt
+	next.Name = strings.TrimSpace(next.Name)
+
+	if err := utils.ValidateSeries(&next); err != nil {
+		utils.JSONFieldErrors(c, http.StatusUnprocessableEntity, "validation failed", utils.FieldErrors(err))
+		return
+	}
+	err := database.DB.Model(&models.Series{ID: current.ID}).
+		Select("name", "description").
+		Updates(&next).Error
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not update series")
+		return
+	}
+
+	updated, _ := findSeries(database.DB, current.ID)
+	utils.JSONSuccess(c, http.StatusOK, updated)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   DELETE /series/:id  ─ only once it has no volumes
+ * ────────────────────────────────────────────────────────── */
+
+func DeleteSeries(c *gin.Context) {
+	series, ok := loadSeries(c)
+	if !ok {
+		return
+	}
+
+	var volumes int64
+	database.DB.Unscoped().Model(&models.Book{}).Where("series_id = ?", series.ID).Count(&volumes)
+	if volumes > 0 {
+		utils.JSONError(c, http.StatusConflict,
+			fmt.Sprintf("series still has %d book(s); move them out first", volumes))
+		return
+	}
+
+	if err := database.DB.Delete(&models.Series{ID: series.ID}).Error; err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not delete series")
+		return
+	}
+	utils.JSONSuccess(c, http.StatusOK, models.MessageResponse{Message: "series deleted"})
+}
+
+/* ────────────────────────────────────────────────────────── *
+   helpers
+ * ────────────────────────────────────────────────────────── */
+
+func loadSeries(c *gin.Context) (*models.Series, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "invalid UUID")
+		return nil, false
+	}
+	series, err := findSeries(database.DB, id)
+	if err != nil {
+		utils.JSONError(c, http.StatusNotFound, "series not found")
+		return nil, false
+	}
+	return series, true
+}
+
+func findSeries(db *gorm.DB, id uuid.UUID) (*models.Series, error) {
+	var series models.Series
+	err := db.Model(&models.Series{}).
+		Select("series.*, "+seriesBookCount).
+		First(&series, "series.id = ?", id).Error
+	return &series, err
+}
+
+// resolveBookSeries checks book.SeriesID; a volume number only makes
+// sense inside a series.
+func resolveBookSeries(db *gorm.DB, book *models.Book) map[string]string {
+	if book.SeriesID == nil {
+		if book.Volume != nil {
+			return map[string]string{"volume": "requires series_id"}
+		}
+		return nil
+	}
+	var n int64
+	db.Model(&models.Series{}).Where("id = ?", *book.SeriesID).Count(&n)
+	if n == 0 {
+		return map[string]string{"series_id": "series not found"}
+	}
+	return nil
+}
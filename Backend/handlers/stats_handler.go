@@ -0,0 +1,378 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/hasan-kayan/TaskGo/config"
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/hasan-kayan/TaskGo/utils"
+)
+
+// The /stats queries stick to portable SQL – COUNT, SUM, AVG, GROUP BY
+// and CASE – so they run unchanged on any GORM dialect. Anything that
+// would need date functions (the monthly series) is bucketed in Go.
+
+const (
+	defaultStatsMonths = 12
+	maxStatsMonths     = 120
+	defaultStatsTop    = 10
+)
+
+// StatsOverview is the headline numbers of the catalogue.
+type StatsOverview struct {
+	Books          int64   `json:"books"`
+	TrashedBooks   int64   `json:"trashed_books"`
+	Authors        int64   `json:"authors"`
+	Publishers     int64   `json:"publishers"`
+	Series         int64   `json:"series"`
+	Tags           int64   `json:"tags"`
+	Members        int64   `json:"members"`
+	Copies         int64   `json:"copies"`
+	ActiveLoans    int64   `json:"active_loans"`
+	AveragePages   float64 `json:"average_pages"` // over books with a page count
+	BooksWithPages int64   `json:"books_with_pages"`
+}
+
+// StatCount is one bucket of a distribution.
+type StatCount struct {
+	Key   string `json:"key"`
+	Count int64  `json:"count"`
+}
+
+// StatDistribution splits the live books by one field. Unknown counts
+// the books that leave the field empty.
+type StatDistribution struct {
+	Total   int64       `json:"total"`
+	Unknown int64       `json:"unknown"`
+	Buckets []StatCount `json:"buckets"`
+}
+
+// AuthorStat is an author and the number of live books crediting them.
+type AuthorStat struct {
+	AuthorID  uuid.UUID `json:"author_id"`
+	Name      string    `json:"name"`
+	BookCount int64     `json:"book_count"`
+}
+
+// FieldCompleteness tells how many live books lack a field.
+type FieldCompleteness struct {
+	Field    string  `json:"field"`
+	Missing  int64   `json:"missing"`
+	Complete float64 `json:"complete_pct"` // 0–100, one decimal
+}
+
+// StatsCompleteness is the data-quality report over the live books.
+type StatsCompleteness struct {
+	Books  int64               `json:"books"`
+	Fields []FieldCompleteness `json:"fields"`
+}
+
+// completenessFields maps a reported field to the SQL test for "missing".
+var completenessFields = []struct{ field, missing string }{
+	{"isbn", "isbn IS NULL OR isbn = ''"},
+	{"cover_image_url", "cover_image_url IS NULL OR cover_image_url = ''"},
+	{"description", "description IS NULL OR description = ''"},
+	{"publisher", "publisher IS NULL OR publisher = ''"},
+	{"type", "type IS NULL OR type = ''"},
+	{"year", "year IS NULL OR year = 0"},
+	{"pages", "pages IS NULL OR pages = 0"},
+}
+
+/* ────────────────────────────────────────────────────────── *
+   GET /stats  ─ totals and average page count
+ * ────────────────────────────────────────────────────────── */
+
+func GetStats(c *gin.Context) {
+	var out StatsOverview
+	counts := []struct {
+		db   *gorm.DB
+		dest *int64
+	}{
+		{database.DB.Model(&models.Book{}), &out.Books},
+		{database.DB.Unscoped().Model(&models.Book{}).Where("deleted_at IS NOT NULL"), &out.TrashedBooks},
+		{database.DB.Model(&models.Author{}), &out.Authors},
+		{database.DB.Model(&models.Publisher{}), &out.Publishers},
+		{database.DB.Model(&models.Series{}), &out.Series},
+		{database.DB.Model(&models.Tag{}), &out.Tags},
+		{database.DB.Model(&models.Member{}), &out.Members},
+		{database.DB.Model(&models.Copy{}), &out.Copies},
+		{database.DB.Model(&models.Loan{}).Where("returned_at IS NULL"), &out.ActiveLoans},
+	}
+	for _, q := range counts {
+		if err := q.db.Count(q.dest).Error; err != nil {
+			utils.JSONError(c, http.StatusInternalServerError, "could not compute stats")
+			return
+		}
+	}
+
+	var pages struct {
+		N   int64
+		Avg *float64
+	}
+	err := database.DB.Model(&models.Book{}).
+		Select("COUNT(*) AS n, AVG(pages) AS avg").
+		Where("pages > 0").
+		Scan(&pages).Error
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not compute stats")
+		return
+	}
+	out.BooksWithPages = pages.N
+	if pages.Avg != nil {
+		out.AveragePages = round1(*pages.Avg)
+	}
+	renderStats(c, out)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   GET /stats/added  ─ books added per month, ?months=12
+ * ────────────────────────────────────────────────────────── */
+
+// GetStatsAdded counts the books created in each of the last months
+// (the current one included), oldest first. Months without new books
+// are listed with 0; books trashed since are still counted.
+func GetStatsAdded(c *gin.Context) {
+	months := defaultStatsMonths
+	if raw := c.Query("months"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > maxStatsMonths {
+			utils.JSONError(c, http.StatusBadRequest, fmt.Sprintf("months must be an integer from 1 to %d", maxStatsMonths))
+			return
+		}
+		months = n
+	}
+
+	now := time.Now()
+	start := time.Date(now.Year(), now.Month()-time.Month(months-1), 1, 0, 0, 0, 0, now.Location())
+	var created []time.Time
+	err := database.DB.Unscoped().Model(&models.Book{}).
+		Where("created_at >= ?", start).
+		Pluck("created_at", &created).Error
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not compute stats")
+		return
+	}
+
+	byMonth := make(map[string]int64, months)
+	for _, t := range created {
+		byMonth[t.In(now.Location()).Format("2006-01")]++
+	}
+	out := make([]StatCount, months)
+	for i := range out {
+		key := start.AddDate(0, i, 0).Format("2006-01")
+		out[i] = StatCount{Key: key, Count: byMonth[key]}
+	}
+	renderStats(c, out)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   GET /stats/years  ─ live books per publication year
+ * ────────────────────────────────────────────────────────── */
+
+func GetStatsYears(c *gin.Context) {
+	out, err := distribution("year", "year > 0", "year")
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not compute stats")
+		return
+	}
+	renderStats(c, out)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   GET /stats/types  ─ live books per type, largest first
+ * ────────────────────────────────────────────────────────── */
+
+func GetStatsTypes(c *gin.Context) {
+	out, err := distribution("type", "type IS NOT NULL AND type <> ''", "n DESC, type")
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not compute stats")
+		return
+	}
+	renderStats(c, out)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   GET /stats/authors  ─ most credited authors, ?limit=10
+ * ────────────────────────────────────────────────────────── */
+
+func GetStatsAuthors(c *gin.Context) {
+	limit, err := parseStatsTop(c)
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	out := []AuthorStat{}
+	err = database.DB.Table("book_authors ba").
+		Select("a.id AS author_id, a.name AS name, COUNT(DISTINCT ba.book_id) AS book_count").
+		Joins("JOIN authors a ON a.id = ba.author_id").
+		Joins("JOIN books b ON b.id = ba.book_id AND b.deleted_at IS NULL").
+		Group("a.id, a.name").
+		Order("book_count DESC, a.name, a.id").
+		Limit(limit).
+		Scan(&out).Error
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not compute stats")
+		return
+	}
+	renderStats(c, out)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   GET /stats/publishers  ─ publishers with most books, ?limit=10
+ * ────────────────────────────────────────────────────────── */
+
+func GetStatsPublishers(c *gin.Context) {
+	limit, err := parseStatsTop(c)
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rows, err := countBy(database.DB.Model(&models.Book{}), "publisher",
+		"publisher IS NOT NULL AND publisher <> ''", "n DESC, publisher", limit)
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not compute stats")
+		return
+	}
+	renderStats(c, rows)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   GET /stats/completeness  ─ share of books with each field set
+ * ────────────────────────────────────────────────────────── */
+
+func GetStatsCompleteness(c *gin.Context) {
+	sel := "COUNT(*)"
+	for _, f := range completenessFields {
+		sel += ", SUM(CASE WHEN " + f.missing + " THEN 1 ELSE 0 END)"
+	}
+	// one row: the book count, then the missing count per field
+	row := make([]*int64, len(completenessFields)+1)
+	dest := make([]any, len(row))
+	for i := range row {
+		dest[i] = &row[i]
+	}
+	if err := database.DB.Model(&models.Book{}).Select(sel).Row().Scan(dest...); err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not compute stats")
+		return
+	}
+
+	out := StatsCompleteness{Books: deref(row[0]), Fields: make([]FieldCompleteness, len(completenessFields))}
+	for i, f := range completenessFields {
+		missing := deref(row[i+1])
+		complete := 100.0
+		if out.Books > 0 {
+			complete = round1(100 * float64(out.Books-missing) / float64(out.Books))
+		}
+		out.Fields[i] = FieldCompleteness{Field: f.field, Missing: missing, Complete: complete}
+	}
+	renderStats(c, out)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   helpers
+ * ────────────────────────────────────────────────────────── */
+
+// distribution counts the live books per value of column; rows failing
+// known are reported as Unknown.
+func distribution(column, known, order string) (*StatDistribution, error) {
+	db := database.DB.Model(&models.Book{}).Session(&gorm.Session{})
+	out := &StatDistribution{}
+	if err := db.Count(&out.Total).Error; err != nil {
+		return nil, err
+	}
+	buckets, err := countBy(db, column, known, order, 0)
+	if err != nil {
+		return nil, err
+	}
+	out.Buckets, out.Unknown = buckets, out.Total
+	for _, b := range buckets {
+		out.Unknown -= b.Count
+	}
+	return out, nil
+}
+
+// countBy groups the rows of db that pass where by column, in order
+// (which may use the count alias n); limit 0 returns every group. The
+// aliases avoid words some dialects reserve, such as key and value.
+func countBy(db *gorm.DB, column, where, order string, limit int) ([]StatCount, error) {
+	q := db.Select(column + " AS bucket, COUNT(*) AS n").Where(where).Group(column).Order(order)
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+	rows, err := q.Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []StatCount{}
+	for rows.Next() {
+		var (
+			bucket any
+			n      int64
+		)
+		if err := rows.Scan(&bucket, &n); err != nil {
+			return nil, err
+		}
+		out = append(out, StatCount{Key: statKey(bucket), Count: n})
+	}
+	return out, rows.Err()
+}
+
+// statKey renders a grouped value; drivers differ in whether they hand
+// back text columns as string or []byte.
+func statKey(v any) string {
+	if b, ok := v.([]byte); ok {
+		return string(b)
+	}
+	return fmt.Sprint(v)
+}
+
+func parseStatsTop(c *gin.Context) (int, error) {
+	raw := c.Query("limit")
+	if raw == "" {
+		return defaultStatsTop, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 1 {
+		return 0, errors.New("limit must be a positive integer")
+	}
+	return min(n, maxPageLimit), nil
+}
+
+// renderStats answers with a weak ETag over the body and lets clients
+// and proxies keep it for STATS_CACHE_SECONDS (default 300, 0 = always
+// revalidate). A matching If-None-Match gets 304.
+func renderStats(c *gin.Context, data any) {
+	etag, err := bodyETag(data)
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not compute stats")
+		return
+	}
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", config.Int("STATS_CACHE_SECONDS", 300, 0)))
+	if notModified(c, etag) {
+		return
+	}
+	utils.JSONSuccess(c, http.StatusOK, data)
+}
+
+func round1(f float64) float64 { return math.Round(f*10) / 10 }
+
+func deref(n *int64) int64 {
+	if n == nil {
+		return 0
+	}
+	return *n
+}
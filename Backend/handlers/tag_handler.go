@@ -0,0 +1,356 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/hasan-kayan/TaskGo/utils"
+)
+
+// tagUsageCount counts the live books carrying the tag itself.
+const tagUsageCount = `(SELECT COUNT(*) FROM book_tags bt
+	JOIN books b ON b.id = bt.book_id
+	WHERE bt.tag_id = tags.id AND b.deleted_at IS NULL) AS usage_count`
+
+// tagTree selects the tags with the given slugs plus all their
+// descendants, so filtering by a genre also finds its subgenres.
+const tagTree = `WITH RECURSIVE tree(id) AS (
+		SELECT id FROM tags WHERE slug IN ?
+		UNION SELECT t.id FROM tags t JOIN tree ON t.parent_id = tree.id
+	) SELECT id FROM tree`
+
+// tagSubtree is tagTree rooted at a single tag id.
+const tagSubtree = `WITH RECURSIVE tree(id) AS (
+		SELECT ?
+		UNION SELECT t.id FROM tags t JOIN tree ON t.parent_id = tree.id
+	) SELECT id FROM tree`
+
+const msgTagTaken = "a tag with this name already exists"
+
+/* ────────────────────────────────────────────────────────── *
+   GET /tags  ─ list with usage counts
+ * ────────────────────────────────────────────────────────── */
+
+// GetTags filters by ?name=, ?parent_id= or ?top_level=true and sorts
+// alphabetically, or by usage with ?sort=-usage.
+func GetTags(c *gin.Context) {
+	limit, offset, err := parseLimitOffset(c)
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	db := database.DB.Model(&models.Tag{})
+	if q := c.Query("name"); q != "" {
+		db = db.Where("LOWER(name) LIKE ?", "%"+strings.ToLower(q)+"%")
+	}
+	if q := c.Query("parent_id"); q != "" {
+		db = db.Where("parent_id = ?", q)
+	}
+	if c.Query("top_level") == "true" {
+		db = db.Where("parent_id IS NULL")
+	}
+	db = db.Session(&gorm.Session{})
+
+	sort := c.DefaultQuery("sort", "name")
+	if sort != "name" && sort != "-usage" {
+		utils.JSONError(c, http.StatusBadRequest, "sort must be name or -usage")
+		return
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not list tags")
+		return
+	}
+
+	q := db.Select("tags.*, " + tagUsageCount)
+	if sort == "-usage" {
+		q = q.Order("usage_count DESC")
+	}
+	tags := []models.Tag{}
+	err = q.Order("slug").Limit(limit).Offset(offset).Find(&tags).Error
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not list tags")
+		return
+	}
+
+	meta := &PageMeta{Total: total, Limit: limit, Offset: offset, Sort: sort}
+	utils.JSONSuccessMeta(c, http.StatusOK, tags, meta)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   GET /tags/:id  ─ with its direct children
+ * ────────────────────────────────────────────────────────── */
+
+func GetTag(c *gin.Context) {
+	tag, ok := loadTag(c)
+	if !ok {
+		return
+	}
+	utils.JSONSuccess(c, http.StatusOK, tag)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   POST /tags  ─ create (parent_id makes it a subtag)
+ * ────────────────────────────────────────────────────────── */
+
+func CreateTag(c *gin.Context) {
+	var payload models.Tag
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	payload.ID = uuid.New()
+	payload.Children = nil
+
+	if fields := validateTag(database.DB, &payload); len(fields) > 0 {
+		utils.JSONFieldErrors(c, http.StatusUnprocessableEntity, "validation failed", fields)
+		return
+	}
+	if err := database.DB.Create(&payload).Error; err != nil {
+		if database.IsUniqueViolation(err) {
+			utils.JSONError(c, http.StatusConflict, msgTagTaken)
+			return
+		}
+		utils.JSONError(c, http.StatusInternalServerError, "could not create tag")
+		return
+	}
+	utils.JSONSuccess(c, http.StatusCreated, payload)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   PUT /tags/:id  ─ rename / move in the hierarchy
+ * ────────────────────────────────────────────────────────── */
+
+func UpdateTag(c *gin.Context) {
+	current, ok := loadTag(c)
+	if !ok {
+		return
+	}
+
+	var next models.Tag
+	if err := c.ShouldBindJSON(&next); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	next.ID = current.ID
+	next.Name = strings.TrimSpace(next.Name)
+	next.Slug = models.TagSlug(next.Name)
+
+	if fields := validateTag(database.DB, &next); len(fields) > 0 {
+		utils.JSONFieldErrors(c, http.StatusUnprocessableEntity, "validation failed", fields)
+		return
+	}
+	err := database.DB.Model(&models.Tag{ID: current.ID}).
+		Select("name", "slug", "parent_id").
+		Updates(&next).Error
+	if err != nil {
+		if database.IsUniqueViolation(err) {
+			utils.JSONError(c, http.StatusConflict, msgTagTaken)
+			return
+		}
+		utils.JSONError(c, http.StatusInternalServerError, "could not update tag")
+		return
+	}
+
+	updated, _ := findTag(database.DB, current.ID)
+	utils.JSONSuccess(c, http.StatusOK, updated)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   DELETE /tags/:id  ─ untag books, lift children one level
+ * ────────────────────────────────────────────────────────── */
+
+func DeleteTag(c *gin.Context) {
+	tag, ok := loadTag(c)
+	if !ok {
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("tag_id = ?", tag.ID).Delete(&models.BookTag{}).Error; err != nil {
+			return err
+		}
+		err := tx.Model(&models.Tag{}).Where("parent_id = ?", tag.ID).Update("parent_id", tag.ParentID).Error
+		if err != nil {
+			return err
+		}
+		return tx.Delete(&models.Tag{ID: tag.ID}).Error
+	})
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not delete tag")
+		return
+	}
+	utils.JSONSuccess(c, http.StatusOK, models.MessageResponse{Message: "tag deleted"})
+}
+
+/* ────────────────────────────────────────────────────────── *
+   GET|PUT /books/:id/tags  ─ a book's tags
+ * ────────────────────────────────────────────────────────── */
+
+func GetBookTags(c *gin.Context) {
+	book, ok := loadBookParam(c)
+	if !ok {
+		return
+	}
+
+	tags, err := bookTags(database.DB, book.ID)
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not load tags")
+		return
+	}
+	utils.JSONSuccess(c, http.StatusOK, tags)
+}
+
+// SetBookTags replaces the book's tags with the listed ones, given by
+// name or slug: ["Programming", "software-architecture"]. The tags
+// must exist. The free-text Type is left as it is.
+func SetBookTags(c *gin.Context) {
+	book, ok := loadBookParam(c)
+	if !ok {
+		return
+	}
+
+	var names []string
+	if err := c.ShouldBindJSON(&names); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "body must be a JSON array of tag names or slugs")
+		return
+	}
+
+	slugs := make([]string, len(names))
+	for i, n := range names {
+		slugs[i] = models.TagSlug(n)
+	}
+	var tags []models.Tag
+	if len(slugs) > 0 {
+		if err := database.DB.Where("slug IN ?", slugs).Find(&tags).Error; err != nil {
+			utils.JSONError(c, http.StatusInternalServerError, "could not load tags")
+			return
+		}
+	}
+	bySlug := make(map[string]uuid.UUID, len(tags))
+	for _, t := range tags {
+		bySlug[t.Slug] = t.ID
+	}
+	fields := map[string]string{}
+	for i, s := range slugs {
+		if _, ok := bySlug[s]; !ok {
+			fields[fmt.Sprintf("[%d]", i)] = "tag not found"
+		}
+	}
+	if len(fields) > 0 {
+		utils.JSONFieldErrors(c, http.StatusUnprocessableEntity, "validation failed", fields)
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("book_id = ?", book.ID).Delete(&models.BookTag{}).Error; err != nil {
+			return err
+		}
+		for _, id := range bySlug {
+			if err := tx.Create(&models.BookTag{BookID: book.ID, TagID: id}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not save tags")
+		return
+	}
+
+	saved, _ := bookTags(database.DB, book.ID)
+	utils.JSONSuccess(c, http.StatusOK, saved)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   helpers
+ * ────────────────────────────────────────────────────────── */
+
+// applyTagFilter narrows db to books tagged with any (default) or all
+// of the comma-separated tag names/slugs; subtags count as a match.
+// mode is checked by checkBookFilters.
+func applyTagFilter(db *gorm.DB, raw, mode string) *gorm.DB {
+	var slugs []string
+	for _, n := range strings.Split(raw, ",") {
+		if s := models.TagSlug(n); s != "" {
+			slugs = append(slugs, s)
+		}
+	}
+	if len(slugs) == 0 {
+		return db
+	}
+
+	tagged := "books.id IN (SELECT book_id FROM book_tags WHERE tag_id IN (" + tagTree + "))"
+	if mode != "all" {
+		return db.Where(tagged, slugs)
+	}
+	for _, s := range slugs {
+		db = db.Where(tagged, []string{s})
+	}
+	return db
+}
+
+func loadTag(c *gin.Context) (*models.Tag, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "invalid UUID")
+		return nil, false
+	}
+	tag, err := findTag(database.DB, id)
+	if err != nil {
+		utils.JSONError(c, http.StatusNotFound, "tag not found")
+		return nil, false
+	}
+	return tag, true
+}
+
+func findTag(db *gorm.DB, id uuid.UUID) (*models.Tag, error) {
+	var tag models.Tag
+	err := db.Model(&models.Tag{}).
+		Select("tags.*, "+tagUsageCount).
+		Preload("Children", func(db *gorm.DB) *gorm.DB { return db.Order("slug") }).
+		First(&tag, "tags.id = ?", id).Error
+	return &tag, err
+}
+
+func bookTags(db *gorm.DB, bookID uuid.UUID) ([]models.Tag, error) {
+	tags := []models.Tag{}
+	err := db.Where("id IN (?)", db.Model(&models.BookTag{}).Select("tag_id").Where("book_id = ?", bookID)).
+		Order("slug").
+		Find(&tags).Error
+	return tags, err
+}
+
+// validateTag checks the struct tags, that the name yields a slug and
+// that the parent exists and is not the tag itself or a descendant.
+func validateTag(db *gorm.DB, t *models.Tag) map[string]string {
+	if err := utils.ValidateTag(t); err != nil {
+		return utils.FieldErrors(err)
+	}
+	if models.TagSlug(t.Name) == "" {
+		return map[string]string{"name": "must contain a letter or digit"}
+	}
+	if t.ParentID == nil {
+		return nil
+	}
+
+	var parent models.Tag
+	if err := db.First(&parent, "id = ?", *t.ParentID).Error; err != nil {
+		return map[string]string{"parent_id": "tag not found"}
+	}
+	var inTree int64
+	db.Raw("SELECT COUNT(*) FROM ("+tagSubtree+") t WHERE t.id = ?", t.ID, parent.ID).Scan(&inTree)
+	if inTree > 0 {
+		return map[string]string{"parent_id": "would make the tag its own descendant"}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/hasan-kayan/TaskGo/utils"
+)
+
+/* ────────────────────────────────────────────────────────── *
+   GET /books/trash  ─ soft-deleted books, most recent first
+ * ────────────────────────────────────────────────────────── */
+
+func GetTrash(c *gin.Context) {
+	page, err := parsePageRequest(c, defaultTrashSort)
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	trashed := database.DB.Unscoped().Where("deleted_at IS NOT NULL")
+	books, meta, err := fetchBookPage(applyBookFilters(trashed, c), page)
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "could not list trash")
+		return
+	}
+
+	utils.JSONSuccessMeta(c, http.StatusOK, books, meta)
+}
+
+/* ────────────────────────────────────────────────────────── *
+   POST /books/:id/restore  ─ undo a soft delete
+ * ────────────────────────────────────────────────────────── */
+
+func RestoreBook(c *gin.Context) {
+	bookID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "invalid UUID")
+		return
+	}
+
+	var book models.Book
+	err = database.DB.Unscoped().
+		Where("deleted_at IS NOT NULL").
+		First(&book, "id = ?", bookID).Error
+	if err != nil {
+		utils.JSONError(c, http.StatusNotFound, "book not found in trash")
+		return
+	}
+
+	if ifMatchFailed(c, &book) {
+		return
+	}
+
+	restored, err := database.RestoreBook(actorDB(c), &book)
+	switch {
+	case database.IsISBNViolation(err):
+		// another live book took the ISBN while this one was binned
+		utils.JSONError(c, http.StatusConflict, msgISBNTaken)
+	case err != nil:
+		utils.JSONError(c, http.StatusInternalServerError, "could not restore book")
+	case !restored:
+		versionConflict(c, "")
+	default:
+		c.Header("ETag", book.ETag)
+		utils.JSONSuccess(c, http.StatusOK, book)
+	}
+}
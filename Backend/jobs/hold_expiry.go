@@ -0,0 +1,55 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/models"
+)
+
+var holdInterval = time.Hour
+
+/*───────────────────────────────────────────────────────────────*
+|                       Hold pickup expiry                      |
+*───────────────────────────────────────────────────────────────*/
+
+// ExpireHolds closes ready holds whose pickup window passed before now
+// and passes their copies to the next hold in each queue. Returns how
+// many holds expired.
+func ExpireHolds(db *gorm.DB, now time.Time, window time.Duration) (int64, error) {
+	var expired int64
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		expired, err = database.CloseHolds(tx, models.HoldExpired, now, window,
+			"status = ? AND expires_at < ?", models.HoldReady, now)
+		return err
+	})
+	return expired, err
+}
+
+// StartHoldExpirer runs ExpireHolds hourly with the given pickup window
+// in the background until ctx is cancelled.
+func StartHoldExpirer(ctx context.Context, db *gorm.DB, window time.Duration) {
+	go func() {
+		ticker := time.NewTicker(holdInterval)
+		defer ticker.Stop()
+
+		for {
+			if n, err := ExpireHolds(db, time.Now(), window); err != nil {
+				log.Printf("❌ hold expiry failed: %v", err)
+			} else if n > 0 {
+				log.Printf("📚 expired %d uncollected hold(s)", n)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
@@ -0,0 +1,69 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/models"
+)
+
+var overdueInterval = 24 * time.Hour
+
+/*───────────────────────────────────────────────────────────────*
+|                  Overdue loans and their fines                |
+*───────────────────────────────────────────────────────────────*/
+
+// MarkOverdue stamps overdue_since on active loans that are past due at
+// now, then brings the fines of all overdue active loans up to date.
+// Returns how many loans were newly marked.
+func MarkOverdue(db *gorm.DB, now time.Time) (int64, error) {
+	var marked int64
+	err := db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.Loan{}).
+			Where("returned_at IS NULL AND overdue_since IS NULL AND due_at < ?", now).
+			Update("overdue_since", gorm.Expr("due_at"))
+		if res.Error != nil {
+			return res.Error
+		}
+		marked = res.RowsAffected
+
+		var loans []models.Loan
+		if err := tx.Where("returned_at IS NULL AND due_at < ?", now).Find(&loans).Error; err != nil {
+			return err
+		}
+		for i := range loans {
+			if _, err := database.AssessFine(tx, &loans[i], now); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return marked, err
+}
+
+// StartOverdueTracker runs MarkOverdue daily in the background until
+// ctx is cancelled.
+func StartOverdueTracker(ctx context.Context, db *gorm.DB) {
+	go func() {
+		ticker := time.NewTicker(overdueInterval)
+		defer ticker.Stop()
+
+		for {
+			if n, err := MarkOverdue(db, time.Now()); err != nil {
+				log.Printf("❌ overdue tracking failed: %v", err)
+			} else if n > 0 {
+				log.Printf("⏰ %d loan(s) became overdue", n)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
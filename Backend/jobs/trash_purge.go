@@ -0,0 +1,87 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/hasan-kayan/TaskGo/config"
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/models"
+)
+
+/*───────────────────────────────────────────────────────────────*
+|            Configuration ‒ read once at program start         |
+*───────────────────────────────────────────────────────────────*/
+
+var (
+	// TRASH_RETENTION_DAYS – how long deleted books stay restorable.
+	// 0 keeps them forever (no automatic purge).
+	trashRetention = time.Duration(config.Int("TRASH_RETENTION_DAYS", 30, 0)) * 24 * time.Hour
+	purgeInterval  = time.Hour
+)
+
+/*───────────────────────────────────────────────────────────────*
+|                     Trash retention purge                     |
+*───────────────────────────────────────────────────────────────*/
+
+// PurgeTrash permanently deletes books that were soft-deleted more
+// than retention ago, with their dependent rows (see database.PurgeBook),
+// and returns how many were removed. Books with loans or fines on
+// record stay in the trash.
+func PurgeTrash(db *gorm.DB, retention time.Duration) (int64, error) {
+	cutoff := time.Now().Add(-retention)
+	var books []models.Book
+	err := db.Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+		Find(&books).Error
+	if err != nil {
+		return 0, err
+	}
+
+	var purged int64
+	for i := range books {
+		ok, err := database.PurgeBook(db, &books[i])
+		if errors.Is(err, database.ErrBookInCirculation) {
+			continue
+		}
+		if err != nil {
+			return purged, err
+		}
+		if ok {
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// StartTrashPurger runs PurgeTrash hourly in the background until ctx
+// is cancelled. It is a no-op when retention is disabled.
+func StartTrashPurger(ctx context.Context, db *gorm.DB) {
+	if trashRetention == 0 {
+		log.Println("🗑️  trash retention disabled – deleted books are kept")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(purgeInterval)
+		defer ticker.Stop()
+
+		for {
+			if n, err := PurgeTrash(db, trashRetention); err != nil {
+				log.Printf("❌ trash purge failed: %v", err)
+			} else if n > 0 {
+				log.Printf("🗑️  purged %d book(s) from trash", n)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
@@ -13,7 +13,11 @@ import (
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 
+	"github.com/hasan-kayan/TaskGo/config"
 	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/enrichment"
+	"github.com/hasan-kayan/TaskGo/handlers"
+	"github.com/hasan-kayan/TaskGo/jobs"
 	"github.com/hasan-kayan/TaskGo/middleware"
 	"github.com/hasan-kayan/TaskGo/routes"
 
@@ -49,6 +53,20 @@ func main() {
 	// ─────────────────────────────────────────────────────
 	database.ConnectDB() // DSN, log mode, migrate flags are env-driven
 
+	// Metadata providers for POST /books/:id/enrich (ENRICH_*)
+	enricher, err := enrichment.FromEnv()
+	if err != nil {
+		log.Fatalf("❌  Enrichment misconfigured: %v\n", err)
+	}
+	handlers.SetEnricher(enricher)
+
+	// Background jobs stop when the server shuts down
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	jobs.StartTrashPurger(jobsCtx, database.DB) // TRASH_RETENTION_DAYS
+	jobs.StartHoldExpirer(jobsCtx, database.DB, config.HoldPickupWindow)
+	jobs.StartOverdueTracker(jobsCtx, database.DB)
+
 	// ─────────────────────────────────────────────────────
 	// 3.  Gin engine & middleware
 	// ─────────────────────────────────────────────────────
@@ -93,6 +111,7 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	log.Println("🛑  Shutting down…")
+	stopJobs()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -2,13 +2,13 @@ package middleware
 
 import (
 	"net"
-	"os"
-	"strconv"
 	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"golang.org/x/time/rate"
+
+	"github.com/hasan-kayan/TaskGo/config"
 )
 
 /*───────────────────────────────────────────────────────────────*
@@ -16,19 +16,11 @@ import (
 *───────────────────────────────────────────────────────────────*/
 
 var (
-	rlRPS    = getIntEnv("RATE_LIMIT_RPS", 60)   // requests per second
-	rlBurst  = getIntEnv("RATE_LIMIT_BURST", 30) // maximum burst size
-	cleanTTL = time.Minute * 5                   // keep idle visitor structs
+	rlRPS    = config.Int("RATE_LIMIT_RPS", 60, 1)   // requests per second
+	rlBurst  = config.Int("RATE_LIMIT_BURST", 30, 1) // maximum burst size
+	cleanTTL = time.Minute * 5                       // keep idle visitor structs
 )
 
-func getIntEnv(key string, def int) int {
-	v, err := strconv.Atoi(os.Getenv(key))
-	if err != nil || v <= 0 {
-		return def
-	}
-	return v
-}
-
 /*───────────────────────────────────────────────────────────────*
 |                    Visitor bookkeeping                        |
 *───────────────────────────────────────────────────────────────*/
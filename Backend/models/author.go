@@ -0,0 +1,71 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Contributor roles a person can have on a book.
+const (
+	RoleAuthor      = "author"
+	RoleEditor      = "editor"
+	RoleTranslator  = "translator"
+	RoleIllustrator = "illustrator"
+)
+
+// Author is a person credited on one or more books.
+//
+// swagger:model Author
+type Author struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Name      string `json:"name" binding:"required" validate:"required" gorm:"index"`
+	Bio       string `json:"bio,omitempty"`
+	BirthYear int    `json:"birth_year,omitempty" validate:"gte=0"`
+
+	BookCount int64 `json:"book_count" gorm:"->;-:migration"` // filled by list queries
+}
+
+func (a *Author) BeforeCreate(tx *gorm.DB) (err error) {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	a.Name = strings.TrimSpace(a.Name)
+	return
+}
+
+// BookAuthor is the join row between Book and Author. One person may
+// hold several roles on the same book (author and illustrator, say),
+// so the role is part of the key. Position orders the credits.
+type BookAuthor struct {
+	BookID   uuid.UUID `json:"book_id" gorm:"type:uuid;primaryKey"`
+	AuthorID uuid.UUID `json:"author_id" gorm:"type:uuid;primaryKey;index"`
+	Role     string    `json:"role" gorm:"primaryKey"` // one of the Role* constants
+	Position int       `json:"position"`
+
+	Author *Author `json:"author,omitempty" gorm:"foreignKey:AuthorID"`
+}
+
+// authorSeparators split co-author bylines. Commas are left alone:
+// "Martin, Robert C." is one person.
+var authorSeparators = strings.NewReplacer(" & ", ";", " and ", ";", " And ", ";")
+
+// SplitAuthorNames turns "Gamma; Helm & Johnson" into its names.
+func SplitAuthorNames(byline string) []string {
+	var names []string
+	seen := map[string]bool{}
+	for _, n := range strings.Split(authorSeparators.Replace(byline), ";") {
+		n = strings.Join(strings.Fields(n), " ")
+		if n == "" || seen[strings.ToLower(n)] {
+			continue
+		}
+		seen[strings.ToLower(n)] = true
+		names = append(names, n)
+	}
+	return names
+}
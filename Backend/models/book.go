@@ -1,6 +1,7 @@
 package models
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,28 +12,64 @@ func (b *Book) BeforeCreate(tx *gorm.DB) (err error) {
 	if b.ID == uuid.Nil {
 		b.ID = uuid.New()
 	}
+	if b.Version == 0 {
+		b.Version = 1
+	}
+	return
+}
+
+// AfterFind / AfterSave keep the derived ETag in step with Version.
+func (b *Book) AfterFind(tx *gorm.DB) (err error) {
+	b.ETag = b.EntityTag()
+	return
+}
+
+func (b *Book) AfterSave(tx *gorm.DB) (err error) {
+	b.ETag = b.EntityTag()
 	return
 }
 
+// EntityTag is the strong HTTP ETag of this revision of the book.
+// Every successful write bumps Version, so the tag changes with it.
+func (b *Book) EntityTag() string {
+	return fmt.Sprintf(`"%s-v%d"`, b.ID, b.Version)
+}
+
 // Book represents the structure for a book entity.
 // It includes metadata such as title, author, and publication year.
 //
 // swagger:model Book
 type Book struct {
-	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey"`
-	CreatedAt time.Time  `json:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at"`
-	DeletedAt *time.Time `json:"deleted_at,omitempty" gorm:"index"`
+	ID        uuid.UUID      `json:"id" gorm:"type:uuid;primaryKey"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"index" swaggertype:"string"` // soft delete – see /books/trash
+
+	// optimistic concurrency – see If-Match handling in handlers
+	Version int    `json:"version" gorm:"not null;default:1"`
+	ETag    string `json:"etag,omitempty" gorm:"-"`
 
 	Title         string `json:"title" binding:"required" validate:"required"`
 	Author        string `json:"author" binding:"required" validate:"required"`
 	Year          int    `json:"year" validate:"gte=0"`
-	ISBN          string `json:"isbn,omitempty" validate:"omitempty,len=10|len=13"`
+	ISBN          string `json:"isbn,omitempty" validate:"omitempty,isbn"` // canonical ISBN-13, see utils.NormaliseISBN
+	ISBN10        string `json:"isbn10,omitempty" gorm:"column:isbn10"`    // derived from ISBN; empty for 979- numbers
 	Description   string `json:"description,omitempty"`
 	CoverImageURL string `json:"cover_image_url,omitempty" validate:"omitempty,url"`
-	Publisher     string `json:"publisher,omitempty"`
+	Publisher     string `json:"publisher,omitempty"` // display name; follows PublisherID when set
 	Type          string `json:"type,omitempty"`
 	Pages         int    `json:"pages,omitempty" validate:"gte=0"`
+
+	PublisherID  *uuid.UUID `json:"publisher_id,omitempty" gorm:"type:uuid;index"`
+	PublisherRef *Publisher `json:"-" gorm:"foreignKey:PublisherID;constraint:OnDelete:SET NULL"`
+
+	// series membership; Volume may be fractional (2.5 for a novella)
+	SeriesID  *uuid.UUID `json:"series_id,omitempty" gorm:"type:uuid;index"`
+	Volume    *float64   `json:"volume,omitempty" validate:"omitempty,gte=0"`
+	SeriesRef *Series    `json:"-" gorm:"foreignKey:SeriesID;constraint:OnDelete:SET NULL"`
+
+	// live review totals, filled by the read endpoints; not book state
+	Rating *BookRating `json:"rating,omitempty" gorm:"-"`
 }
 
 // ErrorResponse is used for API error responses.
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Copy conditions, best to worst.
+const (
+	ConditionNew     = "new"
+	ConditionGood    = "good"
+	ConditionFair    = "fair"
+	ConditionPoor    = "poor"
+	ConditionDamaged = "damaged"
+)
+
+// Copy is one physical item of a Book that can be lent out. A copy is
+// on loan while it has a Loan without ReturnedAt.
+//
+// swagger:model Copy
+type Copy struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	BookID    uuid.UUID `json:"book_id" gorm:"type:uuid;not null;index"`
+	Barcode   string    `json:"barcode" binding:"required" validate:"required" gorm:"uniqueIndex"`
+	Condition string    `json:"condition" validate:"omitempty,oneof=new good fair poor damaged"`
+	Location  string    `json:"location,omitempty"` // shelf mark / branch
+
+	OnLoan bool `json:"on_loan" gorm:"->;-:migration"` // filled by queries
+}
+
+func (c *Copy) BeforeCreate(tx *gorm.DB) (err error) {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	c.Barcode = strings.TrimSpace(c.Barcode)
+	if c.Condition == "" {
+		c.Condition = ConditionGood
+	}
+	return
+}
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DefaultPolicyType is the FinePolicy used for books whose Type has no
+// policy of its own.
+const DefaultPolicyType = "*"
+
+// DefaultFinePolicy seeds the default policy on first migration.
+var DefaultFinePolicy = FinePolicy{Type: DefaultPolicyType, DailyRate: 25, GraceDays: 0, MaxFine: 1000}
+
+// FinePolicy sets how overdue loans are charged for books of one Type.
+// Amounts are in minor currency units (cents).
+//
+// swagger:model FinePolicy
+type FinePolicy struct {
+	Type      string    `json:"type" gorm:"primaryKey"` // book Type, or "*"
+	DailyRate int64     `json:"daily_rate" validate:"gte=0"`
+	GraceDays int       `json:"grace_days" validate:"gte=0"` // free overdue days
+	MaxFine   int64     `json:"max_fine" validate:"gte=0"`   // per item; 0 = uncapped
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ClosedDay is a date the library is shut; it is never charged.
+//
+// swagger:model ClosedDay
+type ClosedDay struct {
+	Date      string    `json:"date" gorm:"primaryKey" validate:"required,datetime=2006-01-02"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Fine is what one loan owes for being overdue. Amount accrues while
+// the loan is out and is fixed (Final) once it is returned.
+//
+// swagger:model Fine
+type Fine struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	LoanID   uuid.UUID `json:"loan_id" gorm:"type:uuid;not null;uniqueIndex"`
+	MemberID uuid.UUID `json:"member_id" gorm:"type:uuid;not null;index"`
+	BookID   uuid.UUID `json:"book_id" gorm:"type:uuid;not null"`
+
+	PolicyType   string `json:"policy_type"`  // the policy it was assessed under
+	OverdueDays  int    `json:"overdue_days"` // open days past due
+	Amount       int64  `json:"amount"`
+	Paid         int64  `json:"paid"`
+	Waived       int64  `json:"waived"`
+	WaiverReason string `json:"waiver_reason,omitempty"`
+	Final        bool   `json:"final"`
+
+	Balance int64 `json:"balance" gorm:"-"`
+}
+
+func (f *Fine) BeforeCreate(tx *gorm.DB) (err error) {
+	if f.ID == uuid.Nil {
+		f.ID = uuid.New()
+	}
+	return
+}
+
+func (f *Fine) AfterFind(tx *gorm.DB) (err error) {
+	f.Balance = f.Amount - f.Paid - f.Waived
+	return
+}
+
+// Assess counts the overdue days from due until and what they cost under
+// p. Days go by local calendar date and closed days are skipped. The
+// first GraceDays are free and the total stops at MaxFine.
+func (p *FinePolicy) Assess(due, until time.Time, closed map[string]bool) (days int, amount int64) {
+	due, until = due.Local(), until.Local()
+	day := time.Date(due.Year(), due.Month(), due.Day()+1, 0, 0, 0, 0, time.Local)
+	for !day.After(until) {
+		if !closed[day.Format(time.DateOnly)] {
+			days++
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+
+	if charged := days - p.GraceDays; charged > 0 {
+		amount = int64(charged) * p.DailyRate
+	}
+	if p.MaxFine > 0 && amount > p.MaxFine {
+		amount = p.MaxFine
+	}
+	return days, amount
+}
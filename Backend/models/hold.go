@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Hold states. A hold waits in its book's queue until a copy is
+// assigned (ready); it is then fulfilled by checking that copy out, or
+// expires if not picked up in time. Members may cancel open holds.
+const (
+	HoldWaiting   = "waiting"
+	HoldReady     = "ready"
+	HoldFulfilled = "fulfilled"
+	HoldCancelled = "cancelled"
+	HoldExpired   = "expired"
+)
+
+// Hold is a member's place in the first-in-first-out queue for a book.
+//
+// swagger:model Hold
+type Hold struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	CreatedAt time.Time `json:"created_at" gorm:"index"` // queue order
+	UpdatedAt time.Time `json:"updated_at"`
+
+	BookID   uuid.UUID `json:"book_id" gorm:"type:uuid;not null;index"`
+	MemberID uuid.UUID `json:"member_id" gorm:"type:uuid;not null;index"`
+	Status   string    `json:"status" gorm:"not null;index"` // one of the Hold* constants
+
+	CopyID    *uuid.UUID `json:"copy_id,omitempty" gorm:"type:uuid"` // set once ready
+	ReadyAt   *time.Time `json:"ready_at,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" gorm:"index"` // pick up before this
+	ClosedAt  *time.Time `json:"closed_at,omitempty"`
+
+	Position int64 `json:"position,omitempty" gorm:"->;-:migration"` // place in the queue while waiting
+}
+
+func (h *Hold) BeforeCreate(tx *gorm.DB) (err error) {
+	if h.ID == uuid.Nil {
+		h.ID = uuid.New()
+	}
+	if h.Status == "" {
+		h.Status = HoldWaiting
+	}
+	return
+}
+
+// Open reports whether the hold still waits or awaits pickup.
+func (h *Hold) Open() bool { return h.Status == HoldWaiting || h.Status == HoldReady }
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Loan is one checkout of a Copy by a Member. It is active until
+// ReturnedAt is set; the database allows one active loan per copy.
+//
+// swagger:model Loan
+type Loan struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	CopyID   uuid.UUID `json:"copy_id" gorm:"type:uuid;not null;index"`
+	BookID   uuid.UUID `json:"book_id" gorm:"type:uuid;not null;index"` // the copy's book, for per-book queries
+	MemberID uuid.UUID `json:"member_id" gorm:"type:uuid;not null;index"`
+
+	CheckedOutAt time.Time  `json:"checked_out_at"`
+	DueAt        time.Time  `json:"due_at" gorm:"index"`
+	ReturnedAt   *time.Time `json:"returned_at,omitempty" gorm:"index"`
+	Renewals     int        `json:"renewals"`
+	OverdueSince *time.Time `json:"overdue_since,omitempty"` // set by the daily overdue job
+
+	Copy *Copy `json:"copy,omitempty" gorm:"foreignKey:CopyID"`
+}
+
+func (l *Loan) BeforeCreate(tx *gorm.DB) (err error) {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	return
+}
+
+// Active reports whether the copy is still out.
+func (l *Loan) Active() bool { return l.ReturnedAt == nil }
+
+// Overdue reports whether an active loan is past its due date at t.
+func (l *Loan) Overdue(t time.Time) bool { return l.Active() && t.After(l.DueAt) }
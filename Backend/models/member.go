@@ -0,0 +1,33 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Member is a library patron who can borrow copies.
+//
+// swagger:model Member
+type Member struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Name  string `json:"name" binding:"required" validate:"required" gorm:"index"`
+	Email string `json:"email" binding:"required" validate:"required,email" gorm:"uniqueIndex"`
+	Phone string `json:"phone,omitempty"`
+
+	ActiveLoans int64 `json:"active_loans" gorm:"->;-:migration"` // filled by queries
+}
+
+func (m *Member) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == uuid.Nil {
+		m.ID = uuid.New()
+	}
+	m.Name = strings.TrimSpace(m.Name)
+	m.Email = strings.ToLower(strings.TrimSpace(m.Email))
+	return
+}
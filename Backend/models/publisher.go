@@ -0,0 +1,34 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Publisher is a publishing house. Imprints are publishers whose
+// ParentID points at the house they belong to.
+//
+// swagger:model Publisher
+type Publisher struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Name     string     `json:"name" binding:"required" validate:"required" gorm:"index"`
+	ParentID *uuid.UUID `json:"parent_id,omitempty" gorm:"type:uuid;index"` // set on imprints
+	Website  string     `json:"website,omitempty" validate:"omitempty,url"`
+
+	Imprints  []Publisher `json:"imprints,omitempty" gorm:"foreignKey:ParentID"`
+	BookCount int64       `json:"book_count" gorm:"->;-:migration"` // filled by list queries
+}
+
+func (p *Publisher) BeforeCreate(tx *gorm.DB) (err error) {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	p.Name = strings.TrimSpace(p.Name)
+	return
+}
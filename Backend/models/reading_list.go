@@ -0,0 +1,55 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ReadingList is a member's named, ordered list of books. Private lists
+// are only shown to their owner.
+//
+// swagger:model ReadingList
+type ReadingList struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	MemberID    uuid.UUID `json:"member_id" gorm:"type:uuid;not null;index"` // owner
+	Name        string    `json:"name" validate:"required,max=200"`
+	Description string    `json:"description,omitempty"`
+	Public      bool      `json:"public" gorm:"not null;default:false;index"`
+
+	ItemCount int64 `json:"item_count" gorm:"->;-:migration"` // filled by list queries
+}
+
+func (l *ReadingList) BeforeCreate(tx *gorm.DB) (err error) {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	l.Name = strings.TrimSpace(l.Name)
+	return
+}
+
+// ReadingListItem places a book at a 1-based Position in a list. A
+// trashed book leaves the item in place; purging the book removes it.
+//
+// swagger:model ReadingListItem
+type ReadingListItem struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	CreatedAt time.Time `json:"added_at"`
+
+	ListID   uuid.UUID `json:"list_id" gorm:"type:uuid;not null;uniqueIndex:idx_list_items_book"`
+	BookID   uuid.UUID `json:"book_id" gorm:"type:uuid;not null;uniqueIndex:idx_list_items_book;index"`
+	Position int       `json:"position" gorm:"not null"`
+	Note     string    `json:"note,omitempty"`
+}
+
+func (i *ReadingListItem) BeforeCreate(tx *gorm.DB) (err error) {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	return
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Review is a member's star rating of a book, with optional text. A
+// member reviews a book at most once.
+//
+// swagger:model Review
+type Review struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	CreatedAt time.Time `json:"created_at" gorm:"index"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	BookID   uuid.UUID `json:"book_id" gorm:"type:uuid;not null;uniqueIndex:idx_reviews_book_member"`
+	MemberID uuid.UUID `json:"member_id" gorm:"type:uuid;not null;uniqueIndex:idx_reviews_book_member"`
+	Rating   int       `json:"rating" gorm:"not null" validate:"required,gte=1,lte=5"`
+	Text     string    `json:"text,omitempty"`
+
+	MemberName string `json:"member_name,omitempty" gorm:"->;-:migration"` // the reviewer
+}
+
+func (r *Review) BeforeCreate(tx *gorm.DB) (err error) {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return
+}
+
+// BookRating is the running total of a book's reviews. Review writes
+// adjust it in the same transaction (see database.AdjustRating), so
+// reads never aggregate the reviews table.
+//
+// swagger:model BookRating
+type BookRating struct {
+	BookID  uuid.UUID `json:"-" gorm:"type:uuid;primaryKey"`
+	Count   int64     `json:"count" gorm:"not null"`
+	Sum     int64     `json:"-" gorm:"not null"`
+	Average float64   `json:"average" gorm:"not null;index"`
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Revision actions recorded in BookRevision.Action.
+const (
+	ActionCreate  = "create"
+	ActionUpdate  = "update"
+	ActionDelete  = "delete"  // moved to the trash
+	ActionRestore = "restore" // taken out of the trash
+	ActionPurge   = "purge"   // permanently deleted
+)
+
+// BookRevision records one write to a book: who made it, when, and the
+// fields it changed. Revisions are numbered per book from 1 and are
+// kept after the book itself is purged.
+//
+// swagger:model BookRevision
+type BookRevision struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+
+	BookID   uuid.UUID `json:"book_id" gorm:"type:uuid;not null;uniqueIndex:idx_book_revisions_book_rev"`
+	Revision int       `json:"revision" gorm:"not null;uniqueIndex:idx_book_revisions_book_rev"`
+	Action   string    `json:"action"` // one of the Action* constants
+	Actor    string    `json:"actor"`
+	Note     string    `json:"note,omitempty"`
+
+	Changes  FieldChanges `json:"changes" gorm:"type:text"`
+	Snapshot JSONObject   `json:"snapshot,omitempty" gorm:"type:text"` // the book after this write; empty for purges
+}
+
+func (r *BookRevision) BeforeCreate(tx *gorm.DB) (err error) {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return
+}
+
+// FieldChange is the before/after pair of one field. From is null on
+// create, To is null on purge.
+type FieldChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// FieldChanges maps JSON field names to their change; stored as JSON.
+type FieldChanges map[string]FieldChange
+
+func (f FieldChanges) Value() (driver.Value, error) { return jsonValue(f) }
+func (f *FieldChanges) Scan(src any) error          { return jsonScan(src, f) }
+
+// JSONObject is a free-form JSON object stored as text.
+type JSONObject map[string]any
+
+func (o JSONObject) Value() (driver.Value, error) { return jsonValue(o) }
+func (o *JSONObject) Scan(src any) error          { return jsonScan(src, o) }
+
+func jsonValue(v any) (driver.Value, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func jsonScan(src, dst any) error {
+	switch s := src.(type) {
+	case nil:
+		return nil
+	case string:
+		return json.Unmarshal([]byte(s), dst)
+	case []byte:
+		return json.Unmarshal(s, dst)
+	default:
+		return fmt.Errorf("cannot scan %T into JSON", src)
+	}
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Series groups the volumes of a multi-volume work. Books join a
+// series through Book.SeriesID and are ordered by Book.Volume.
+//
+// swagger:model Series
+type Series struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Name        string `json:"name" binding:"required" validate:"required" gorm:"index"`
+	Description string `json:"description,omitempty"`
+
+	BookCount int64 `json:"book_count" gorm:"->;-:migration"` // filled by list queries
+}
+
+func (s *Series) BeforeCreate(tx *gorm.DB) (err error) {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	s.Name = strings.TrimSpace(s.Name)
+	return
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Tag is a genre, subject or any other label. Tags form an optional
+// hierarchy through ParentID ("Fiction" → "Science Fiction").
+//
+// swagger:model Tag
+type Tag struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Name     string     `json:"name" binding:"required" validate:"required"`
+	Slug     string     `json:"slug" gorm:"uniqueIndex;not null"` // derived from Name
+	ParentID *uuid.UUID `json:"parent_id,omitempty" gorm:"type:uuid;index"`
+
+	Children   []Tag `json:"children,omitempty" gorm:"foreignKey:ParentID"`
+	UsageCount int64 `json:"usage_count" gorm:"->;-:migration"` // filled by list queries
+}
+
+func (t *Tag) BeforeCreate(tx *gorm.DB) (err error) {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	t.Name = strings.TrimSpace(t.Name)
+	t.Slug = TagSlug(t.Name)
+	return
+}
+
+// BookTag is the join row between Book and Tag.
+type BookTag struct {
+	BookID uuid.UUID `json:"book_id" gorm:"type:uuid;primaryKey"`
+	TagID  uuid.UUID `json:"tag_id" gorm:"type:uuid;primaryKey;index"`
+}
+
+// TagSlug lower-cases name and joins its words with hyphens, so
+// "Science Fiction" and "science-fiction" name the same tag.
+func TagSlug(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	return b.String()
+}
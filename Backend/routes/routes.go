@@ -9,6 +9,13 @@ import (
 func SetupRoutes(r *gin.Engine) {
 	registerHealthRoutes(r)
 	registerBookRoutes(r)
+	registerAuthorRoutes(r)
+	registerPublisherRoutes(r)
+	registerTagRoutes(r)
+	registerSeriesRoutes(r)
+	registerCirculationRoutes(r)
+	registerReadingListRoutes(r)
+	registerStatsRoutes(r)
 	registerUtilityRoutes(r)
 }
 
@@ -23,9 +30,168 @@ func registerBookRoutes(r *gin.Engine) {
 	{
 		books.GET("", handlers.GetBooks)
 		books.POST("", handlers.CreateBook)
+		books.POST("/bulk", handlers.BulkBooks)
+		books.POST("/import", handlers.ImportBooks) // CSV / TSV, ?dry_run=true
+		books.GET("/export", handlers.ExportBooks)  // ?format=csv|ndjson|json
+		books.GET("/search", handlers.SearchBooks)
+		books.GET("/facets", handlers.GetBookFacets) // same filters as GET /books
+		books.GET("/trash", handlers.GetTrash)
+		books.GET("/duplicates", handlers.GetBookDuplicates) // ?min_score=0.85
+		books.POST("/merge", handlers.MergeBooks)            // {target_id, source_ids, fields}
+		books.GET("/isbn/:isbn", handlers.GetBookByISBN)     // ISBN-10 or ISBN-13
 		books.GET("/:id", handlers.GetBook)
-		books.PUT("/:id", handlers.UpdateBook)
-		books.DELETE("/:id", handlers.DeleteBook)
+		books.PUT("/:id", handlers.UpdateBook)    // full replacement
+		books.PATCH("/:id", handlers.PatchBook)   // merge patch / JSON patch
+		books.DELETE("/:id", handlers.DeleteBook) // ?purge=true skips the trash
+		books.POST("/:id/restore", handlers.RestoreBook)
+		books.GET("/:id/history", handlers.GetBookHistory)
+		books.POST("/:id/revert/:revision", handlers.RevertBook)
+		books.POST("/:id/enrich", handlers.EnrichBook) // preview; ?apply=true writes
+		books.GET("/:id/authors", handlers.GetBookAuthors)
+		books.PUT("/:id/authors", handlers.SetBookAuthors) // replaces all credits
+		books.GET("/:id/tags", handlers.GetBookTags)
+		books.PUT("/:id/tags", handlers.SetBookTags) // replaces all tags
+		books.GET("/:id/copies", handlers.GetBookCopies)
+		books.POST("/:id/copies", handlers.CreateCopy)
+		books.GET("/:id/holds", handlers.GetBookHolds) // queue order
+		books.POST("/:id/holds", handlers.PlaceHold)   // {member_id}
+		books.GET("/:id/reviews", handlers.GetBookReviews)
+		books.POST("/:id/reviews", handlers.CreateReview) // {member_id, rating, text}
+		books.GET("/:id/reviews/:review_id", handlers.GetReview)
+		books.PUT("/:id/reviews/:review_id", handlers.UpdateReview)
+		books.DELETE("/:id/reviews/:review_id", handlers.DeleteReview)
+	}
+}
+
+// CRUD routes for Author resource.
+func registerAuthorRoutes(r *gin.Engine) {
+	authors := r.Group("/authors")
+	{
+		authors.GET("", handlers.GetAuthors)
+		authors.POST("", handlers.CreateAuthor)
+		authors.GET("/:id", handlers.GetAuthor)
+		authors.PUT("/:id", handlers.UpdateAuthor)
+		authors.DELETE("/:id", handlers.DeleteAuthor) // 409 while credited on books
+		authors.GET("/:id/books", handlers.GetAuthorBooks)
+	}
+}
+
+// CRUD routes for Publisher resource (imprints are child publishers).
+func registerPublisherRoutes(r *gin.Engine) {
+	publishers := r.Group("/publishers")
+	{
+		publishers.GET("", handlers.GetPublishers)
+		publishers.POST("", handlers.CreatePublisher)
+		publishers.GET("/:id", handlers.GetPublisher)
+		publishers.PUT("/:id", handlers.UpdatePublisher)
+		publishers.DELETE("/:id", handlers.DeletePublisher)     // 409 while it has books or imprints
+		publishers.POST("/:id/merge", handlers.MergePublishers) // {"source_ids": […]}
+	}
+}
+
+// CRUD routes for Tag resource (hierarchical via parent_id).
+func registerTagRoutes(r *gin.Engine) {
+	tags := r.Group("/tags")
+	{
+		tags.GET("", handlers.GetTags) // ?sort=-usage
+		tags.POST("", handlers.CreateTag)
+		tags.GET("/:id", handlers.GetTag)
+		tags.PUT("/:id", handlers.UpdateTag)
+		tags.DELETE("/:id", handlers.DeleteTag) // untags books, lifts children
+	}
+}
+
+// CRUD routes for Series resource.
+func registerSeriesRoutes(r *gin.Engine) {
+	series := r.Group("/series")
+	{
+		series.GET("", handlers.GetSeriesList)
+		series.POST("", handlers.CreateSeries)
+		series.GET("/:id", handlers.GetSeries) // volumes in order
+		series.PUT("/:id", handlers.UpdateSeries)
+		series.DELETE("/:id", handlers.DeleteSeries) // 409 while it has volumes
+	}
+}
+
+// Circulation: physical copies, members, their loans, holds and fines.
+func registerCirculationRoutes(r *gin.Engine) {
+	copies := r.Group("/copies")
+	{
+		copies.GET("", handlers.GetCopyByBarcode) // ?barcode=
+		copies.GET("/:id", handlers.GetCopy)
+		copies.PUT("/:id", handlers.UpdateCopy)
+		copies.DELETE("/:id", handlers.DeleteCopy) // 409 while on loan or hold
+	}
+
+	members := r.Group("/members")
+	{
+		members.GET("", handlers.GetMembers)
+		members.POST("", handlers.CreateMember)
+		members.GET("/:id", handlers.GetMember)
+		members.PUT("/:id", handlers.UpdateMember)
+		members.DELETE("/:id", handlers.DeleteMember) // 409 with active loans or fines
+		members.GET("/:id/loans", handlers.GetMemberLoans)
+		members.GET("/:id/holds", handlers.GetMemberHolds)
+		members.GET("/:id/fines", handlers.GetMemberFines) // ?outstanding=true
+	}
+
+	loans := r.Group("/loans")
+	{
+		loans.GET("", handlers.GetLoans)
+		loans.POST("", handlers.Checkout) // {copy_id | barcode, member_id}
+		loans.GET("/:id", handlers.GetLoan)
+		loans.POST("/:id/return", handlers.ReturnLoan)
+		loans.POST("/:id/renew", handlers.RenewLoan)
+	}
+
+	holds := r.Group("/holds")
+	{
+		holds.GET("/:id", handlers.GetHold)
+		holds.POST("/:id/cancel", handlers.CancelHold)
+	}
+
+	fines := r.Group("/fines")
+	{
+		fines.GET("/:id", handlers.GetFine)
+		fines.POST("/:id/pay", handlers.PayFine)     // {amount}
+		fines.POST("/:id/waive", handlers.WaiveFine) // {reason, amount?}
+	}
+
+	r.GET("/fine-policies", handlers.GetFinePolicies)
+	r.PUT("/fine-policies/:type", handlers.PutFinePolicy) // "*" is the default
+	r.DELETE("/fine-policies/:type", handlers.DeleteFinePolicy)
+
+	r.GET("/closed-days", handlers.GetClosedDays)
+	r.PUT("/closed-days/:date", handlers.PutClosedDay) // YYYY-MM-DD
+	r.DELETE("/closed-days/:date", handlers.DeleteClosedDay)
+}
+
+// Member-curated reading lists; writes need the owner's X-Member-ID.
+func registerReadingListRoutes(r *gin.Engine) {
+	lists := r.Group("/lists")
+	{
+		lists.GET("", handlers.GetReadingLists) // public + own private
+		lists.POST("", handlers.CreateReadingList)
+		lists.GET("/:id", handlers.GetReadingList) // items with their books
+		lists.PUT("/:id", handlers.UpdateReadingList)
+		lists.DELETE("/:id", handlers.DeleteReadingList)
+		lists.POST("/:id/items", handlers.AddReadingListItem) // {book_id, position, note}
+		lists.PUT("/:id/items", handlers.ReorderReadingList)  // {book_ids: [...]}
+		lists.DELETE("/:id/items/:book_id", handlers.RemoveReadingListItem)
+	}
+}
+
+// Read-only catalogue reports; responses carry Cache-Control and an ETag.
+func registerStatsRoutes(r *gin.Engine) {
+	stats := r.Group("/stats")
+	{
+		stats.GET("", handlers.GetStats)
+		stats.GET("/added", handlers.GetStatsAdded) // ?months=12
+		stats.GET("/years", handlers.GetStatsYears)
+		stats.GET("/types", handlers.GetStatsTypes)
+		stats.GET("/authors", handlers.GetStatsAuthors)       // ?limit=10
+		stats.GET("/publishers", handlers.GetStatsPublishers) // ?limit=10
+		stats.GET("/completeness", handlers.GetStatsCompleteness)
 	}
 }
 
@@ -0,0 +1,185 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type authorCredit struct {
+	AuthorID uuid.UUID `json:"author_id"`
+	Role     string    `json:"role"`
+	Position int       `json:"position"`
+	Author   struct {
+		Name string `json:"name"`
+	} `json:"author"`
+}
+
+// findAuthorByName – /authors?name= ile tek yazarı bulur
+func findAuthorByName(t *testing.T, name string) models.Author {
+	t.Helper()
+	rec := doRequest(t, "GET", "/authors?name="+url.QueryEscape(name))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var authors []models.Author
+	parseEnvelope(t, rec.Body.Bytes(), &authors)
+	require.Len(t, authors, 1, name)
+	return authors[0]
+}
+
+func TestCreateBookLinksBylineAuthors(t *testing.T) {
+	setupTestDB()
+	suffix := uuid.NewString()[:8]
+	first, second := "Erich Gamma "+suffix, "Richard Helm "+suffix
+	kind := "Authors-" + suffix
+
+	rec := sendJSON(t, "POST", "/books", "application/json",
+		fmt.Sprintf(`{"title": "Design Patterns", "author": "%s & %s", "type": %q}`, first, second, kind))
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var book models.Book
+	parseEnvelope(t, rec.Body.Bytes(), &book)
+
+	// ikinci kitap aynı yazarı yeniden kullanır (büyük/küçük harf farkı önemsiz)
+	other := models.Book{Title: "Another", Author: "erich gamma " + suffix, Type: kind}
+	require.NoError(t, database.DB.Create(&other).Error)
+
+	gamma := findAuthorByName(t, first)
+	assert.EqualValues(t, 2, gamma.BookCount)
+	findAuthorByName(t, second)
+
+	rec = doRequest(t, "GET", "/authors/"+gamma.ID.String()+"/books?sort=title")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var books []models.Book
+	parseEnvelope(t, rec.Body.Bytes(), &books)
+	require.Len(t, books, 2)
+	assert.Equal(t, "Another", books[0].Title)
+
+	// author filtresi ortak yazarın adıyla da eşleşir
+	listed, _, _ := getBookPage(t, url.Values{"type": {kind}, "author": {second}})
+	require.Len(t, listed, 1)
+	assert.Equal(t, book.ID, listed[0].ID)
+	listed, _, _ = getBookPage(t, url.Values{"type": {kind}, "author_id": {gamma.ID.String()}})
+	assert.Len(t, listed, 2)
+}
+
+func TestBookCreditsRolesAndAuthorDelete(t *testing.T) {
+	setupTestDB()
+	suffix := uuid.NewString()[:8]
+	book := models.Book{Title: "Der Prozess", Author: "Franz Kafka " + suffix}
+	require.NoError(t, database.DB.Create(&book).Error)
+	kafka := findAuthorByName(t, "Franz Kafka "+suffix)
+
+	rec := sendJSON(t, "POST", "/authors", "application/json", `{"name": "  Breon Mitchell `+suffix+`  "}`)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var translator models.Author
+	parseEnvelope(t, rec.Body.Bytes(), &translator)
+	assert.Equal(t, "Breon Mitchell "+suffix, translator.Name)
+
+	path := "/books/" + book.ID.String() + "/authors"
+	rec = sendJSON(t, "PUT", path, "application/json", fmt.Sprintf(
+		`[{"author_id": %q}, {"author_id": %q, "role": "translator"}]`, kafka.ID, translator.ID))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = doRequest(t, "GET", path)
+	var credits []authorCredit
+	parseEnvelope(t, rec.Body.Bytes(), &credits)
+	require.Len(t, credits, 2)
+	assert.Equal(t, "author", credits[0].Role)
+	assert.Equal(t, "translator", credits[1].Role)
+	assert.Equal(t, translator.Name, credits[1].Author.Name)
+
+	rec = doRequest(t, "GET", "/authors/"+translator.ID.String()+"/books?role=translator")
+	var books []models.Book
+	parseEnvelope(t, rec.Body.Bytes(), &books)
+	assert.Len(t, books, 1)
+
+	// geçersiz rol / bilinmeyen yazar → 422
+	rec = sendJSON(t, "PUT", path, "application/json", fmt.Sprintf(
+		`[{"author_id": %q, "role": "ghostwriter"}, {"author_id": %q}]`, kafka.ID, uuid.New()))
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(t, fieldErrors(t, rec), "[0].role")
+
+	// krediliyken silinemez
+	assert.Equal(t, http.StatusConflict, doRequest(t, "DELETE", "/authors/"+translator.ID.String()).Code)
+	rec = sendJSON(t, "PUT", path, "application/json", fmt.Sprintf(`[{"author_id": %q}]`, kafka.ID))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, http.StatusOK, doRequest(t, "DELETE", "/authors/"+translator.ID.String()).Code)
+	assert.Equal(t, http.StatusNotFound, doRequest(t, "GET", "/authors/"+translator.ID.String()).Code)
+}
+
+func TestDeleteAuthorIgnoresTrashedBooks(t *testing.T) {
+	setupTestDB()
+	name := "Trashed Only " + uuid.NewString()[:8]
+	book := models.Book{Title: "Binned", Author: name}
+	require.NoError(t, database.DB.Create(&book).Error)
+	author := findAuthorByName(t, name)
+
+	assert.Equal(t, http.StatusConflict, doRequest(t, "DELETE", "/authors/"+author.ID.String()).Code)
+
+	// yalnızca çöpteki kitapta kredili → silinebilir, kredi de gider
+	require.Equal(t, http.StatusOK, doRequest(t, "DELETE", "/books/"+book.ID.String()).Code)
+	assert.Equal(t, http.StatusOK, doRequest(t, "DELETE", "/authors/"+author.ID.String()).Code)
+	var credits int64
+	database.DB.Model(&models.BookAuthor{}).Where("author_id = ?", author.ID).Count(&credits)
+	assert.Zero(t, credits)
+}
+
+func TestBookUpdateResyncsBylineCredits(t *testing.T) {
+	setupTestDB()
+	suffix := uuid.NewString()[:8]
+	gamma, helm, vlissides := "Erich Gamma "+suffix, "Richard Helm "+suffix, "John Vlissides "+suffix
+	book := models.Book{Title: "Design Patterns", Author: gamma + " & " + helm}
+	editor := models.Author{Name: "Grady Booch " + suffix}
+	require.NoError(t, database.DB.Create(&book).Error)
+	require.NoError(t, database.DB.Create(&editor).Error)
+
+	// el ile eklenen editör kredisi byline değişince korunur
+	path := "/books/" + book.ID.String() + "/authors"
+	rec := sendJSON(t, "PUT", path, "application/json", fmt.Sprintf(
+		`[{"author_id": %q}, {"author_id": %q}, {"author_id": %q, "role": "editor"}]`,
+		findAuthorByName(t, gamma).ID, findAuthorByName(t, helm).ID, editor.ID))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = sendJSON(t, "PATCH", "/books/"+book.ID.String(), "application/merge-patch+json",
+		fmt.Sprintf(`{"author": "%s and %s"}`, gamma, vlissides))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var credits []authorCredit
+	parseEnvelope(t, doRequest(t, "GET", path).Body.Bytes(), &credits)
+	names := map[string]bool{}
+	for _, cr := range credits {
+		names[cr.Author.Name+"/"+cr.Role] = true
+	}
+	assert.Len(t, credits, 3)
+	assert.Contains(t, names, gamma+"/author")
+	assert.Contains(t, names, vlissides+"/author")
+	assert.Contains(t, names, editor.Name+"/editor")
+
+	listed, _, _ := getBookPage(t, url.Values{"author": {helm}})
+	assert.Empty(t, listed, "the old byline name no longer matches")
+	listed, _, _ = getBookPage(t, url.Values{"author": {vlissides}})
+	require.Len(t, listed, 1)
+	assert.Equal(t, book.ID, listed[0].ID)
+}
+
+func TestMigrateBackfillsAuthorsFromBylines(t *testing.T) {
+	setupTestDB()
+	suffix := uuid.NewString()[:8]
+	book := models.Book{Title: "Legacy", Author: "Ada Lovelace " + suffix + " and Charles Babbage " + suffix}
+	require.NoError(t, database.DB.Create(&book).Error)
+
+	// eski şemayı taklit et: bağlantı tablosu yok
+	require.NoError(t, database.DB.Migrator().DropTable(&models.BookAuthor{}))
+	require.NoError(t, database.Migrate(database.DB))
+
+	var credits []models.BookAuthor
+	database.DB.Where("book_id = ?", book.ID).Order("position").Find(&credits)
+	require.Len(t, credits, 2)
+	assert.Equal(t, findAuthorByName(t, "Charles Babbage "+suffix).ID, credits[1].AuthorID)
+}
@@ -0,0 +1,68 @@
+package tests
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// instant returns a timestamp strictly between the writes around it.
+func instant() string {
+	time.Sleep(2 * time.Millisecond)
+	t := time.Now().UTC().Format(time.RFC3339Nano)
+	time.Sleep(2 * time.Millisecond)
+	return t
+}
+
+func TestBooksAsOf(t *testing.T) {
+	setupTestDB()
+	beforeCreate := instant()
+
+	kind := "AsOf-" + uuid.NewString()
+	rec := sendJSON(t, "POST", "/books", "application/json",
+		`{"title": "First Draft", "author": "Anne Lamott", "type": "`+kind+`"}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var book models.Book
+	parseEnvelope(t, rec.Body.Bytes(), &book)
+	path := "/books/" + book.ID.String()
+
+	created := instant()
+	rec = sendJSON(t, "PATCH", path, "application/merge-patch+json", `{"title": "Bird by Bird"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	renamed := instant()
+	require.Equal(t, http.StatusOK, doRequest(t, "DELETE", path).Code)
+	deleted := instant()
+
+	// tek kitap: o anki hâli
+	for at, want := range map[string]string{created: "First Draft", renamed: "Bird by Bird"} {
+		rec = doRequest(t, "GET", path+"?as_of="+url.QueryEscape(at))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var past models.Book
+		parseEnvelope(t, rec.Body.Bytes(), &past)
+		assert.Equal(t, want, past.Title, "as_of=%s", at)
+	}
+	assert.Equal(t, http.StatusNotFound, doRequest(t, "GET", path+"?as_of="+url.QueryEscape(beforeCreate)).Code)
+	assert.Equal(t, http.StatusNotFound, doRequest(t, "GET", path+"?as_of="+url.QueryEscape(deleted)).Code)
+	assert.Equal(t, http.StatusNotFound, doRequest(t, "GET", path).Code, "gone from the live catalogue")
+
+	// liste: filtreler geçmiş hâle uygulanır, silinen kitap görünür
+	books, meta, code := getBookPage(t, url.Values{"type": {kind}, "title": {"draft"}, "as_of": {created}})
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, books, 1)
+	assert.Equal(t, "First Draft", books[0].Title)
+	assert.EqualValues(t, 1, meta.Total)
+
+	books, _, _ = getBookPage(t, url.Values{"type": {kind}, "title": {"draft"}, "as_of": {renamed}})
+	assert.Empty(t, books)
+	books, _, _ = getBookPage(t, url.Values{"type": {kind}, "as_of": {deleted}})
+	assert.Empty(t, books)
+
+	_, _, code = getBookPage(t, url.Values{"as_of": {"last tuesday"}})
+	assert.Equal(t, http.StatusBadRequest, code)
+}
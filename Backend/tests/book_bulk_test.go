@@ -0,0 +1,107 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type bulkResponse struct {
+	Committed bool `json:"committed"`
+	Succeeded int  `json:"succeeded"`
+	Failed    int  `json:"failed"`
+	Results   []struct {
+		Status int               `json:"status"`
+		ID     string            `json:"id"`
+		Fields map[string]string `json:"fields"`
+		Book   *models.Book      `json:"book"`
+	} `json:"results"`
+}
+
+func postBulk(t *testing.T, body string) (bulkResponse, int) {
+	t.Helper()
+	rec := sendJSON(t, "POST", "/books/bulk", "application/json", body)
+
+	var env struct {
+		Data bulkResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &env), rec.Body.String())
+	return env.Data, rec.Code
+}
+
+func TestBulkAtomicCommitsEverything(t *testing.T) {
+	setupTestDB()
+	existing := models.Book{Title: "Old title", Author: "A", Pages: 100}
+	doomed := models.Book{Title: "Doomed", Author: "B"}
+	require.NoError(t, database.DB.Create(&existing).Error)
+	require.NoError(t, database.DB.Create(&doomed).Error)
+
+	resp, code := postBulk(t, fmt.Sprintf(`{"operations": [
+		{"op": "create", "book": {"title": "Fresh", "author": "C", "rating": {"count": 9, "average": 5}}},
+		{"op": "update", "id": %q, "if_match": %q, "book": {"title": "New title", "pages": 0}},
+		{"op": "delete", "id": %q}
+	]}`, existing.ID, existing.EntityTag(), doomed.ID))
+
+	require.Equal(t, http.StatusOK, code)
+	assert.True(t, resp.Committed)
+	assert.Equal(t, 3, resp.Succeeded)
+	assert.Equal(t, http.StatusCreated, resp.Results[0].Status)
+	require.NotNil(t, resp.Results[0].Book)
+	assert.Nil(t, resp.Results[0].Book.Rating, "ratings are never taken from the client")
+
+	var updated models.Book
+	database.DB.First(&updated, "id = ?", existing.ID)
+	assert.Equal(t, "New title", updated.Title)
+	assert.Zero(t, updated.Pages)
+	assert.Error(t, database.DB.First(&models.Book{}, "id = ?", doomed.ID).Error)
+}
+
+func TestBulkAtomicRollsBackOnAnyFailure(t *testing.T) {
+	setupTestDB()
+	kind := "Bulk-" + uuid.NewString()
+
+	resp, code := postBulk(t, fmt.Sprintf(`{"mode": "atomic", "operations": [
+		{"op": "create", "book": {"title": "Good", "author": "A", "type": %q}},
+		{"op": "create", "book": {"title": "", "author": "A", "type": %q}}
+	]}`, kind, kind))
+
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+	assert.False(t, resp.Committed)
+	assert.Equal(t, 1, resp.Failed)
+	assert.Equal(t, "is required", resp.Results[1].Fields["title"])
+
+	var count int64
+	database.DB.Model(&models.Book{}).Where("type = ?", kind).Count(&count)
+	assert.Zero(t, count, "first create must be rolled back")
+}
+
+func TestBulkBestEffortKeepsGoodItems(t *testing.T) {
+	setupTestDB()
+	kind := "Bulk-" + uuid.NewString()
+
+	resp, code := postBulk(t, fmt.Sprintf(`{"mode": "best_effort", "operations": [
+		{"op": "create", "book": {"title": "Good", "author": "A", "type": %q}},
+		{"op": "create", "book": {"title": "Bad", "author": "A", "pages": -5, "type": %q}},
+		{"op": "delete", "id": %q},
+		{"op": "explode"}
+	]}`, kind, kind, uuid.New()))
+
+	require.Equal(t, http.StatusOK, code)
+	assert.True(t, resp.Committed)
+	assert.Equal(t, 1, resp.Succeeded)
+	assert.Equal(t, 3, resp.Failed)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Results[1].Status)
+	assert.Equal(t, http.StatusNotFound, resp.Results[2].Status)
+	assert.Equal(t, http.StatusBadRequest, resp.Results[3].Status)
+
+	var count int64
+	database.DB.Model(&models.Book{}).Where("type = ?", kind).Count(&count)
+	assert.EqualValues(t, 1, count)
+}
@@ -0,0 +1,112 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetBookETagAndIfNoneMatch(t *testing.T) {
+	setupTestDB()
+	book := models.Book{Title: "Domain-Driven Design", Author: "Eric Evans"}
+	require.NoError(t, database.DB.Create(&book).Error)
+
+	rec := sendWithHeaders(t, "GET", "/books/"+book.ID.String(), "", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	etag := rec.Header().Get("ETag")
+	assert.Equal(t, book.EntityTag(), etag)
+	assert.Equal(t, "no-cache", rec.Header().Get("Cache-Control"))
+
+	var fetched models.Book
+	parseEnvelope(t, rec.Body.Bytes(), &fetched)
+	assert.Equal(t, etag, fetched.ETag, "items carry their ETag in the body too")
+
+	rec = sendWithHeaders(t, "GET", "/books/"+book.ID.String(), "", map[string]string{"If-None-Match": etag})
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Empty(t, rec.Body.String())
+
+	// GET'in verdiği ETag yazmalarda If-Match olarak geçer
+	rec = sendWithHeaders(t, "PATCH", "/books/"+book.ID.String(), `{"year": 2003}`, map[string]string{
+		"Content-Type": "application/merge-patch+json", "If-Match": etag,
+	})
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+}
+
+func TestWritesHonourIfMatch(t *testing.T) {
+	setupTestDB()
+	book := models.Book{Title: "Release It!", Author: "Michael Nygard"}
+	require.NoError(t, database.DB.Create(&book).Error)
+	path := "/books/" + book.ID.String()
+	v1 := book.EntityTag()
+
+	// librarian A saves with the right tag
+	rec := sendWithHeaders(t, "PATCH", path, `{"year": 2007}`, map[string]string{
+		"Content-Type": "application/merge-patch+json", "If-Match": v1,
+	})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	v2 := rec.Header().Get("ETag")
+	assert.NotEqual(t, v1, v2)
+
+	// librarian B still holds v1 – PUT, PATCH and DELETE are all refused
+	rec = sendWithHeaders(t, "PUT", path, `{"title": "Stale", "author": "B"}`, map[string]string{
+		"Content-Type": "application/json", "If-Match": v1,
+	})
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+	assert.Equal(t, v2, rec.Header().Get("ETag"))
+
+	rec = sendWithHeaders(t, "PATCH", path, `{"year": 1}`, map[string]string{
+		"Content-Type": "application/merge-patch+json", "If-Match": v1,
+	})
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+
+	rec = sendWithHeaders(t, "DELETE", path, "", map[string]string{"If-Match": v1})
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+
+	// weak tags never satisfy If-Match
+	rec = sendWithHeaders(t, "DELETE", path, "", map[string]string{"If-Match": "W/" + v2})
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+
+	var stored models.Book
+	database.DB.First(&stored, "id = ?", book.ID)
+	assert.Equal(t, 2007, stored.Year)
+	assert.Equal(t, 2, stored.Version)
+
+	rec = sendWithHeaders(t, "DELETE", path, "", map[string]string{"If-Match": v2})
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestGetBooksListETag(t *testing.T) {
+	setupTestDB()
+
+	rec := sendWithHeaders(t, "GET", "/books?limit=5", "", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	etag := rec.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	rec = sendWithHeaders(t, "GET", "/books?limit=5", "", map[string]string{"If-None-Match": etag})
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+}
+
+func TestGetBooksListETagTracksRatings(t *testing.T) {
+	setupTestDB()
+	kind := "Rated-" + uuid.NewString()
+	book := models.Book{Title: "Rated Page", Author: "A", Type: kind}
+	require.NoError(t, database.DB.Create(&book).Error)
+	path := "/books?sort=rating&type=" + kind
+
+	rec := sendWithHeaders(t, "GET", path, "", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	etag := rec.Header().Get("ETag")
+
+	// puan değişince sayfanın ETag'i de değişir
+	member := createMember(t, "List ETag Reviewer")
+	_, code := postReview(t, book.ID, member.ID, 5)
+	require.Equal(t, http.StatusCreated, code)
+	rec = sendWithHeaders(t, "GET", path, "", map[string]string{"If-None-Match": etag})
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
@@ -0,0 +1,108 @@
+package tests
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportCSVHonoursFiltersAndSort(t *testing.T) {
+	kind := seedPaginationBooks(t)
+
+	rec := doRequest(t, "GET", "/books/export?format=csv&sort=year,title&type="+kind)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/csv")
+	assert.Regexp(t, `attachment; filename="books-\d{8}-\d{6}\.csv"`, rec.Header().Get("Content-Disposition"))
+
+	rows, err := csv.NewReader(rec.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 6) // başlık + 5 kitap
+	assert.Equal(t, "title", rows[0][1])
+
+	var titles []string
+	for _, row := range rows[1:] {
+		titles = append(titles, row[1])
+	}
+	assert.Equal(t, []string{"D", "E", "A", "B", "C"}, titles)
+}
+
+func TestExportNDJSONWalksEveryBatch(t *testing.T) {
+	setupTestDB()
+	kind := "Export-" + uuid.NewString()
+	books := make([]models.Book, 520) // batch boyutundan (500) fazla
+	for i := range books {
+		books[i] = models.Book{Title: fmt.Sprintf("Vol %03d", i), Author: "Exporter", Type: kind}
+	}
+	require.NoError(t, database.DB.CreateInBatches(&books, 100).Error)
+
+	rec := doRequest(t, "GET", "/books/export?format=ndjson&sort=title&type="+kind)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/x-ndjson", rec.Header().Get("Content-Type"))
+
+	seen := map[string]bool{}
+	sc := bufio.NewScanner(rec.Body)
+	for sc.Scan() {
+		var b models.Book
+		require.NoError(t, json.Unmarshal(sc.Bytes(), &b))
+		assert.False(t, seen[b.Title], "duplicate %s", b.Title)
+		seen[b.Title] = true
+	}
+	assert.Len(t, seen, 520)
+}
+
+func TestExportSortsBySeriesAcrossBatches(t *testing.T) {
+	setupTestDB()
+	kind := "Export-Series-" + uuid.NewString()
+	series := models.Series{Name: "Export Saga " + kind}
+	require.NoError(t, database.DB.Create(&series).Error)
+	books := make([]models.Book, 520) // çoğunun serisi yok (NULL)
+	for i := range books {
+		books[i] = models.Book{Title: fmt.Sprintf("Vol %03d", i), Author: "Exporter", Type: kind}
+		if i < 20 {
+			books[i].SeriesID = &series.ID
+		}
+	}
+	require.NoError(t, database.DB.CreateInBatches(&books, 100).Error)
+
+	for _, sort := range []string{"series", "-series"} {
+		rec := doRequest(t, "GET", "/books/export?format=ndjson&sort="+sort+"&type="+kind)
+		require.Equal(t, http.StatusOK, rec.Code, sort)
+
+		seen := map[uuid.UUID]bool{}
+		sc := bufio.NewScanner(rec.Body)
+		for sc.Scan() {
+			var b models.Book
+			require.NoError(t, json.Unmarshal(sc.Bytes(), &b))
+			assert.False(t, seen[b.ID], "%s: duplicate %s", sort, b.Title)
+			seen[b.ID] = true
+		}
+		assert.Len(t, seen, 520, sort)
+	}
+}
+
+func TestExportJSONArrayAndBadFormat(t *testing.T) {
+	kind := seedPaginationBooks(t)
+
+	rec := doRequest(t, "GET", "/books/export?format=json&type="+kind)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var books []models.Book
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &books))
+	assert.Len(t, books, 5)
+
+	// boş sonuç da geçerli bir dizi olmalı
+	rec = doRequest(t, "GET", "/books/export?format=json&type=nothing-"+uuid.NewString())
+	assert.Equal(t, "[]", strings.TrimSpace(rec.Body.String()))
+
+	rec = doRequest(t, "GET", "/books/export?format=xml")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,81 @@
+package tests
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func filterTitles(t *testing.T, expr string) []string {
+	t.Helper()
+	rec := doRequest(t, "GET", "/books?sort=title&filter="+url.QueryEscape(expr))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var books []models.Book
+	parseEnvelope(t, rec.Body.Bytes(), &books)
+	titles := make([]string, len(books))
+	for i, b := range books {
+		titles[i] = b.Title
+	}
+	return titles
+}
+
+func TestBookFilterExpression(t *testing.T) {
+	setupTestDB()
+	tag := "fx" + uuid.NewString()[:8]
+	seed := []models.Book{
+		{Title: tag + " Clean Code", Author: "Martin", Type: "Programming", Year: 2008, Pages: 464},
+		{Title: tag + " Clean Coder", Author: "Martin", Type: "Programming", Year: 1999, Pages: 256},
+		{Title: tag + " Dune", Author: "Herbert", Type: "Fiction", Year: 1965, Pages: 200},
+		{Title: tag + " 100% Pure", Author: "Anon", Type: "Fiction", Year: 2010, Pages: 500},
+	}
+	for i := range seed {
+		require.NoError(t, database.DB.Create(&seed[i]).Error)
+	}
+	scope := `title~"` + tag + `" AND `
+
+	assert.Equal(t, []string{tag + " Clean Code"},
+		filterTitles(t, scope+`year>=2000 AND (type="Programming" OR pages<300) AND title~"clean"`))
+	assert.Equal(t, []string{tag + " 100% Pure", tag + " Clean Code"},
+		filterTitles(t, scope+`(year > 2005 or author = Herbert) and not title ~ "dune"`))
+	assert.Equal(t, []string{tag + " Clean Coder", tag + " Dune"},
+		filterTitles(t, scope+"pages<>464 AND pages<500"))
+	assert.Equal(t, []string{tag + " 100% Pure"}, filterTitles(t, scope+`title~"0%"`))
+	assert.Len(t, filterTitles(t, scope+"series_id=null"), 4)
+	// values are bound, never spliced into the SQL
+	assert.Empty(t, filterTitles(t, scope+`type="x' OR 1=1 --"`))
+	// the classic filters still apply alongside
+	rec := doRequest(t, "GET", "/books?type=Fiction&filter="+url.QueryEscape(scope+"pages>=200"))
+	var books []models.Book
+	parseEnvelope(t, rec.Body.Bytes(), &books)
+	assert.Len(t, books, 2)
+}
+
+func TestBookFilterExpressionErrors(t *testing.T) {
+	setupTestDB()
+	cases := map[string]string{
+		`year>=2000 AND`:                       "invalid filter at position 15: expected a field name, got end of filter",
+		`(year>2000`:                           `invalid filter at position 11: expected ")", got end of filter`,
+		`colour="red"`:                         `invalid filter at position 1: unknown field "colour"`,
+		`year~"19"`:                            `invalid filter at position 5: ~ only applies to text fields`,
+		`pages<"many"`:                         `invalid filter at position 7: pages expects a whole number, got "many"`,
+		`title="open`:                          "invalid filter at position 7: unterminated string",
+		`title="a" year=1`:                     `invalid filter at position 11: expected AND, OR or end of filter, got "year"`,
+		`year=null`:                            "invalid filter at position 6: year is never null",
+		`created_at>"last week"`:               `invalid filter at position 12: created_at expects a date (YYYY-MM-DD) or RFC 3339 timestamp, got "last week"`,
+		`series_id>"` + uuid.NewString() + `"`: "invalid filter at position 10: series_id can only be compared with = or !=",
+	}
+	for expr, want := range cases {
+		rec := doRequest(t, "GET", "/books?filter="+url.QueryEscape(expr))
+		assert.Equal(t, http.StatusBadRequest, rec.Code, expr)
+		assert.Equal(t, want, parseError(t, rec), expr)
+	}
+
+	rec := doRequest(t, "GET", "/books?year=19x")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
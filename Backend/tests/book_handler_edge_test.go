@@ -58,7 +58,7 @@ func TestGetBook_Success(t *testing.T) {
 }
 
 // ───────────────────────────────────────────────────────────
-// UPDATE BOOK : 200
+// UPDATE BOOK : 200  (partial update → PATCH, PUT is full replacement)
 // ───────────────────────────────────────────────────────────
 func TestUpdateBook_Success(t *testing.T) {
 	r := testRouter()
@@ -68,8 +68,8 @@ func TestUpdateBook_Success(t *testing.T) {
 	}
 	b, _ := json.Marshal(update)
 
-	req, _ := http.NewRequest("PUT", fmt.Sprintf("/books/%s", sharedBookID), bytes.NewBuffer(b))
-	req.Header.Set("Content-Type", "application/json")
+	req, _ := http.NewRequest("PATCH", fmt.Sprintf("/books/%s", sharedBookID), bytes.NewBuffer(b))
+	req.Header.Set("Content-Type", "application/merge-patch+json")
 
 	rec := httptest.NewRecorder()
 	r.ServeHTTP(rec, req)
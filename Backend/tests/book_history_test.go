@@ -0,0 +1,110 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/jobs"
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func bookHistory(t *testing.T, id uuid.UUID) []models.BookRevision {
+	rec := doRequest(t, "GET", "/books/"+id.String()+"/history")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var revs []models.BookRevision
+	parseEnvelope(t, rec.Body.Bytes(), &revs)
+	return revs
+}
+
+func TestBookHistoryRecordsEveryWrite(t *testing.T) {
+	setupTestDB()
+	actor := map[string]string{"X-Actor": "librarian@example.org", "Content-Type": "application/json"}
+
+	rec := sendWithHeaders(t, "POST", "/books", `{"title": "Refactoring", "author": "Martin Fowler", "pages": 448}`, actor)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var book models.Book
+	parseEnvelope(t, rec.Body.Bytes(), &book)
+
+	rec = sendJSON(t, "PATCH", "/books/"+book.ID.String(), "application/merge-patch+json", `{"pages": 460}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.Equal(t, http.StatusOK, doRequest(t, "DELETE", "/books/"+book.ID.String()).Code)
+	require.Equal(t, http.StatusOK, doRequest(t, "POST", "/books/"+book.ID.String()+"/restore").Code)
+
+	revs := bookHistory(t, book.ID)
+	require.Len(t, revs, 4)
+	// en yeni önce
+	var actions []string
+	for _, r := range revs {
+		actions = append(actions, r.Action)
+	}
+	assert.Equal(t, []string{"restore", "delete", "update", "create"}, actions)
+	assert.Equal(t, 1, revs[3].Revision)
+	assert.Equal(t, "librarian@example.org", revs[3].Actor)
+	assert.Equal(t, "anonymous", revs[2].Actor)
+	assert.Equal(t, models.FieldChange{From: float64(448), To: float64(460)}, revs[2].Changes["pages"])
+	assert.Len(t, revs[2].Changes, 1, "only pages changed")
+	assert.Nil(t, revs[3].Changes["title"].From)
+	assert.Equal(t, "Refactoring", revs[3].Changes["title"].To)
+
+	// bilinmeyen kitap → 404, geçersiz UUID → 400
+	assert.Equal(t, http.StatusNotFound, doRequest(t, "GET", "/books/"+uuid.NewString()+"/history").Code)
+	assert.Equal(t, http.StatusBadRequest, doRequest(t, "GET", "/books/nope/history").Code)
+}
+
+func TestRevertBookToRevision(t *testing.T) {
+	setupTestDB()
+	book := seedPatchBook(t)
+	original := book.Title
+
+	rec := sendJSON(t, "PATCH", "/books/"+book.ID.String(), "application/merge-patch+json",
+		`{"title": "Vandalised", "description": null}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = doRequest(t, "POST", fmt.Sprintf("/books/%s/revert/1", book.ID))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var reverted models.Book
+	parseEnvelope(t, rec.Body.Bytes(), &reverted)
+	assert.Equal(t, original, reverted.Title)
+	assert.Equal(t, book.Description, reverted.Description)
+	assert.Equal(t, book.Version+2, reverted.Version)
+
+	// test kurulumu ISBN-10 backfill'ini de "system" revizyonu olarak yazabilir
+	revs := bookHistory(t, book.ID)
+	require.GreaterOrEqual(t, len(revs), 3)
+	assert.Equal(t, "update", revs[0].Action)
+	assert.Equal(t, "reverted to revision 1", revs[0].Note)
+	assert.Equal(t, "Vandalised", revs[0].Changes["title"].From)
+
+	assert.Equal(t, http.StatusNotFound, doRequest(t, "POST", fmt.Sprintf("/books/%s/revert/99", book.ID)).Code)
+	assert.Equal(t, http.StatusBadRequest, doRequest(t, "POST", fmt.Sprintf("/books/%s/revert/zero", book.ID)).Code)
+
+	// If-Match eskiyse geri alma da 412 döner
+	rec = sendWithHeaders(t, "POST", fmt.Sprintf("/books/%s/revert/2", book.ID), "", map[string]string{"If-Match": book.ETag})
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+}
+
+func TestHistoryCoversBulkStatements(t *testing.T) {
+	setupTestDB()
+	// handler dışı, koşullu toplu silme – trash purger
+	book := models.Book{Title: "Ephemeral", Author: "Nobody"}
+	require.NoError(t, database.DB.Create(&book).Error)
+	require.NoError(t, database.DB.Delete(&book).Error)
+	database.DB.Unscoped().Model(&book).UpdateColumn("deleted_at", time.Now().AddDate(0, 0, -90))
+
+	_, err := jobs.PurgeTrash(database.DB, 30*24*time.Hour)
+	require.NoError(t, err)
+
+	// kitap silinmiş olsa da geçmiş okunabilir
+	revs := bookHistory(t, book.ID)
+	require.NotEmpty(t, revs)
+	assert.Equal(t, "purge", revs[0].Action)
+	assert.Equal(t, "system", revs[0].Actor)
+	assert.Empty(t, revs[0].Snapshot)
+	assert.Equal(t, "Ephemeral", revs[0].Changes["title"].From)
+}
@@ -0,0 +1,110 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type importReport struct {
+	DryRun         bool              `json:"dry_run"`
+	Columns        map[string]string `json:"columns"`
+	IgnoredColumns []string          `json:"ignored_columns"`
+	Rows           int               `json:"rows"`
+	Valid          int               `json:"valid"`
+	Imported       int               `json:"imported"`
+	Failed         int               `json:"failed"`
+	Errors         []struct {
+		Row    int               `json:"row"`
+		Fields map[string]string `json:"fields"`
+	} `json:"errors"`
+}
+
+// uploadCSV – multipart form ile dosya yükler (alanlar dosyadan önce)
+func uploadCSV(t *testing.T, query, filename, content string, fields map[string]string) (importReport, int) {
+	t.Helper()
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	for k, v := range fields {
+		_ = w.WriteField(k, v)
+	}
+	fw, _ := w.CreateFormFile("file", filename)
+	_, _ = fw.Write([]byte(content))
+	_ = w.Close()
+
+	rec := sendWithHeaders(t, "POST", "/books/import"+query, body.String(),
+		map[string]string{"Content-Type": w.FormDataContentType()})
+
+	var env struct {
+		Data importReport `json:"data"`
+	}
+	_ = json.Unmarshal(rec.Body.Bytes(), &env)
+	return env.Data, rec.Code
+}
+
+func countType(kind string) int64 {
+	var n int64
+	database.DB.Model(&models.Book{}).Where("type = ?", kind).Count(&n)
+	return n
+}
+
+func TestImportCSVReportsBadRows(t *testing.T) {
+	setupTestDB()
+	kind := "Import-" + uuid.NewString()
+	csv := fmt.Sprintf("Title,Author,Year,Genre,Shelf\n"+
+		"Clean Code,Robert C. Martin,2008,%[1]s,A1\n"+
+		",Nameless,2001,%[1]s,A2\n"+
+		"Refactoring,Martin Fowler,nineteen,%[1]s,A3\n"+
+		"\"Design Patterns, Elements\",Gamma,1994,%[1]s,A4\n", kind)
+
+	report, code := uploadCSV(t, "", "books.csv", csv, nil)
+	require.Equal(t, http.StatusCreated, code)
+	assert.Equal(t, 4, report.Rows)
+	assert.Equal(t, 2, report.Imported)
+	assert.Equal(t, 2, report.Failed)
+	assert.Equal(t, []string{"Shelf"}, report.IgnoredColumns)
+	assert.Equal(t, "type", report.Columns["Genre"])
+
+	require.Len(t, report.Errors, 2)
+	assert.Equal(t, 3, report.Errors[0].Row)
+	assert.Equal(t, "is required", report.Errors[0].Fields["title"])
+	assert.Equal(t, 4, report.Errors[1].Row)
+	assert.Contains(t, report.Errors[1].Fields, "year")
+
+	assert.EqualValues(t, 2, countType(kind))
+}
+
+func TestImportTSVDryRunWithMapping(t *testing.T) {
+	setupTestDB()
+	kind := "Import-" + uuid.NewString()
+	tsv := fmt.Sprintf("Book Name\tWriter\tKind\n"+
+		"The Pragmatic Programmer\tHunt & Thomas\t%[1]s\n", kind)
+
+	report, code := uploadCSV(t, "", "legacy.tsv", tsv, map[string]string{
+		"mapping": `{"Book Name": "title", "Writer": "author", "Kind": "type"}`,
+		"dry_run": "true",
+	})
+	require.Equal(t, http.StatusOK, code)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 1, report.Valid)
+	assert.Zero(t, report.Imported)
+	assert.Zero(t, countType(kind), "dry run must not write")
+}
+
+func TestImportRequiresTitleAndAuthorColumns(t *testing.T) {
+	_, code := uploadCSV(t, "", "books.csv", "Name Of Thing,Year\nx,1\n", nil)
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+
+	rec := sendJSON(t, "POST", "/books/import", "application/json", `{}`)
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+}
@@ -0,0 +1,115 @@
+package tests
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"testing"
+
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/hasan-kayan/TaskGo/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// uniqueISBN – her çağrıda geçerli, rastgele bir 978 ISBN-13 üretir
+// (ISBN benzersiz olduğu için sabit değerler testler arasında çakışır)
+func uniqueISBN() string {
+	body := fmt.Sprintf("978%09d", rand.IntN(1_000_000_000))
+	for d := 0; d <= 9; d++ {
+		if isbn13, _, err := utils.NormaliseISBN(fmt.Sprintf("%s%d", body, d)); err == nil {
+			return isbn13
+		}
+	}
+	panic("unreachable")
+}
+
+func TestNormaliseISBN(t *testing.T) {
+	cases := []struct{ in, isbn13, isbn10 string }{
+		{"978-0-13-235088-4", "9780132350884", "0132350882"},
+		{"0 13 235088 2", "9780132350884", "0132350882"},
+		{"080442957x", "9780804429573", "080442957X"},
+		{"979-10-90636-07-1", "9791090636071", ""},
+	}
+	for _, tc := range cases {
+		isbn13, isbn10, err := utils.NormaliseISBN(tc.in)
+		require.NoError(t, err, tc.in)
+		assert.Equal(t, tc.isbn13, isbn13, tc.in)
+		assert.Equal(t, tc.isbn10, isbn10, tc.in)
+	}
+
+	for _, bad := range []string{"9780132350885", "0132350883", "12345", "97801323508XX", "1230132350881"} {
+		_, _, err := utils.NormaliseISBN(bad)
+		assert.ErrorIs(t, err, utils.ErrInvalidISBN, bad)
+	}
+}
+
+func TestCreateBookStoresCanonicalISBN(t *testing.T) {
+	setupTestDB()
+	database.DB.Unscoped().Where("isbn = ?", "9780132350884").Delete(&models.Book{})
+
+	rec := sendJSON(t, "POST", "/books", "application/json",
+		`{"title": "Clean Code", "author": "Robert C. Martin", "isbn": "0-13-235088-2"}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var created models.Book
+	parseEnvelope(t, rec.Body.Bytes(), &created)
+	assert.Equal(t, "9780132350884", created.ISBN)
+	assert.Equal(t, "0132350882", created.ISBN10)
+
+	// aynı ISBN, farklı yazım → 409
+	rec = sendJSON(t, "POST", "/books", "application/json",
+		`{"title": "Clean Code (copy)", "author": "Robert C. Martin", "isbn": "978 0132350884"}`)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), "ISBN already exists")
+
+	// yanlış kontrol basamağı → 422
+	rec = sendJSON(t, "POST", "/books", "application/json",
+		`{"title": "Typo", "author": "Someone", "isbn": "978-0-13-235088-5"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Equal(t, "must be a valid ISBN-10 or ISBN-13", fieldErrors(t, rec)["isbn"])
+
+	// her iki biçimle arama
+	for _, q := range []string{"9780132350884", "0-13-235088-2"} {
+		rec = doRequest(t, "GET", "/books/isbn/"+q)
+		require.Equal(t, http.StatusOK, rec.Code, q)
+		var found models.Book
+		parseEnvelope(t, rec.Body.Bytes(), &found)
+		assert.Equal(t, created.ID, found.ID)
+	}
+	assert.Equal(t, http.StatusBadRequest, doRequest(t, "GET", "/books/isbn/123").Code)
+	assert.Equal(t, http.StatusNotFound, doRequest(t, "GET", "/books/isbn/"+uniqueISBN()).Code)
+}
+
+func TestISBNConflictOnUpdateAndRestore(t *testing.T) {
+	setupTestDB()
+	isbn := uniqueISBN()
+	first := models.Book{Title: "First", Author: "A", ISBN: isbn}
+	second := models.Book{Title: "Second", Author: "B"}
+	require.NoError(t, database.DB.Create(&first).Error)
+	require.NoError(t, database.DB.Create(&second).Error)
+
+	rec := sendJSON(t, "PATCH", "/books/"+second.ID.String(), "application/merge-patch+json",
+		fmt.Sprintf(`{"isbn": %q}`, isbn))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	// çöp kutusundaki kitap ISBN'i serbest bırakır, geri yükleme çakışır
+	require.Equal(t, http.StatusOK, doRequest(t, "DELETE", "/books/"+first.ID.String()).Code)
+	rec = sendJSON(t, "PATCH", "/books/"+second.ID.String(), "application/merge-patch+json",
+		fmt.Sprintf(`{"isbn": %q}`, isbn))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, http.StatusConflict, doRequest(t, "POST", "/books/"+first.ID.String()+"/restore").Code)
+}
+
+func TestCreateBookDuplicateIDIsNotAnISBNConflict(t *testing.T) {
+	setupTestDB()
+	existing := models.Book{Title: "Taken Id", Author: "A"}
+	require.NoError(t, database.DB.Create(&existing).Error)
+
+	// aynı id, ISBN yok → id çakışması olarak raporlanır
+	rec := sendJSON(t, "POST", "/books", "application/json",
+		fmt.Sprintf(`{"id": %q, "title": "Other", "author": "B", "isbn": %q}`, existing.ID, uniqueISBN()))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), "id already exists")
+	assert.NotContains(t, rec.Body.String(), "ISBN")
+}
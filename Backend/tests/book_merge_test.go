@@ -0,0 +1,189 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/handlers"
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/hasan-kayan/TaskGo/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clusterWith finds the duplicate cluster that holds id.
+func clusterWith(t *testing.T, id uuid.UUID) *handlers.DuplicateCluster {
+	t.Helper()
+	rec := doRequest(t, "GET", "/books/duplicates?limit=200")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var clusters []handlers.DuplicateCluster
+	parseEnvelope(t, rec.Body.Bytes(), &clusters)
+	for i := range clusters {
+		for _, b := range clusters[i].Books {
+			if b.ID == id {
+				return &clusters[i]
+			}
+		}
+	}
+	return nil
+}
+
+func TestBookDuplicates(t *testing.T) {
+	setupTestDB()
+	surname := "Qx" + uuid.NewString()[:6]
+	seed := []models.Book{
+		{Title: "Clean Code", Author: "Robert C. " + surname},
+		{Title: "Clean Code: A Handbook of Agile Craftsmanship", Author: surname + ", Robert"},
+		{Title: "The Clean Coder", Author: "Robert " + surname},
+		{Title: "Refactoring", Author: "Robert " + surname},
+	}
+	for i := range seed {
+		require.NoError(t, database.DB.Create(&seed[i]).Error)
+	}
+
+	cl := clusterWith(t, seed[0].ID)
+	require.NotNil(t, cl)
+	assert.Equal(t, seed[0].ID, cl.SuggestedTarget)
+	ids := []uuid.UUID{}
+	for _, b := range cl.Books {
+		ids = append(ids, b.ID)
+	}
+	assert.Contains(t, ids, seed[1].ID)
+	assert.NotContains(t, ids, seed[3].ID)
+	assert.Equal(t, "title_author", cl.Pairs[0].Reason)
+	assert.Nil(t, clusterWith(t, seed[3].ID))
+
+	// the same ISBN written two ways
+	a := models.Book{Title: "Dune", Author: "Frank Herbert " + surname}
+	b := models.Book{Title: "Children of Dune", Author: "Someone Else " + uuid.NewString()[:6]}
+	require.NoError(t, database.DB.Create(&a).Error)
+	require.NoError(t, database.DB.Create(&b).Error)
+	isbn := uniqueISBN()
+	require.NoError(t, database.DB.Exec("UPDATE books SET isbn = ? WHERE id = ?", isbn, a.ID).Error)
+	require.NoError(t, database.DB.Exec("UPDATE books SET isbn = ? WHERE id = ?", utils.ISBN10From13(isbn), b.ID).Error)
+	cl = clusterWith(t, b.ID)
+	require.NotNil(t, cl)
+	assert.Equal(t, handlers.DuplicatePair{A: a.ID, B: b.ID, Score: 1, Reason: "isbn"}, cl.Pairs[0])
+	require.NoError(t, database.DB.Delete(&b).Error)
+
+	assert.Equal(t, http.StatusBadRequest, doRequest(t, "GET", "/books/duplicates?min_score=2").Code)
+}
+
+func TestBookDuplicatesSkipDistinctEditionsAndVolumes(t *testing.T) {
+	setupTestDB()
+	surname := "Qv" + uuid.NewString()[:6]
+
+	// aynı başlık, farklı ISBN → ayrı baskılar
+	first := models.Book{Title: "Design Patterns", Author: "Erich " + surname, ISBN: uniqueISBN()}
+	second := models.Book{Title: "Design Patterns", Author: "Erich " + surname, ISBN: uniqueISBN()}
+	require.NoError(t, database.DB.Create(&first).Error)
+	require.NoError(t, database.DB.Create(&second).Error)
+	assert.Nil(t, clusterWith(t, first.ID))
+
+	// aynı serinin farklı ciltleri
+	series := models.Series{Name: "Series " + surname}
+	require.NoError(t, database.DB.Create(&series).Error)
+	one, two := 1.0, 2.0
+	vol1 := models.Book{Title: "The Saga", Author: "Ann " + surname, SeriesID: &series.ID, Volume: &one}
+	vol2 := models.Book{Title: "The Saga", Author: "Ann " + surname, SeriesID: &series.ID, Volume: &two}
+	require.NoError(t, database.DB.Create(&vol1).Error)
+	require.NoError(t, database.DB.Create(&vol2).Error)
+	assert.Nil(t, clusterWith(t, vol1.ID))
+
+	// ISBN'i olmayan bir kopya yine eşleşir
+	copyOf := models.Book{Title: "Design Patterns", Author: surname + ", Erich"}
+	require.NoError(t, database.DB.Create(&copyOf).Error)
+	assert.NotNil(t, clusterWith(t, copyOf.ID))
+}
+
+func TestBookDuplicatesTolerateMisspeltAndMissingBylines(t *testing.T) {
+	setupTestDB()
+	tag := uuid.NewString()[:6]
+
+	// "Martin" / "Martn": soyadı yazım hatalı olsa da karşılaştırılır
+	surname := "Mart" + tag
+	right := models.Book{Title: "Agile Software Development " + tag, Author: "Robert C. " + surname}
+	typo := models.Book{Title: "Agile Software Development " + tag, Author: "Robert C. " + surname[:len(surname)-1]}
+	require.NoError(t, database.DB.Create(&right).Error)
+	require.NoError(t, database.DB.Create(&typo).Error)
+	cl := clusterWith(t, typo.ID)
+	require.NotNil(t, cl)
+	assert.Equal(t, right.ID, cl.SuggestedTarget)
+
+	// "A and B" yazımı ile "A; B" aynı yazarlar
+	joined := models.Book{Title: "Pair Work " + tag, Author: "Ann Qa" + tag + " and Bob Qb" + tag}
+	listed := models.Book{Title: "Pair Work " + tag, Author: "Qa" + tag + ", Ann; Qb" + tag + ", Bob"}
+	require.NoError(t, database.DB.Create(&joined).Error)
+	require.NoError(t, database.DB.Create(&listed).Error)
+	assert.NotNil(t, clusterWith(t, listed.ID))
+
+	// yazarı boş kitaplar başlıklarıyla eşleşir
+	anon := models.Book{Title: "Zq Anonymous Tales " + tag}
+	anonCopy := models.Book{Title: "Zq Anonymous Tales " + tag + " (reprint)"}
+	require.NoError(t, database.DB.Create(&anon).Error)
+	require.NoError(t, database.DB.Create(&anonCopy).Error)
+	cl = clusterWith(t, anonCopy.ID)
+	require.NotNil(t, cl)
+	assert.Equal(t, anon.ID, cl.SuggestedTarget)
+}
+
+func TestMergeBooks(t *testing.T) {
+	setupTestDB()
+	target := models.Book{Title: "Clean Code", Author: "Robert C. Martin", Year: 2008}
+	source := models.Book{Title: "Clean Code (2nd printing)", Author: "Robert Martin", Year: 2009,
+		ISBN: uniqueISBN(), Description: "A handbook", Pages: 464}
+	require.NoError(t, database.DB.Create(&target).Error)
+	require.NoError(t, database.DB.Create(&source).Error)
+
+	both, other := createMember(t, "Ayşe"), createMember(t, "Mehmet")
+	_, code := postReview(t, target.ID, both.ID, 5)
+	require.Equal(t, http.StatusCreated, code)
+	_, code = postReview(t, source.ID, both.ID, 1)
+	require.Equal(t, http.StatusCreated, code)
+	_, code = postReview(t, source.ID, other.ID, 3)
+	require.Equal(t, http.StatusCreated, code)
+	createCopy(t, source.ID)
+
+	body := fmt.Sprintf(`{"target_id": %q, "source_ids": [%q], "fields": {"year": %q}}`, target.ID, source.ID, source.ID)
+	rec := sendJSON(t, "POST", "/books/merge", "application/json", body)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var result handlers.BookMergeResult
+	parseEnvelope(t, rec.Body.Bytes(), &result)
+
+	// the target's own values win unless empty or overridden
+	assert.Equal(t, "Clean Code", result.Book.Title)
+	assert.Equal(t, 2009, result.Book.Year)
+	assert.Equal(t, source.ISBN, result.Book.ISBN)
+	assert.Equal(t, "A handbook", result.Book.Description)
+	assert.Equal(t, 464, result.Book.Pages)
+	assert.EqualValues(t, 1, result.Moved["reviews"])
+	assert.EqualValues(t, 1, result.Moved["copies"])
+
+	rating := bookRating(t, target.ID)
+	assert.EqualValues(t, 2, rating.Count)
+	assert.Equal(t, 4.0, rating.Average)
+	assert.Equal(t, 1, bookAvailability(t, target.ID)["copies"])
+	assert.Equal(t, http.StatusNotFound, doRequest(t, "GET", "/books/"+source.ID.String()).Code)
+	var trashed models.Book
+	require.NoError(t, database.DB.Unscoped().First(&trashed, "id = ?", source.ID).Error)
+	assert.True(t, trashed.DeletedAt.Valid)
+}
+
+func TestMergeBooksValidation(t *testing.T) {
+	setupTestDB()
+	book := models.Book{Title: "Solo", Author: "Anon"}
+	require.NoError(t, database.DB.Create(&book).Error)
+
+	rec := sendJSON(t, "POST", "/books/merge", "application/json",
+		fmt.Sprintf(`{"target_id": %q, "source_ids": [%q, %q], "fields": {"colour": %q}}`, book.ID, book.ID, uuid.New(), book.ID))
+	require.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(t, rec.Body.String(), "cannot merge a book into itself")
+	assert.Contains(t, rec.Body.String(), "book not found")
+	assert.Contains(t, rec.Body.String(), "unknown field")
+
+	rec = sendJSON(t, "POST", "/books/merge", "application/json", fmt.Sprintf(`{"target_id": %q}`, book.ID))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
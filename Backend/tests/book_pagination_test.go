@@ -0,0 +1,102 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type pageMeta struct {
+	Total      int64  `json:"total"`
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"next_cursor"`
+	PrevCursor string `json:"prev_cursor"`
+}
+
+// getBookPage – GET /books çağırır, kitapları ve meta bilgisini döner
+func getBookPage(t *testing.T, query url.Values) ([]models.Book, pageMeta, int) {
+	t.Helper()
+	rec := doRequest(t, "GET", "/books?"+query.Encode())
+
+	var resp struct {
+		Data []models.Book `json:"data"`
+		Meta pageMeta      `json:"meta"`
+	}
+	_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+	return resp.Data, resp.Meta, rec.Code
+}
+
+func seedPaginationBooks(t *testing.T) string {
+	t.Helper()
+	setupTestDB()
+
+	kind := "Pagination-" + uuid.NewString()
+	years := []int{2001, 1999, 2001, 2010, 1999}
+	titles := []string{"B", "E", "A", "C", "D"}
+	for i := range years {
+		book := models.Book{Title: titles[i], Author: "Pager", Year: years[i], Type: kind}
+		require.NoError(t, database.DB.Create(&book).Error)
+	}
+	return kind
+}
+
+func TestGetBooksLimitOffsetAndSort(t *testing.T) {
+	kind := seedPaginationBooks(t)
+
+	books, meta, code := getBookPage(t, url.Values{"type": {kind}, "sort": {"-year,title"}, "limit": {"2"}, "offset": {"1"}})
+	assert.Equal(t, http.StatusOK, code)
+	assert.EqualValues(t, 5, meta.Total)
+	require.Len(t, books, 2)
+	// 2010 C, 2001 A, 2001 B, 1999 D, 1999 E
+	assert.Equal(t, "A", books[0].Title)
+	assert.Equal(t, "B", books[1].Title)
+	assert.NotEmpty(t, meta.NextCursor)
+	assert.NotEmpty(t, meta.PrevCursor)
+}
+
+func TestGetBooksCursorWalk(t *testing.T) {
+	kind := seedPaginationBooks(t)
+	q := url.Values{"type": {kind}, "sort": {"year,-title"}, "limit": {"2"}}
+
+	var titles []string
+	var meta pageMeta
+	for {
+		books, m, code := getBookPage(t, q)
+		require.Equal(t, http.StatusOK, code)
+		for _, b := range books {
+			titles = append(titles, b.Title)
+		}
+		meta = m
+		if m.NextCursor == "" {
+			break
+		}
+		q.Set("cursor", m.NextCursor)
+	}
+	assert.Equal(t, []string{"E", "D", "B", "A", "C"}, titles)
+
+	// walking back from the last page returns the previous two
+	q.Set("cursor", meta.PrevCursor)
+	books, _, code := getBookPage(t, q)
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, books, 2)
+	assert.Equal(t, "B", books[0].Title)
+	assert.Equal(t, "A", books[1].Title)
+}
+
+func TestGetBooksPaginationRejectsBadInput(t *testing.T) {
+	for _, q := range []url.Values{
+		{"sort": {"isbn"}},
+		{"limit": {"0"}},
+		{"cursor": {"not-a-cursor"}},
+	} {
+		_, _, code := getBookPage(t, q)
+		assert.Equal(t, http.StatusBadRequest, code, q.Encode())
+	}
+}
@@ -0,0 +1,128 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sendJSON(t *testing.T, method, path, contentType, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	return sendWithHeaders(t, method, path, body, map[string]string{"Content-Type": contentType})
+}
+
+func seedPatchBook(t *testing.T) models.Book {
+	t.Helper()
+	setupTestDB()
+	book := models.Book{
+		Title: "Refactoring", Author: "Martin Fowler", Year: 1999,
+		ISBN: uniqueISBN(), Description: "Improving the design", Pages: 431,
+	}
+	require.NoError(t, database.DB.Create(&book).Error)
+	return book
+}
+
+func fieldErrors(t *testing.T, rec *httptest.ResponseRecorder) map[string]string {
+	t.Helper()
+	var resp struct {
+		Fields map[string]string `json:"fields"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	return resp.Fields
+}
+
+func TestPatchBookMergePatchClearsFields(t *testing.T) {
+	book := seedPatchBook(t)
+
+	rec := sendJSON(t, "PATCH", "/books/"+book.ID.String(), "application/merge-patch+json",
+		`{"isbn": null, "description": "", "pages": 0, "year": 2018}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var stored models.Book
+	database.DB.First(&stored, "id = ?", book.ID)
+	assert.Empty(t, stored.ISBN)
+	assert.Empty(t, stored.Description)
+	assert.Zero(t, stored.Pages)
+	assert.Equal(t, 2018, stored.Year)
+	assert.Equal(t, "Refactoring", stored.Title, "untouched fields survive")
+}
+
+func TestPatchBookJSONPatch(t *testing.T) {
+	book := seedPatchBook(t)
+
+	rec := sendJSON(t, "PATCH", "/books/"+book.ID.String(), "application/json-patch+json", `[
+		{"op": "test",    "path": "/title", "value": "Refactoring"},
+		{"op": "replace", "path": "/pages", "value": 0},
+		{"op": "copy",    "from": "/author", "path": "/publisher"},
+		{"op": "remove",  "path": "/isbn"}
+	]`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var stored models.Book
+	database.DB.First(&stored, "id = ?", book.ID)
+	assert.Zero(t, stored.Pages)
+	assert.Equal(t, "Martin Fowler", stored.Publisher)
+	assert.Empty(t, stored.ISBN)
+
+	// failing test op → 409, nothing written
+	rec = sendJSON(t, "PATCH", "/books/"+book.ID.String(), "application/json-patch+json", `[
+		{"op": "replace", "path": "/title", "value": "Changed"},
+		{"op": "test", "path": "/year", "value": 1}
+	]`)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	database.DB.First(&stored, "id = ?", book.ID)
+	assert.Equal(t, "Refactoring", stored.Title)
+}
+
+func TestPatchBookValidatesBeforeSaving(t *testing.T) {
+	book := seedPatchBook(t)
+
+	rec := sendJSON(t, "PATCH", "/books/"+book.ID.String(), "application/merge-patch+json",
+		`{"title": null, "pages": -1, "id": "00000000-0000-0000-0000-000000000000", "colour": "red"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(t, fieldErrors(t, rec), "id")
+
+	rec = sendJSON(t, "PATCH", "/books/"+book.ID.String(), "application/merge-patch+json",
+		`{"title": null, "pages": -1}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	fields := fieldErrors(t, rec)
+	assert.Equal(t, "is required", fields["title"])
+	assert.Contains(t, fields, "pages")
+
+	rec = sendJSON(t, "PATCH", "/books/"+book.ID.String(), "application/merge-patch+json", `{"year": "soon"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(t, fieldErrors(t, rec), "year")
+
+	var stored models.Book
+	database.DB.First(&stored, "id = ?", book.ID)
+	assert.Equal(t, "Refactoring", stored.Title)
+	assert.Equal(t, 431, stored.Pages)
+
+	rec = sendJSON(t, "PATCH", "/books/"+book.ID.String(), "text/plain", `{}`)
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+}
+
+func TestPutBookIsFullReplacement(t *testing.T) {
+	book := seedPatchBook(t)
+
+	rec := sendJSON(t, "PUT", "/books/"+book.ID.String(), "application/json",
+		`{"title": "Refactoring 2e", "author": "Martin Fowler"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var stored models.Book
+	database.DB.First(&stored, "id = ?", book.ID)
+	assert.Equal(t, "Refactoring 2e", stored.Title)
+	assert.Zero(t, stored.Year)
+	assert.Empty(t, stored.ISBN)
+	assert.Zero(t, stored.Pages)
+
+	rec = sendJSON(t, "PUT", "/books/"+book.ID.String(), "application/json", `{"title": "No author"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(t, fieldErrors(t, rec), "author")
+}
@@ -0,0 +1,118 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type searchHit struct {
+	models.Book
+	Score      float64           `json:"score"`
+	Highlights map[string]string `json:"highlights"`
+}
+
+func searchBooks(t *testing.T, q string) ([]searchHit, int) {
+	t.Helper()
+	rec := doRequest(t, "GET", "/books/search?q="+url.QueryEscape(q))
+
+	var resp struct {
+		Data []searchHit `json:"data"`
+	}
+	_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+	return resp.Data, rec.Code
+}
+
+func TestSearchBooksRanksTitleAboveDescription(t *testing.T) {
+	setupTestDB()
+
+	// her çalıştırmada benzersiz bir kelime – paylaşılan DB'de çakışma olmasın
+	word := "zq" + strings.ReplaceAll(uuid.NewString()[:8], "-", "")
+	inDesc := models.Book{Title: "Patterns", Author: "Gamma", Description: "A classic about " + word + " design"}
+	inTitle := models.Book{Title: "The " + word + " Handbook", Author: "Someone"}
+	require.NoError(t, database.DB.Create(&inDesc).Error)
+	require.NoError(t, database.DB.Create(&inTitle).Error)
+
+	hits, code := searchBooks(t, word)
+	assert.Equal(t, http.StatusOK, code)
+	require.Len(t, hits, 2)
+	assert.Equal(t, inTitle.ID, hits[0].ID, "title match should rank first")
+	assert.Greater(t, hits[0].Score, hits[1].Score)
+	assert.Contains(t, hits[0].Highlights["title"], "<mark>"+word+"</mark>")
+	assert.Contains(t, hits[1].Highlights["description"], "<mark>"+word+"</mark>")
+
+	// updates are picked up by the index
+	require.NoError(t, database.DB.Model(&inDesc).Update("description", "nothing here").Error)
+	hits, _ = searchBooks(t, word)
+	assert.Len(t, hits, 1)
+}
+
+func TestSearchBooksPhraseAndPrefix(t *testing.T) {
+	setupTestDB()
+
+	word := "xk" + strings.ReplaceAll(uuid.NewString()[:8], "-", "")
+	book := models.Book{Title: word + "ing systems well", Author: "Kleppmann"}
+	require.NoError(t, database.DB.Create(&book).Error)
+
+	hits, code := searchBooks(t, word+"*")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, hits, 1)
+
+	hits, _ = searchBooks(t, `"systems well" `+word+"*")
+	assert.Len(t, hits, 1)
+}
+
+func TestSearchBooksEscapesHighlights(t *testing.T) {
+	setupTestDB()
+
+	word := "xs" + strings.ReplaceAll(uuid.NewString()[:8], "-", "")
+	book := models.Book{Title: `<img src=x onerror=alert(1)> ` + word, Author: "A & B",
+		Description: `<script>` + word + `</script>`}
+	require.NoError(t, database.DB.Create(&book).Error)
+
+	hits, code := searchBooks(t, word)
+	assert.Equal(t, http.StatusOK, code)
+	require.Len(t, hits, 1)
+	assert.Equal(t, "&lt;img src=x onerror=alert(1)&gt; <mark>"+word+"</mark>", hits[0].Highlights["title"])
+	assert.Equal(t, "A &amp; B", hits[0].Highlights["author"])
+	assert.Equal(t, "&lt;script&gt;<mark>"+word+"</mark>&lt;/script&gt;", hits[0].Highlights["description"])
+	assert.Equal(t, book.Title, hits[0].Title, "the book itself is returned as stored")
+}
+
+func TestSearchBooksRequiresQuery(t *testing.T) {
+	_, code := searchBooks(t, "  ")
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestSearchBooksRejectsMalformedFTSQuery(t *testing.T) {
+	setupTestDB()
+	if !database.SearchIndexEnabled {
+		t.Skip("FTS5 not compiled in; LIKE search accepts any text")
+	}
+	_, code := searchBooks(t, `"unterminated`)
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestMigrateDropsStaleSearchTriggersWithoutFTS(t *testing.T) {
+	setupTestDB()
+	if database.SearchIndexEnabled {
+		t.Skip("FTS5 compiled in; the triggers are live")
+	}
+	// FTS5'li bir sürümün bıraktığı tetikleyici
+	require.NoError(t, database.DB.Exec(`CREATE TRIGGER IF NOT EXISTS books_fts_ai AFTER INSERT ON books BEGIN
+		INSERT INTO books_fts(rowid, title, author, description)
+		VALUES (new.rowid, new.title, new.author, new.description);
+	END`).Error)
+	require.NoError(t, database.Migrate(database.DB))
+
+	book := models.Book{Title: "After Downgrade", Author: "Anon"}
+	assert.NoError(t, database.DB.Create(&book).Error)
+}
@@ -25,6 +25,8 @@ type Envelope struct {
 
 func setupTestRouter() *gin.Engine {
 	gin.SetMode(gin.TestMode)
+	// built fresh on every run – results must not depend on earlier runs
+	os.Remove("test.db")
 	os.Setenv("DB_DSN", "test.db")
 
 	database.ConnectDB()
@@ -0,0 +1,170 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/jobs"
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func doRequest(t *testing.T, method, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	return sendWithHeaders(t, method, path, "", nil)
+}
+
+func TestSoftDeleteTrashAndRestore(t *testing.T) {
+	setupTestDB()
+	kind := "Trash-" + uuid.NewString()
+	book := models.Book{Title: "Deletable", Author: "Nobody", Type: kind}
+	require.NoError(t, database.DB.Create(&book).Error)
+
+	rec := doRequest(t, "DELETE", "/books/"+book.ID.String())
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// gizli: liste ve tekil GET
+	listed, _, _ := getBookPage(t, url.Values{"type": {kind}})
+	assert.Empty(t, listed)
+	assert.Equal(t, http.StatusNotFound, doRequest(t, "GET", "/books/"+book.ID.String()).Code)
+
+	// çöp kutusunda görünür
+	rec = doRequest(t, "GET", "/books/trash?type="+kind)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var trashed []models.Book
+	parseEnvelope(t, rec.Body.Bytes(), &trashed)
+	require.Len(t, trashed, 1)
+	assert.True(t, trashed[0].DeletedAt.Valid)
+
+	// geri yükle
+	rec = doRequest(t, "POST", "/books/"+book.ID.String()+"/restore")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, http.StatusOK, doRequest(t, "GET", "/books/"+book.ID.String()).Code)
+
+	// çöpte olmayan kitap geri yüklenemez
+	rec = doRequest(t, "POST", "/books/"+book.ID.String()+"/restore")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestTrashAndRestoreBumpVersion(t *testing.T) {
+	setupTestDB()
+	book := models.Book{Title: "Versioned Trash", Author: "Nobody"}
+	require.NoError(t, database.DB.Create(&book).Error)
+
+	rec := doRequest(t, "GET", "/books/"+book.ID.String())
+	require.Equal(t, http.StatusOK, rec.Code)
+	etag := rec.Header().Get("ETag")
+
+	require.Equal(t, http.StatusOK, doRequest(t, "DELETE", "/books/"+book.ID.String()).Code)
+	var trashed models.Book
+	require.NoError(t, database.DB.Unscoped().First(&trashed, "id = ?", book.ID).Error)
+	assert.Equal(t, book.Version+1, trashed.Version)
+
+	// çöpe atılmadan önceki ETag artık geçersiz
+	rec = sendWithHeaders(t, "POST", "/books/"+book.ID.String()+"/restore", "", map[string]string{"If-Match": etag})
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+
+	rec = doRequest(t, "POST", "/books/"+book.ID.String()+"/restore")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var restored models.Book
+	parseEnvelope(t, rec.Body.Bytes(), &restored)
+	assert.Equal(t, book.Version+2, restored.Version)
+	assert.Equal(t, restored.ETag, rec.Header().Get("ETag"))
+
+	// eski ETag ile koşullu GET artık 304 vermez
+	rec = sendWithHeaders(t, "GET", "/books/"+book.ID.String(), "", map[string]string{"If-None-Match": etag})
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// eski ETag ile If-Match artık kabul edilmez
+	rec = sendWithHeaders(t, "DELETE", "/books/"+book.ID.String(), "", map[string]string{"If-Match": etag})
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+}
+
+func TestDeleteBookPurge(t *testing.T) {
+	setupTestDB()
+	book := models.Book{Title: "Gone", Author: "Nobody"}
+	require.NoError(t, database.DB.Create(&book).Error)
+
+	// önce çöpe, sonra kalıcı
+	assert.Equal(t, http.StatusOK, doRequest(t, "DELETE", "/books/"+book.ID.String()).Code)
+	assert.Equal(t, http.StatusOK, doRequest(t, "DELETE", "/books/"+book.ID.String()+"?purge=true").Code)
+
+	var count int64
+	database.DB.Unscoped().Model(&models.Book{}).Where("id = ?", book.ID).Count(&count)
+	assert.Zero(t, count)
+}
+
+func TestPurgeRemovesDependentRows(t *testing.T) {
+	setupTestDB()
+	book := models.Book{Title: "Purged " + uuid.NewString()[:8], Author: "Nobody " + uuid.NewString()[:8], Type: "Gone"}
+	kept := models.Book{Title: "Kept", Author: "Somebody"}
+	require.NoError(t, database.DB.Create(&book).Error)
+	require.NoError(t, database.DB.Create(&kept).Error)
+	member := createMember(t, "Purge")
+	_, code := postReview(t, book.ID, member.ID, 4)
+	require.Equal(t, http.StatusCreated, code)
+	createCopy(t, book.ID)
+	_, code = placeHold(t, book.ID, createMember(t, "Queue").ID)
+	require.Less(t, code, 300)
+
+	list := models.ReadingList{MemberID: member.ID, Name: "Mixed"}
+	require.NoError(t, database.DB.Create(&list).Error)
+	require.NoError(t, database.DB.Create(&models.ReadingListItem{ListID: list.ID, BookID: book.ID, Position: 1}).Error)
+	require.NoError(t, database.DB.Create(&models.ReadingListItem{ListID: list.ID, BookID: kept.ID, Position: 2}).Error)
+
+	require.Equal(t, http.StatusOK, doRequest(t, "DELETE", "/books/"+book.ID.String()+"?purge=true").Code)
+
+	for _, dep := range []any{&models.BookAuthor{}, &models.BookTag{}, &models.Review{},
+		&models.BookRating{}, &models.Copy{}, &models.Hold{}, &models.ReadingListItem{}} {
+		var n int64
+		database.DB.Model(dep).Where("book_id = ?", book.ID).Count(&n)
+		assert.Zero(t, n, "%T", dep)
+	}
+	var item models.ReadingListItem
+	require.NoError(t, database.DB.First(&item, "list_id = ?", list.ID).Error)
+	assert.Equal(t, kept.ID, item.BookID)
+	assert.Equal(t, 1, item.Position, "the gap is closed")
+}
+
+func TestPurgeRefusesBooksWithLoans(t *testing.T) {
+	setupTestDB()
+	book := models.Book{Title: "Lent", Author: "Nobody"}
+	require.NoError(t, database.DB.Create(&book).Error)
+	_, code := checkout(t, createCopy(t, book.ID).ID, createMember(t, "Borrower").ID)
+	require.Equal(t, http.StatusCreated, code)
+
+	rec := doRequest(t, "DELETE", "/books/"+book.ID.String()+"?purge=true")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	// the purger leaves it in the trash as well
+	require.Equal(t, http.StatusOK, doRequest(t, "DELETE", "/books/"+book.ID.String()).Code)
+	database.DB.Unscoped().Model(&book).Update("deleted_at", time.Now().Add(-48*time.Hour))
+	_, err := jobs.PurgeTrash(database.DB, 24*time.Hour)
+	require.NoError(t, err)
+	var count int64
+	database.DB.Unscoped().Model(&models.Book{}).Where("id = ?", book.ID).Count(&count)
+	assert.EqualValues(t, 1, count)
+}
+
+func TestPurgeTrashHonoursRetention(t *testing.T) {
+	setupTestDB()
+	old := models.Book{Title: "Old", Author: "Nobody"}
+	fresh := models.Book{Title: "Fresh", Author: "Nobody"}
+	require.NoError(t, database.DB.Create(&old).Error)
+	require.NoError(t, database.DB.Create(&fresh).Error)
+	database.DB.Unscoped().Model(&old).Update("deleted_at", time.Now().Add(-48*time.Hour))
+	database.DB.Delete(&fresh)
+
+	_, err := jobs.PurgeTrash(database.DB, 24*time.Hour)
+	require.NoError(t, err)
+
+	var ids []uuid.UUID
+	database.DB.Unscoped().Model(&models.Book{}).Where("id IN ?", []uuid.UUID{old.ID, fresh.ID}).Pluck("id", &ids)
+	assert.Equal(t, []uuid.UUID{fresh.ID}, ids)
+}
@@ -0,0 +1,175 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createMember(t *testing.T, name string) models.Member {
+	t.Helper()
+	body := fmt.Sprintf(`{"name": %q, "email": "%s@example.org"}`, name, uuid.NewString())
+	rec := sendJSON(t, "POST", "/members", "application/json", body)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var m models.Member
+	parseEnvelope(t, rec.Body.Bytes(), &m)
+	return m
+}
+
+func createCopy(t *testing.T, bookID uuid.UUID) models.Copy {
+	t.Helper()
+	body := fmt.Sprintf(`{"barcode": "BC-%s", "location": "Stacks A"}`, uuid.NewString()[:8])
+	rec := sendJSON(t, "POST", "/books/"+bookID.String()+"/copies", "application/json", body)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var cp models.Copy
+	parseEnvelope(t, rec.Body.Bytes(), &cp)
+	return cp
+}
+
+func checkout(t *testing.T, copyID, memberID uuid.UUID) (models.Loan, int) {
+	t.Helper()
+	rec := sendJSON(t, "POST", "/loans", "application/json",
+		fmt.Sprintf(`{"copy_id": %q, "member_id": %q}`, copyID, memberID))
+	var loan models.Loan
+	if rec.Code == http.StatusCreated {
+		parseEnvelope(t, rec.Body.Bytes(), &loan)
+	}
+	return loan, rec.Code
+}
+
+func bookAvailability(t *testing.T, bookID uuid.UUID) map[string]int {
+	t.Helper()
+	rec := doRequest(t, "GET", "/books/"+bookID.String())
+	require.Equal(t, http.StatusOK, rec.Code)
+	var detail struct {
+		Availability map[string]int `json:"availability"`
+	}
+	parseEnvelope(t, rec.Body.Bytes(), &detail)
+	return detail.Availability
+}
+
+func TestCheckoutReturnRenew(t *testing.T) {
+	setupTestDB()
+	book := models.Book{Title: "The Pragmatic Programmer", Author: "Hunt & Thomas"}
+	require.NoError(t, database.DB.Create(&book).Error)
+	first, second := createCopy(t, book.ID), createCopy(t, book.ID)
+	ada, bob := createMember(t, "Ada"), createMember(t, "Bob")
+
+	assert.Equal(t, map[string]int{"copies": 2, "on_loan": 0, "available": 2}, bookAvailability(t, book.ID))
+
+	loan, code := checkout(t, first.ID, ada.ID)
+	require.Equal(t, http.StatusCreated, code)
+	assert.True(t, loan.Active())
+	assert.True(t, loan.DueAt.After(loan.CheckedOutAt))
+	assert.True(t, loan.Copy.OnLoan)
+
+	// aynı kopya ikinci kez ödünç verilemez
+	_, code = checkout(t, first.ID, bob.ID)
+	assert.Equal(t, http.StatusConflict, code)
+	assert.Equal(t, map[string]int{"copies": 2, "on_loan": 1, "available": 1}, bookAvailability(t, book.ID))
+
+	// barkodla ödünç
+	rec := sendJSON(t, "POST", "/loans", "application/json",
+		fmt.Sprintf(`{"barcode": %q, "member_id": %q}`, second.Barcode, bob.ID))
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	// yenileme – limit 2
+	path := "/loans/" + loan.ID.String()
+	for i := 1; i <= 2; i++ {
+		rec = doRequest(t, "POST", path+"/renew")
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var renewed models.Loan
+		parseEnvelope(t, rec.Body.Bytes(), &renewed)
+		assert.Equal(t, i, renewed.Renewals)
+		assert.False(t, renewed.DueAt.Before(loan.DueAt))
+	}
+	assert.Equal(t, http.StatusConflict, doRequest(t, "POST", path+"/renew").Code)
+
+	rec = doRequest(t, "POST", path+"/return")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var returned models.Loan
+	parseEnvelope(t, rec.Body.Bytes(), &returned)
+	assert.NotNil(t, returned.ReturnedAt)
+	assert.Equal(t, http.StatusConflict, doRequest(t, "POST", path+"/return").Code)
+	assert.Equal(t, http.StatusConflict, doRequest(t, "POST", path+"/renew").Code)
+
+	// üye geçmişi ve aktif filtre
+	rec = doRequest(t, "GET", "/members/"+ada.ID.String()+"/loans?active=false")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var loans []models.Loan
+	parseEnvelope(t, rec.Body.Bytes(), &loans)
+	require.Len(t, loans, 1)
+	assert.Equal(t, loan.ID, loans[0].ID)
+
+	// aktif ödüncü olan üye ve kopya silinemez
+	assert.Equal(t, http.StatusConflict, doRequest(t, "DELETE", "/members/"+bob.ID.String()).Code)
+	assert.Equal(t, http.StatusConflict, doRequest(t, "DELETE", "/copies/"+second.ID.String()).Code)
+	assert.Equal(t, http.StatusOK, doRequest(t, "DELETE", "/copies/"+first.ID.String()).Code)
+}
+
+func TestConcurrentCheckoutsLendOnce(t *testing.T) {
+	setupTestDB()
+	book := models.Book{Title: "Designing Data-Intensive Applications", Author: "Martin Kleppmann"}
+	require.NoError(t, database.DB.Create(&book).Error)
+	cp := createCopy(t, book.ID)
+
+	members := make([]models.Member, 5)
+	for i := range members {
+		members[i] = createMember(t, fmt.Sprintf("Racer %d", i))
+	}
+
+	var wg sync.WaitGroup
+	codes := make([]int, len(members))
+	for i := range members {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, codes[i] = checkout(t, cp.ID, members[i].ID)
+		}(i)
+	}
+	wg.Wait()
+
+	var active int64
+	database.DB.Model(&models.Loan{}).Where("copy_id = ? AND returned_at IS NULL", cp.ID).Count(&active)
+	assert.EqualValues(t, 1, active, "codes: %v", codes)
+	assert.Contains(t, codes, http.StatusCreated)
+}
+
+func TestCirculationValidation(t *testing.T) {
+	setupTestDB()
+	book := models.Book{Title: "Working Effectively with Legacy Code", Author: "Michael Feathers"}
+	require.NoError(t, database.DB.Create(&book).Error)
+	cp := createCopy(t, book.ID)
+
+	rec := sendJSON(t, "POST", "/books/"+book.ID.String()+"/copies", "application/json",
+		fmt.Sprintf(`{"barcode": %q}`, cp.Barcode))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	rec = sendJSON(t, "POST", "/books/"+book.ID.String()+"/copies", "application/json",
+		`{"barcode": "X-1", "condition": "soggy"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(t, fieldErrors(t, rec)["condition"], "must be one of")
+
+	rec = sendJSON(t, "POST", "/members", "application/json", `{"name": "Eve", "email": "not-an-email"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Equal(t, "must be a valid email address", fieldErrors(t, rec)["email"])
+
+	rec = sendJSON(t, "POST", "/loans", "application/json", fmt.Sprintf(`{"member_id": %q}`, uuid.New()))
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	fields := fieldErrors(t, rec)
+	assert.Equal(t, "copy_id or barcode is required", fields["copy_id"])
+	assert.Equal(t, "member not found", fields["member_id"])
+
+	rec = doRequest(t, "GET", "/copies?barcode="+cp.Barcode)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var found models.Copy
+	parseEnvelope(t, rec.Body.Bytes(), &found)
+	assert.Equal(t, cp.ID, found.ID)
+	assert.Equal(t, models.ConditionGood, found.Condition)
+}
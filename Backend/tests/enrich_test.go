@@ -0,0 +1,157 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/enrichment"
+	"github.com/hasan-kayan/TaskGo/handlers"
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func enrich(t *testing.T, id, query string) (handlers.EnrichmentResult, *httptest.ResponseRecorder) {
+	t.Helper()
+	rec := doRequest(t, "POST", "/books/"+id+"/enrich"+query)
+	var out handlers.EnrichmentResult
+	if rec.Code == http.StatusOK {
+		parseEnvelope(t, rec.Body.Bytes(), &out)
+	}
+	return out, rec
+}
+
+func TestEnrichBookPreviewAndApply(t *testing.T) {
+	setupTestDB()
+	isbn := uniqueISBN()
+	handlers.SetEnricher(enrichment.NewFixture(map[string]enrichment.Metadata{
+		isbn: {Description: "A handbook of agile craftsmanship", Publisher: "Prentice Hall",
+			Pages: 464, Year: 2008, CoverImageURL: "not a url"},
+	}))
+
+	book := models.Book{Title: "Clean Code", Author: "Robert C. Martin", ISBN: isbn, Pages: 431}
+	require.NoError(t, database.DB.Create(&book).Error)
+
+	// preview – nothing is written, set fields and bad values are left alone
+	out, rec := enrich(t, book.ID.String(), "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.False(t, out.Applied)
+	assert.Equal(t, "fixture", out.Source)
+	assert.Equal(t, []string{"description", "publisher", "year"}, sortedKeys(out.Changes))
+	assert.EqualValues(t, 2008, out.Changes["year"].To)
+	assert.Equal(t, 431, out.Book.Pages)
+	var stored models.Book
+	require.NoError(t, database.DB.First(&stored, "id = ?", book.ID).Error)
+	assert.Empty(t, stored.Description)
+
+	out, rec = enrich(t, book.ID.String(), "?apply=true&fields=year,publisher")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.True(t, out.Applied)
+	assert.Equal(t, []string{"publisher", "year"}, sortedKeys(out.Changes))
+	require.NoError(t, database.DB.First(&stored, "id = ?", book.ID).Error)
+	assert.Equal(t, 2008, stored.Year)
+	assert.Equal(t, "Prentice Hall", stored.Publisher)
+	assert.Empty(t, stored.Description)
+	assert.Equal(t, book.Version+1, stored.Version)
+
+	// nothing left to fill for those fields
+	out, _ = enrich(t, book.ID.String(), "?apply=true&fields=year")
+	assert.False(t, out.Applied)
+	assert.Empty(t, out.Changes)
+
+	_, rec = enrich(t, book.ID.String(), "?fields=title")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	noISBN := models.Book{Title: "Untitled", Author: "Anon"}
+	require.NoError(t, database.DB.Create(&noISBN).Error)
+	_, rec = enrich(t, noISBN.ID.String(), "")
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	handlers.SetEnricher(enrichment.NewFixture(nil))
+	_, rec = enrich(t, book.ID.String(), "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestOpenLibraryProviderCacheAndTimeout(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		switch r.URL.Path {
+		case "/isbn/9780132350884.json":
+			w.Write([]byte(`{"title": "Clean Code", "publishers": ["Prentice Hall"], "number_of_pages": 464,
+				"publish_date": "August 1, 2008", "covers": [123], "works": [{"key": "/works/OL1W"}]}`))
+		case "/works/OL1W.json":
+			w.Write([]byte(`{"description": {"type": "/type/text", "value": "Even bad code can function."}}`))
+		case "/isbn/9780000000002.json":
+			time.Sleep(200 * time.Millisecond)
+			w.Write([]byte(`{}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	ol := enrichment.NewOpenLibrary(srv.URL)
+	ol.CoversURL = "https://covers.example"
+	p := enrichment.Cached(enrichment.WithTimeout(ol, 50*time.Millisecond), time.Minute)
+	ctx := context.Background()
+
+	m, err := p.Lookup(ctx, "9780132350884")
+	require.NoError(t, err)
+	assert.Equal(t, enrichment.Metadata{Title: "Clean Code", Description: "Even bad code can function.",
+		Publisher: "Prentice Hall", Pages: 464, Year: 2008,
+		CoverImageURL: "https://covers.example/b/id/123-L.jpg", Source: "openlibrary"}, *m)
+	_, err = p.Lookup(ctx, "9780132350884")
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, hits.Load(), "second lookup is served from the cache")
+
+	_, err = p.Lookup(ctx, "9781111111113")
+	assert.ErrorIs(t, err, enrichment.ErrNotFound)
+	_, err = p.Lookup(ctx, "9780000000002")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func sortedKeys(changes models.FieldChanges) []string {
+	keys := make([]string, 0, len(changes))
+	for k := range changes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestEnrichmentFromEnvRejectsMisconfiguration(t *testing.T) {
+	t.Setenv("ENRICH_PROVIDERS", "")
+	p, err := enrichment.FromEnv()
+	require.NoError(t, err)
+	assert.NotNil(t, p)
+
+	// bilinmeyen sağlayıcı, yüklenemeyen fixture, boş liste → hata
+	t.Setenv("ENRICH_PROVIDERS", "openlibrary,goodreads")
+	_, err = enrichment.FromEnv()
+	assert.ErrorContains(t, err, "goodreads")
+
+	t.Setenv("ENRICH_PROVIDERS", "fixture")
+	t.Setenv("ENRICH_FIXTURE_FILE", t.TempDir()+"/missing.json")
+	_, err = enrichment.FromEnv()
+	assert.Error(t, err)
+
+	t.Setenv("ENRICH_PROVIDERS", " , ")
+	_, err = enrichment.FromEnv()
+	assert.Error(t, err)
+}
+
+func TestEnrichBookWithoutProvider(t *testing.T) {
+	setupTestDB()
+	book := models.Book{Title: "Unconfigured", Author: "Anon", ISBN: uniqueISBN()}
+	require.NoError(t, database.DB.Create(&book).Error)
+
+	handlers.SetEnricher(nil)
+	defer handlers.SetEnricher(enrichment.NewFixture(nil))
+	_, rec := enrich(t, book.ID.String(), "")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
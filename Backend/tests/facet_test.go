@@ -0,0 +1,61 @@
+package tests
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/handlers"
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func getFacets(t *testing.T, q url.Values) handlers.BookFacets {
+	t.Helper()
+	rec := doRequest(t, "GET", "/books/facets?"+q.Encode())
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var out handlers.BookFacets
+	parseEnvelope(t, rec.Body.Bytes(), &out)
+	return out
+}
+
+func TestBookFacetsFollowFilters(t *testing.T) {
+	setupTestDB()
+	tag := uuid.NewString()[:8]
+	seed := []models.Book{
+		{Title: "Go " + tag, Author: "Pike", Type: "Programming", Year: 2015, Pages: 380, Publisher: "Addison"},
+		{Title: "C " + tag, Author: "Kernighan", Type: "Programming", Year: 1988, Pages: 272, Publisher: "Prentice"},
+		{Title: "Unix " + tag, Author: "Kernighan", Type: "History", Year: 2019, Pages: 180},
+		{Title: "Notes " + tag, Author: "Anon", Type: "Programming"},
+	}
+	for i := range seed {
+		require.NoError(t, database.DB.Create(&seed[i]).Error)
+	}
+	// çöptekiler sayılmaz
+	trashed := models.Book{Title: "Old " + tag, Author: "Pike", Type: "Programming", Year: 1970}
+	require.NoError(t, database.DB.Create(&trashed).Error)
+	require.Equal(t, http.StatusOK, doRequest(t, "DELETE", "/books/"+trashed.ID.String()).Code)
+
+	out := getFacets(t, url.Values{"title": {tag}})
+	assert.EqualValues(t, 4, out.Total)
+	assert.Equal(t, []handlers.FacetValue{{Value: "Programming", Count: 3}, {Value: "History", Count: 1}}, out.Facets["type"])
+	assert.Equal(t, handlers.FacetValue{Value: "Kernighan", Count: 2}, out.Facets["author"][0])
+	assert.Equal(t, []handlers.FacetValue{{Value: "Addison", Count: 1}, {Value: "Prentice", Count: 1}}, out.Facets["publisher"])
+	assert.Equal(t, []handlers.FacetValue{{Value: "2010", Count: 2}, {Value: "1980", Count: 1}}, out.Facets["decade"])
+	assert.Equal(t, []handlers.FacetValue{{Value: "100-199", Count: 1}, {Value: "200-299", Count: 1}, {Value: "300-499", Count: 1}}, out.Facets["pages"])
+
+	// uygulanan filtreler sayımları daraltır
+	out = getFacets(t, url.Values{"title": {tag}, "type": {"Programming"}, "facets": {"author"}})
+	assert.EqualValues(t, 3, out.Total)
+	assert.Len(t, out.Facets, 1)
+	assert.Len(t, out.Facets["author"], 3)
+
+	out = getFacets(t, url.Values{"title": {tag}, "facets": {"author"}, "limit": {"1"}})
+	assert.Equal(t, []handlers.FacetValue{{Value: "Kernighan", Count: 2}}, out.Facets["author"])
+
+	assert.Equal(t, http.StatusBadRequest, doRequest(t, "GET", "/books/facets?facets=colour").Code)
+	assert.Equal(t, http.StatusBadRequest, doRequest(t, "GET", "/books/facets?min_rating=9").Code)
+}
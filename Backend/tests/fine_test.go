@@ -0,0 +1,144 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/handlers"
+	"github.com/hasan-kayan/TaskGo/jobs"
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func day(s string) time.Time {
+	t, err := time.ParseInLocation(time.DateOnly, s, time.Local)
+	if err != nil {
+		panic(err)
+	}
+	return t.Add(12 * time.Hour)
+}
+
+func TestFinePolicyAssess(t *testing.T) {
+	policy := models.FinePolicy{DailyRate: 50, GraceDays: 2, MaxFine: 400}
+	closed := map[string]bool{"2026-03-04": true}
+
+	cases := []struct {
+		until  string
+		days   int
+		amount int64
+	}{
+		{"2026-03-01", 0, 0},   // vade günü
+		{"2026-03-03", 2, 0},   // tolerans içinde
+		{"2026-03-04", 2, 0},   // kapalı gün sayılmaz
+		{"2026-03-06", 4, 100}, // 2 gün ücretli
+		{"2026-03-31", 29, 400},
+	}
+	for _, tc := range cases {
+		days, amount := policy.Assess(day("2026-03-01"), day(tc.until), closed)
+		assert.Equal(t, tc.days, days, tc.until)
+		assert.Equal(t, tc.amount, amount, tc.until)
+	}
+}
+
+func TestOverdueJobFinesAndSettlement(t *testing.T) {
+	setupTestDB()
+	book := models.Book{Title: "Clean Architecture", Author: "Robert C. Martin", Type: "Reference"}
+	require.NoError(t, database.DB.Create(&book).Error)
+	cp := createCopy(t, book.ID)
+	ada := createMember(t, "Ada")
+
+	rec := sendJSON(t, "PUT", "/fine-policies/reference", "application/json",
+		`{"daily_rate": 100, "grace_days": 1, "max_fine": 0}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	rec = sendJSON(t, "PUT", "/fine-policies/x", "application/json", `{"daily_rate": -1}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	loan, code := checkout(t, cp.ID, ada.ID)
+	require.Equal(t, http.StatusCreated, code)
+	due := time.Now().AddDate(0, 0, -5)
+	require.NoError(t, database.DB.Model(&models.Loan{}).Where("id = ?", loan.ID).Update("due_at", due).Error)
+
+	closedDay := due.AddDate(0, 0, 2).Format(time.DateOnly)
+	rec = sendJSON(t, "PUT", "/closed-days/"+closedDay, "application/json", `{"name": "Bayram"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, http.StatusUnprocessableEntity, doRequest(t, "PUT", "/closed-days/31-12-2026").Code)
+
+	// gecikmiş ödünç yenilenemez
+	assert.Equal(t, http.StatusConflict, doRequest(t, "POST", "/loans/"+loan.ID.String()+"/renew").Code)
+
+	n, err := jobs.MarkOverdue(database.DB, time.Now())
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, n)
+	n, _ = jobs.MarkOverdue(database.DB, time.Now())
+	assert.Zero(t, n, "loans are marked once")
+
+	rec = doRequest(t, "GET", "/loans/"+loan.ID.String())
+	var marked models.Loan
+	parseEnvelope(t, rec.Body.Bytes(), &marked)
+	assert.NotNil(t, marked.OverdueSince)
+
+	// 5 gün − 1 kapalı gün − 1 tolerans = 3 gün × 100
+	rec = doRequest(t, "GET", "/members/"+ada.ID.String()+"/fines")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var fines handlers.MemberFines
+	parseEnvelope(t, rec.Body.Bytes(), &fines)
+	require.Len(t, fines.Fines, 1)
+	fine := fines.Fines[0]
+	assert.Equal(t, "reference", fine.PolicyType)
+	assert.Equal(t, 4, fine.OverdueDays)
+	assert.EqualValues(t, 300, fine.Amount)
+	assert.EqualValues(t, 300, fines.Balance)
+	assert.False(t, fine.Final)
+
+	finePath := "/fines/" + fine.ID.String()
+	rec = sendJSON(t, "POST", finePath+"/pay", "application/json", `{"amount": 500}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	rec = sendJSON(t, "POST", finePath+"/pay", "application/json", `{"amount": 200}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	parseEnvelope(t, rec.Body.Bytes(), &fine)
+	assert.EqualValues(t, 100, fine.Balance)
+
+	// iade cezayı kesinleştirir
+	rec = doRequest(t, "POST", "/loans/"+loan.ID.String()+"/return")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var returned handlers.ReturnResult
+	parseEnvelope(t, rec.Body.Bytes(), &returned)
+	require.NotNil(t, returned.Fine)
+	assert.True(t, returned.Fine.Final)
+	assert.EqualValues(t, 300, returned.Fine.Amount)
+
+	assert.Equal(t, http.StatusConflict, doRequest(t, "DELETE", "/members/"+ada.ID.String()).Code)
+
+	rec = sendJSON(t, "POST", finePath+"/waive", "application/json", `{}`)
+	assert.Equal(t, "is required", fieldErrors(t, rec)["reason"])
+	rec = sendJSON(t, "POST", finePath+"/waive", "application/json", `{"reason": "first offence"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	parseEnvelope(t, rec.Body.Bytes(), &fine)
+	assert.EqualValues(t, 100, fine.Waived)
+	assert.Zero(t, fine.Balance)
+
+	rec = doRequest(t, "GET", "/members/"+ada.ID.String()+"/fines?outstanding=true")
+	parseEnvelope(t, rec.Body.Bytes(), &fines)
+	assert.Empty(t, fines.Fines)
+	assert.Equal(t, http.StatusOK, doRequest(t, "DELETE", "/members/"+ada.ID.String()).Code)
+}
+
+func TestDefaultFinePolicyIsSeededAndKept(t *testing.T) {
+	setupTestDB()
+	rec := doRequest(t, "GET", "/fine-policies")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var policies []models.FinePolicy
+	parseEnvelope(t, rec.Body.Bytes(), &policies)
+
+	var found bool
+	for _, p := range policies {
+		found = found || p.Type == models.DefaultPolicyType
+	}
+	assert.True(t, found, fmt.Sprint(policies))
+	assert.Equal(t, http.StatusConflict, doRequest(t, "DELETE", "/fine-policies/*").Code)
+	assert.Equal(t, http.StatusNotFound, doRequest(t, "DELETE", "/fine-policies/no-such-type").Code)
+}
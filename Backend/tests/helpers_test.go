@@ -2,7 +2,9 @@ package tests
 
 import (
 	"encoding/json"
+	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/hasan-kayan/TaskGo/models"
@@ -40,3 +42,18 @@ func parseError(t *testing.T, rec *httptest.ResponseRecorder) string {
 	_ = json.Unmarshal(rec.Body.Bytes(), &errResp)
 	return errResp.Error
 }
+
+// sendWithHeaders – isteği test router'ından geçirir; diğer istek
+// yardımcıları hep bunu çağırır
+func sendWithHeaders(t *testing.T, method, path, body string, headers map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+	r := testRouter()
+
+	req, _ := http.NewRequest(method, path, strings.NewReader(body))
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
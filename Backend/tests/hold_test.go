@@ -0,0 +1,145 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/jobs"
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func placeHold(t *testing.T, bookID, memberID uuid.UUID) (models.Hold, int) {
+	t.Helper()
+	rec := sendJSON(t, "POST", "/books/"+bookID.String()+"/holds", "application/json",
+		fmt.Sprintf(`{"member_id": %q}`, memberID))
+	var hold models.Hold
+	if rec.Code == http.StatusCreated {
+		parseEnvelope(t, rec.Body.Bytes(), &hold)
+	}
+	return hold, rec.Code
+}
+
+func getHold(t *testing.T, id uuid.UUID) models.Hold {
+	t.Helper()
+	rec := doRequest(t, "GET", "/holds/"+id.String())
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var hold models.Hold
+	parseEnvelope(t, rec.Body.Bytes(), &hold)
+	return hold
+}
+
+func TestHoldQueueAssignsReturnedCopies(t *testing.T) {
+	setupTestDB()
+	book := models.Book{Title: "Structure and Interpretation of Computer Programs", Author: "Abelson & Sussman"}
+	require.NoError(t, database.DB.Create(&book).Error)
+	cp := createCopy(t, book.ID)
+	ada, bob, cem := createMember(t, "Ada"), createMember(t, "Bob"), createMember(t, "Cem")
+
+	loan, code := checkout(t, cp.ID, ada.ID)
+	require.Equal(t, http.StatusCreated, code)
+
+	// sıra: önce Bob, sonra Cem
+	bobHold, code := placeHold(t, book.ID, bob.ID)
+	require.Equal(t, http.StatusCreated, code)
+	assert.Equal(t, models.HoldWaiting, bobHold.Status)
+	assert.EqualValues(t, 1, bobHold.Position)
+	cemHold, _ := placeHold(t, book.ID, cem.ID)
+	assert.EqualValues(t, 2, cemHold.Position)
+
+	_, code = placeHold(t, book.ID, bob.ID)
+	assert.Equal(t, http.StatusConflict, code, "one open hold per member and book")
+
+	// iade edilen kopya sıradaki ilk üyeye ayrılır
+	rec := doRequest(t, "POST", "/loans/"+loan.ID.String()+"/return")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var returned struct {
+		Hold *models.Hold `json:"hold"`
+	}
+	parseEnvelope(t, rec.Body.Bytes(), &returned)
+	require.NotNil(t, returned.Hold)
+	assert.Equal(t, bobHold.ID, returned.Hold.ID)
+
+	bobHold = getHold(t, bobHold.ID)
+	assert.Equal(t, models.HoldReady, bobHold.Status)
+	require.NotNil(t, bobHold.CopyID)
+	assert.Equal(t, cp.ID, *bobHold.CopyID)
+	assert.WithinDuration(t, time.Now().Add(7*24*time.Hour), *bobHold.ExpiresAt, time.Minute)
+	assert.EqualValues(t, 1, getHold(t, cemHold.ID).Position)
+
+	assert.Equal(t, map[string]int{"copies": 1, "on_loan": 0, "on_hold": 1, "available": 0, "holds_waiting": 1},
+		bookAvailability(t, book.ID))
+
+	// ayrılan kopyayı yalnızca Bob alabilir
+	_, code = checkout(t, cp.ID, ada.ID)
+	assert.Equal(t, http.StatusConflict, code)
+	_, code = checkout(t, cp.ID, bob.ID)
+	require.Equal(t, http.StatusCreated, code)
+	assert.Equal(t, models.HoldFulfilled, getHold(t, bobHold.ID).Status)
+
+	rec = doRequest(t, "GET", "/members/"+bob.ID.String()+"/holds?status=all")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var holds []models.Hold
+	parseEnvelope(t, rec.Body.Bytes(), &holds)
+	require.Len(t, holds, 1)
+	assert.Equal(t, models.HoldFulfilled, holds[0].Status)
+
+	assert.Equal(t, http.StatusBadRequest, doRequest(t, "GET", "/members/"+bob.ID.String()+"/holds?status=lost").Code)
+}
+
+func TestCancelAndExpiryPassCopyDownTheQueue(t *testing.T) {
+	setupTestDB()
+	book := models.Book{Title: "Refactoring", Author: "Martin Fowler"}
+	require.NoError(t, database.DB.Create(&book).Error)
+	ada, bob, cem := createMember(t, "Ada"), createMember(t, "Bob"), createMember(t, "Cem")
+
+	// kopyası olmayan kitap beklenemez
+	_, code := placeHold(t, book.ID, ada.ID)
+	assert.Equal(t, http.StatusConflict, code)
+
+	cp := createCopy(t, book.ID)
+	adaHold, _ := placeHold(t, book.ID, ada.ID)
+	assert.Equal(t, models.HoldReady, adaHold.Status, "a free copy is set aside at once")
+	bobHold, _ := placeHold(t, book.ID, bob.ID)
+	cemHold, _ := placeHold(t, book.ID, cem.ID)
+
+	// Ada iptal eder – kopya Bob'a geçer
+	rec := doRequest(t, "POST", "/holds/"+adaHold.ID.String()+"/cancel")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, http.StatusConflict, doRequest(t, "POST", "/holds/"+adaHold.ID.String()+"/cancel").Code)
+	assert.Equal(t, models.HoldReady, getHold(t, bobHold.ID).Status)
+	assert.Equal(t, http.StatusConflict, doRequest(t, "DELETE", "/copies/"+cp.ID.String()).Code)
+
+	// süresi dolmamış tutmalar kalır
+	n, err := jobs.ExpireHolds(database.DB, time.Now(), 7*24*time.Hour)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	// Bob gelmez – süre dolunca kopya Cem'e geçer
+	n, err = jobs.ExpireHolds(database.DB, time.Now().Add(8*24*time.Hour), 7*24*time.Hour)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, n)
+	assert.Equal(t, models.HoldExpired, getHold(t, bobHold.ID).Status)
+	cemHold = getHold(t, cemHold.ID)
+	assert.Equal(t, models.HoldReady, cemHold.Status)
+	assert.Equal(t, cp.ID, *cemHold.CopyID)
+
+	rec = doRequest(t, "GET", "/books/"+book.ID.String()+"/holds")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var queue []models.Hold
+	parseEnvelope(t, rec.Body.Bytes(), &queue)
+	require.Len(t, queue, 1)
+	assert.Equal(t, cemHold.ID, queue[0].ID)
+
+	// yeni kopya bekleyen olmayınca serbest kalır
+	second := createCopy(t, book.ID)
+	assert.Equal(t, map[string]int{"copies": 2, "on_loan": 0, "on_hold": 1, "available": 1},
+		bookAvailability(t, book.ID))
+	_, code = checkout(t, second.ID, ada.ID)
+	assert.Equal(t, http.StatusCreated, code)
+}
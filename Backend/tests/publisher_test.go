@@ -0,0 +1,133 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createPublisher – POST /publishers, parent boş olabilir
+func createPublisher(t *testing.T, name string, parent *uuid.UUID) models.Publisher {
+	t.Helper()
+	body := fmt.Sprintf(`{"name": %q}`, name)
+	if parent != nil {
+		body = fmt.Sprintf(`{"name": %q, "parent_id": %q}`, name, parent)
+	}
+	rec := sendJSON(t, "POST", "/publishers", "application/json", body)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var p models.Publisher
+	parseEnvelope(t, rec.Body.Bytes(), &p)
+	return p
+}
+
+func TestPublisherImprintsAndBookFilter(t *testing.T) {
+	setupTestDB()
+	suffix := uuid.NewString()[:8]
+	house := createPublisher(t, "Penguin Random House "+suffix, nil)
+	imprint := createPublisher(t, "Vintage "+suffix, &house.ID)
+	kind := "Publisher-" + suffix
+
+	rec := sendJSON(t, "POST", "/books", "application/json", fmt.Sprintf(
+		`{"title": "Beloved", "author": "Toni Morrison", "publisher": "whatever", "publisher_id": %q, "type": %q}`, imprint.ID, kind))
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var book models.Book
+	parseEnvelope(t, rec.Body.Bytes(), &book)
+	assert.Equal(t, imprint.Name, book.Publisher, "display name follows the record")
+
+	// bilinmeyen yayınevi → 422
+	rec = sendJSON(t, "POST", "/books", "application/json", fmt.Sprintf(
+		`{"title": "Orphan", "author": "Nobody", "publisher_id": %q}`, uuid.New()))
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Equal(t, "publisher not found", fieldErrors(t, rec)["publisher_id"])
+
+	// ana yayınevi filtresi alt markaları da kapsar
+	listed, _, _ := getBookPage(t, url.Values{"type": {kind}, "publisher_id": {house.ID.String()}})
+	require.Len(t, listed, 1)
+	assert.Equal(t, book.ID, listed[0].ID)
+
+	rec = doRequest(t, "GET", "/publishers/"+house.ID.String())
+	var got models.Publisher
+	parseEnvelope(t, rec.Body.Bytes(), &got)
+	require.Len(t, got.Imprints, 1)
+	assert.Equal(t, imprint.ID, got.Imprints[0].ID)
+
+	// döngü yasak: ana yayınevi kendi alt markasının alt markası olamaz
+	rec = sendJSON(t, "PUT", "/publishers/"+house.ID.String(), "application/json",
+		fmt.Sprintf(`{"name": %q, "parent_id": %q}`, house.Name, imprint.ID))
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	// kitap ve alt marka varken silinemez
+	assert.Equal(t, http.StatusConflict, doRequest(t, "DELETE", "/publishers/"+house.ID.String()).Code)
+
+	// yeniden adlandırma kitaplara yansır
+	rec = sendJSON(t, "PUT", "/publishers/"+imprint.ID.String(), "application/json",
+		fmt.Sprintf(`{"name": "Vintage Books %s", "parent_id": %q}`, suffix, house.ID))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var stored models.Book
+	database.DB.First(&stored, "id = ?", book.ID)
+	assert.Equal(t, "Vintage Books "+suffix, stored.Publisher)
+	assert.Equal(t, book.Version+1, stored.Version)
+}
+
+func TestMergePublishers(t *testing.T) {
+	setupTestDB()
+	suffix := uuid.NewString()[:8]
+	target := createPublisher(t, "O'Reilly Media "+suffix, nil)
+	dupe := createPublisher(t, "OReilly "+suffix, nil)
+	dupeImprint := createPublisher(t, "Make "+suffix, &dupe.ID)
+
+	book := models.Book{Title: "Learning Go", Author: "Jon Bodner", Publisher: dupe.Name, PublisherID: &dupe.ID}
+	require.NoError(t, database.DB.Create(&book).Error)
+
+	path := "/publishers/" + target.ID.String() + "/merge"
+	rec := sendJSON(t, "POST", path, "application/json", fmt.Sprintf(`{"source_ids": [%q]}`, target.ID))
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	rec = sendJSON(t, "POST", path, "application/json", fmt.Sprintf(`{"source_ids": [%q]}`, dupe.ID))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var result struct {
+		BooksMoved    int64 `json:"books_moved"`
+		ImprintsMoved int64 `json:"imprints_moved"`
+	}
+	parseEnvelope(t, rec.Body.Bytes(), &result)
+	assert.EqualValues(t, 1, result.BooksMoved)
+	assert.EqualValues(t, 1, result.ImprintsMoved)
+
+	var stored models.Book
+	database.DB.First(&stored, "id = ?", book.ID)
+	require.NotNil(t, stored.PublisherID)
+	assert.Equal(t, target.ID, *stored.PublisherID)
+	assert.Equal(t, target.Name, stored.Publisher)
+
+	var imprint models.Publisher
+	database.DB.First(&imprint, "id = ?", dupeImprint.ID)
+	assert.Equal(t, target.ID, *imprint.ParentID)
+	assert.Equal(t, http.StatusNotFound, doRequest(t, "GET", "/publishers/"+dupe.ID.String()).Code)
+}
+
+func TestMigrateBackfillsPublishers(t *testing.T) {
+	setupTestDB()
+	suffix := uuid.NewString()[:8]
+	a := models.Book{Title: "One", Author: "X", Publisher: "Addison-Wesley " + suffix}
+	b := models.Book{Title: "Two", Author: "Y", Publisher: "  addison-wesley " + suffix}
+	require.NoError(t, database.DB.Create(&a).Error)
+	require.NoError(t, database.DB.Create(&b).Error)
+
+	// eski şemayı taklit et: yayınevi tablosu yok
+	require.NoError(t, database.DB.Migrator().DropTable(&models.Publisher{}))
+	database.DB.Model(&models.Book{}).Where("publisher_id IS NOT NULL").Update("publisher_id", nil)
+	require.NoError(t, database.Migrate(database.DB))
+
+	var stored []models.Book
+	database.DB.Find(&stored, "id IN ?", []uuid.UUID{a.ID, b.ID})
+	require.Len(t, stored, 2)
+	require.NotNil(t, stored[0].PublisherID)
+	assert.Equal(t, stored[0].PublisherID, stored[1].PublisherID, "case-insensitive grouping")
+}
@@ -0,0 +1,177 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/handlers"
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// asMember – isteği X-Member-ID başlığıyla gönderir
+func asMember(t *testing.T, member uuid.UUID, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	headers := map[string]string{"Content-Type": "application/json"}
+	if member != uuid.Nil {
+		headers["X-Member-ID"] = member.String()
+	}
+	return sendWithHeaders(t, method, path, body, headers)
+}
+
+func listTitles(t *testing.T, rec *httptest.ResponseRecorder) []string {
+	t.Helper()
+	require.Less(t, rec.Code, 300, rec.Body.String())
+	var detail handlers.ReadingListDetail
+	parseEnvelope(t, rec.Body.Bytes(), &detail)
+	titles := make([]string, len(detail.Items))
+	for i, item := range detail.Items {
+		assert.Equal(t, i+1, item.Position)
+		if item.Book != nil {
+			titles[i] = item.Book.Title
+		} else {
+			assert.True(t, item.BookDeleted)
+		}
+	}
+	return titles
+}
+
+func TestReadingListItemsAndOrder(t *testing.T) {
+	setupTestDB()
+	owner := createMember(t, "Ada")
+	books := make([]models.Book, 3)
+	for i, title := range []string{"Dune", "Emma", "Ulysses"} {
+		books[i] = models.Book{Title: title, Author: "Various"}
+		require.NoError(t, database.DB.Create(&books[i]).Error)
+	}
+
+	rec := sendJSON(t, "POST", "/lists", "application/json",
+		fmt.Sprintf(`{"member_id": %q, "name": "  Summer 2026 ", "public": true}`, owner.ID))
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var list models.ReadingList
+	parseEnvelope(t, rec.Body.Bytes(), &list)
+	assert.Equal(t, "Summer 2026", list.Name)
+	path := "/lists/" + list.ID.String()
+
+	// sahibi olmayan değiştiremez
+	rec = asMember(t, uuid.Nil, "POST", path+"/items", fmt.Sprintf(`{"book_id": %q}`, books[0].ID))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	for _, b := range books[:2] {
+		rec = asMember(t, owner.ID, "POST", path+"/items", fmt.Sprintf(`{"book_id": %q}`, b.ID))
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	}
+	// araya ekleme sonrakileri kaydırır
+	rec = asMember(t, owner.ID, "POST", path+"/items", fmt.Sprintf(`{"book_id": %q, "position": 1, "note": "önce bu"}`, books[2].ID))
+	assert.Equal(t, []string{"Ulysses", "Dune", "Emma"}, listTitles(t, rec))
+	rec = asMember(t, owner.ID, "POST", path+"/items", fmt.Sprintf(`{"book_id": %q}`, books[0].ID))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	rec = asMember(t, owner.ID, "PUT", path+"/items",
+		fmt.Sprintf(`{"book_ids": [%q, %q, %q]}`, books[1].ID, books[0].ID, books[2].ID))
+	assert.Equal(t, []string{"Emma", "Dune", "Ulysses"}, listTitles(t, rec))
+	rec = asMember(t, owner.ID, "PUT", path+"/items", fmt.Sprintf(`{"book_ids": [%q, %q]}`, books[1].ID, books[0].ID))
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	rec = asMember(t, owner.ID, "DELETE", path+"/items/"+books[1].ID.String(), "")
+	assert.Equal(t, []string{"Dune", "Ulysses"}, listTitles(t, rec))
+	rec = asMember(t, owner.ID, "DELETE", path+"/items/"+books[1].ID.String(), "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	// silinen kitap listede yer tutar, kitap verisi boş gelir
+	require.Equal(t, http.StatusOK, doRequest(t, "DELETE", "/books/"+books[0].ID.String()).Code)
+	rec = doRequest(t, "GET", path)
+	assert.Equal(t, []string{"", "Ulysses"}, listTitles(t, rec))
+	var detail handlers.ReadingListDetail
+	parseEnvelope(t, rec.Body.Bytes(), &detail)
+	assert.EqualValues(t, 2, detail.ItemCount)
+	assert.Equal(t, books[0].ID, detail.Items[0].BookID)
+
+	rec = asMember(t, owner.ID, "DELETE", path, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var left int64
+	database.DB.Model(&models.ReadingListItem{}).Where("list_id = ?", list.ID).Count(&left)
+	assert.Zero(t, left)
+}
+
+func TestPrivateReadingListsAreHidden(t *testing.T) {
+	setupTestDB()
+	owner, other := createMember(t, "Owner"), createMember(t, "Other")
+	rec := sendJSON(t, "POST", "/lists", "application/json",
+		fmt.Sprintf(`{"member_id": %q, "name": "Onboarding reading"}`, owner.ID))
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var list models.ReadingList
+	parseEnvelope(t, rec.Body.Bytes(), &list)
+	assert.False(t, list.Public)
+	path := "/lists/" + list.ID.String()
+
+	assert.Equal(t, http.StatusNotFound, asMember(t, uuid.Nil, "GET", path, "").Code)
+	assert.Equal(t, http.StatusNotFound, asMember(t, other.ID, "GET", path, "").Code)
+	assert.Equal(t, http.StatusOK, asMember(t, owner.ID, "GET", path, "").Code)
+
+	count := func(viewer uuid.UUID) int64 {
+		rec := asMember(t, viewer, "GET", "/lists?member_id="+owner.ID.String(), "")
+		require.Equal(t, http.StatusOK, rec.Code)
+		var lists []models.ReadingList
+		parseEnvelope(t, rec.Body.Bytes(), &lists)
+		return int64(len(lists))
+	}
+	assert.Zero(t, count(other.ID))
+	assert.EqualValues(t, 1, count(owner.ID))
+
+	rec = asMember(t, owner.ID, "PUT", path, `{"name": "Onboarding reading", "public": true}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.EqualValues(t, 1, count(other.ID))
+
+	rec = sendJSON(t, "POST", "/lists", "application/json", `{"name": ""}`)
+	require.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	fields := fieldErrors(t, rec)
+	assert.Equal(t, "is required", fields["name"])
+	assert.Equal(t, "is required", fields["member_id"])
+
+	// üye silinince listeleri de gider
+	require.Equal(t, http.StatusOK, doRequest(t, "DELETE", "/members/"+owner.ID.String()).Code)
+	assert.Equal(t, http.StatusNotFound, doRequest(t, "GET", path).Code)
+}
+
+func TestConcurrentListAppendsGetDistinctPositions(t *testing.T) {
+	setupTestDB()
+	owner := createMember(t, "Racing Reader")
+	rec := sendJSON(t, "POST", "/lists", "application/json",
+		fmt.Sprintf(`{"member_id": %q, "name": "Race"}`, owner.ID))
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var list models.ReadingList
+	parseEnvelope(t, rec.Body.Bytes(), &list)
+	path := "/lists/" + list.ID.String()
+
+	books := make([]models.Book, 5)
+	for i := range books {
+		books[i] = models.Book{Title: fmt.Sprintf("Race %d", i), Author: "Various"}
+		require.NoError(t, database.DB.Create(&books[i]).Error)
+	}
+
+	var wg sync.WaitGroup
+	codes := make([]int, len(books))
+	for i := range books {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			codes[i] = asMember(t, owner.ID, "POST", path+"/items", fmt.Sprintf(`{"book_id": %q}`, books[i].ID)).Code
+		}(i)
+	}
+	wg.Wait()
+
+	// her eklenen kitap ayrı bir sıra alır: 1..n
+	var positions []int
+	database.DB.Model(&models.ReadingListItem{}).Where("list_id = ?", list.ID).Order("position").Pluck("position", &positions)
+	for i, p := range positions {
+		assert.Equal(t, i+1, p, "codes: %v", codes)
+	}
+	assert.Contains(t, codes, http.StatusCreated)
+}
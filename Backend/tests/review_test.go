@@ -0,0 +1,142 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func postReview(t *testing.T, bookID, memberID uuid.UUID, rating int) (models.Review, int) {
+	t.Helper()
+	rec := sendJSON(t, "POST", "/books/"+bookID.String()+"/reviews", "application/json",
+		fmt.Sprintf(`{"member_id": %q, "rating": %d, "text": "  okudum  "}`, memberID, rating))
+	var review models.Review
+	if rec.Code == http.StatusCreated {
+		parseEnvelope(t, rec.Body.Bytes(), &review)
+	}
+	return review, rec.Code
+}
+
+func bookRating(t *testing.T, bookID uuid.UUID) models.BookRating {
+	t.Helper()
+	rec := doRequest(t, "GET", "/books/"+bookID.String())
+	require.Equal(t, http.StatusOK, rec.Code)
+	var book models.Book
+	parseEnvelope(t, rec.Body.Bytes(), &book)
+	require.NotNil(t, book.Rating)
+	return *book.Rating
+}
+
+func TestReviewsMaintainRatingTotals(t *testing.T) {
+	setupTestDB()
+	book := models.Book{Title: "Gödel, Escher, Bach", Author: "Douglas Hofstadter"}
+	require.NoError(t, database.DB.Create(&book).Error)
+	ada, bob := createMember(t, "Ada"), createMember(t, "Bob")
+
+	assert.Equal(t, models.BookRating{}, bookRating(t, book.ID))
+
+	first, code := postReview(t, book.ID, ada.ID, 5)
+	require.Equal(t, http.StatusCreated, code)
+	assert.Equal(t, "okudum", first.Text)
+	assert.Equal(t, "Ada", first.MemberName)
+	_, code = postReview(t, book.ID, ada.ID, 3)
+	assert.Equal(t, http.StatusConflict, code, "one review per member and book")
+	_, code = postReview(t, book.ID, bob.ID, 2)
+	require.Equal(t, http.StatusCreated, code)
+
+	r := bookRating(t, book.ID)
+	assert.EqualValues(t, 2, r.Count)
+	assert.InDelta(t, 3.5, r.Average, 1e-9)
+
+	path := "/books/" + book.ID.String() + "/reviews/" + first.ID.String()
+	rec := sendJSON(t, "PUT", path, "application/json", `{"rating": 3, "text": "ikinci okuma"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.InDelta(t, 2.5, bookRating(t, book.ID).Average, 1e-9)
+
+	require.Equal(t, http.StatusOK, doRequest(t, "DELETE", path).Code)
+	assert.Equal(t, http.StatusNotFound, doRequest(t, "GET", path).Code)
+	r = bookRating(t, book.ID)
+	assert.EqualValues(t, 1, r.Count)
+	assert.InDelta(t, 2.0, r.Average, 1e-9)
+
+	rec = doRequest(t, "GET", "/books/"+book.ID.String()+"/reviews")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var page struct {
+		Data []models.Review `json:"data"`
+		Meta pageMeta        `json:"meta"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+	require.Len(t, page.Data, 1)
+	assert.EqualValues(t, 1, page.Meta.Total)
+	assert.Equal(t, http.StatusBadRequest, doRequest(t, "GET", "/books/"+book.ID.String()+"/reviews?sort=text").Code)
+
+	// toplamlar yorumlardan yeniden hesaplananla aynı kalır
+	var stored models.BookRating
+	require.NoError(t, database.DB.First(&stored, "book_id = ?", book.ID).Error)
+	var live struct{ N, S int64 }
+	database.DB.Model(&models.Review{}).Select("COUNT(*) AS n, COALESCE(SUM(rating), 0) AS s").
+		Where("book_id = ?", book.ID).Scan(&live)
+	assert.Equal(t, live.N, stored.Count)
+	assert.Equal(t, live.S, stored.Sum)
+}
+
+func TestReviewValidation(t *testing.T) {
+	setupTestDB()
+	book := models.Book{Title: "The Mythical Man-Month", Author: "Fred Brooks"}
+	require.NoError(t, database.DB.Create(&book).Error)
+
+	rec := sendJSON(t, "POST", "/books/"+book.ID.String()+"/reviews", "application/json",
+		fmt.Sprintf(`{"member_id": %q, "rating": 6}`, uuid.New()))
+	require.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	fields := fieldErrors(t, rec)
+	assert.Equal(t, "must be less than or equal to 5", fields["rating"])
+	assert.Equal(t, "member not found", fields["member_id"])
+
+	rec = sendJSON(t, "POST", "/books/"+book.ID.String()+"/reviews", "application/json", `{"text": "?"}`)
+	fields = fieldErrors(t, rec)
+	assert.Equal(t, "is required", fields["rating"])
+	assert.Equal(t, "is required", fields["member_id"])
+
+	assert.Equal(t, http.StatusBadRequest, doRequest(t, "GET", "/books?min_rating=six").Code)
+	assert.Equal(t, http.StatusBadRequest, doRequest(t, "GET", "/books?min_rating=7").Code)
+}
+
+func TestMinRatingFilterAndRatingSort(t *testing.T) {
+	setupTestDB()
+	member := createMember(t, "Critic")
+	tag := uuid.NewString()[:8]
+	stars := map[string]int{"Great": 5, "Good": 4, "Poor": 2}
+	for title, n := range stars {
+		book := models.Book{Title: title + " " + tag, Author: "Rated"}
+		require.NoError(t, database.DB.Create(&book).Error)
+		_, code := postReview(t, book.ID, member.ID, n)
+		require.Equal(t, http.StatusCreated, code)
+	}
+	unrated := models.Book{Title: "Unrated " + tag, Author: "Rated"}
+	require.NoError(t, database.DB.Create(&unrated).Error)
+
+	books, _, code := getBookPage(t, url.Values{"title": {tag}, "min_rating": {"4"}})
+	require.Equal(t, http.StatusOK, code)
+	assert.Len(t, books, 2)
+
+	// sıralama imleçle sayfalandığında da korunur
+	books, meta, _ := getBookPage(t, url.Values{"title": {tag}, "sort": {"-rating"}, "limit": {"2"}})
+	require.Len(t, books, 2)
+	assert.Equal(t, "Great "+tag, books[0].Title)
+	assert.Equal(t, "Good "+tag, books[1].Title)
+	require.NotEmpty(t, meta.NextCursor)
+	books, _, _ = getBookPage(t, url.Values{"title": {tag}, "sort": {"-rating"}, "limit": {"2"}, "cursor": {meta.NextCursor}})
+	require.Len(t, books, 2)
+	assert.Equal(t, "Poor "+tag, books[0].Title)
+	assert.Equal(t, unrated.ID, books[1].ID)
+	require.NotNil(t, books[1].Rating)
+	assert.Zero(t, books[1].Rating.Count)
+}
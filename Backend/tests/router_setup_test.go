@@ -2,7 +2,7 @@ package tests
 
 import (
 	"github.com/gin-gonic/gin"
-	"github.com/hasan-kayan/TaskGo/handlers"
+	"github.com/hasan-kayan/TaskGo/routes"
 )
 
 var isInitialized = false
@@ -13,12 +13,8 @@ func testRouter() *gin.Engine {
 	gin.SetMode(gin.TestMode)
 
 	r := gin.New()
-	// Routes
-	r.GET("/books", handlers.GetBooks)
-	r.GET("/books/:id", handlers.GetBook)
-	r.POST("/books", handlers.CreateBook)
-	r.PUT("/books/:id", handlers.UpdateBook)
-	r.DELETE("/books/:id", handlers.DeleteBook)
+	// Routes – same table as production, so new endpoints are covered too
+	routes.SetupRoutes(r)
 	r.GET("/ping", func(c *gin.Context) { c.String(200, "pong") })
 
 	return r
@@ -0,0 +1,147 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeriesVolumesInOrder(t *testing.T) {
+	setupTestDB()
+	rec := sendJSON(t, "POST", "/series", "application/json", `{"name": "The Expanse"}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var series models.Series
+	parseEnvelope(t, rec.Body.Bytes(), &series)
+
+	// ciltleri karışık sırayla ekle; 2.5 bir ara hikâye
+	for _, v := range []struct {
+		title  string
+		volume string
+	}{{"Caliban's War", "2"}, {"Companion", "null"}, {"Leviathan Wakes", "1"}, {"Gods of Risk", "2.5"}} {
+		rec := sendJSON(t, "POST", "/books", "application/json", fmt.Sprintf(
+			`{"title": %q, "author": "James S. A. Corey", "series_id": %q, "volume": %s}`, v.title, series.ID, v.volume))
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	}
+
+	rec = doRequest(t, "GET", "/series/"+series.ID.String())
+	require.Equal(t, http.StatusOK, rec.Code)
+	var detail struct {
+		BookCount int64         `json:"book_count"`
+		Volumes   []models.Book `json:"volumes"`
+	}
+	parseEnvelope(t, rec.Body.Bytes(), &detail)
+	var titles []string
+	for _, b := range detail.Volumes {
+		titles = append(titles, b.Title)
+	}
+	assert.Equal(t, []string{"Leviathan Wakes", "Caliban's War", "Gods of Risk", "Companion"}, titles)
+	assert.EqualValues(t, 4, detail.BookCount)
+
+	// GET /books filtresi ve -volume sıralaması, imleçle iki sayfa
+	q := url.Values{"series": {series.ID.String()}, "sort": {"-volume"}, "limit": {"2"}}
+	books, meta, code := getBookPage(t, q)
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, books, 2)
+	assert.Equal(t, "Gods of Risk", books[0].Title)
+	require.NotEmpty(t, meta.NextCursor)
+	q.Set("cursor", meta.NextCursor)
+	books, _, _ = getBookPage(t, q)
+	require.Len(t, books, 2)
+	assert.Equal(t, "Leviathan Wakes", books[0].Title)
+	assert.Equal(t, "Companion", books[1].Title)
+
+	assert.Equal(t, http.StatusConflict, doRequest(t, "DELETE", "/series/"+series.ID.String()).Code)
+}
+
+func TestBookSeriesValidation(t *testing.T) {
+	setupTestDB()
+	rec := sendJSON(t, "POST", "/books", "application/json",
+		`{"title": "Lost", "author": "Nobody", "volume": 3}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Equal(t, "requires series_id", fieldErrors(t, rec)["volume"])
+
+	rec = sendJSON(t, "POST", "/books", "application/json", fmt.Sprintf(
+		`{"title": "Lost", "author": "Nobody", "series_id": %q, "volume": 1}`, uuid.New()))
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Equal(t, "series not found", fieldErrors(t, rec)["series_id"])
+
+	// PATCH ile seriye alma / seriden çıkarma
+	series := models.Series{Name: "Dune Chronicles"}
+	require.NoError(t, database.DB.Create(&series).Error)
+	book := models.Book{Title: "Dune Messiah", Author: "Frank Herbert"}
+	require.NoError(t, database.DB.Create(&book).Error)
+	rec = sendJSON(t, "PATCH", "/books/"+book.ID.String(), "application/merge-patch+json",
+		fmt.Sprintf(`{"series_id": %q, "volume": 2}`, series.ID))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var stored models.Book
+	database.DB.First(&stored, "id = ?", book.ID)
+	require.NotNil(t, stored.Volume)
+	assert.Equal(t, 2.0, *stored.Volume)
+
+	rec = sendJSON(t, "PATCH", "/books/"+book.ID.String(), "application/merge-patch+json",
+		`{"series_id": null, "volume": null}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, http.StatusOK, doRequest(t, "DELETE", "/series/"+series.ID.String()).Code)
+}
+
+func TestSortBySeriesPagesWithCursor(t *testing.T) {
+	setupTestDB()
+	kind := "SeriesSort-" + uuid.NewString()
+	a := models.Series{Name: "A " + kind}
+	b := models.Series{Name: "B " + kind}
+	require.NoError(t, database.DB.Create(&a).Error)
+	require.NoError(t, database.DB.Create(&b).Error)
+	one, two := 1.0, 2.0
+	seed := []models.Book{
+		{Title: "Loose 1", Author: "X", Type: kind},
+		{Title: "A1", Author: "X", Type: kind, SeriesID: &a.ID, Volume: &one},
+		{Title: "B1", Author: "X", Type: kind, SeriesID: &b.ID, Volume: &one},
+		{Title: "Loose 2", Author: "X", Type: kind},
+		{Title: "A2", Author: "X", Type: kind, SeriesID: &a.ID, Volume: &two},
+	}
+	for i := range seed {
+		require.NoError(t, database.DB.Create(&seed[i]).Error)
+	}
+
+	// seriler gruplanır, seri dışı kitaplar sona düşer; imleçle sayfalar çakışmaz
+	for _, sort := range []string{"series,volume", "-series,volume"} {
+		q := url.Values{"type": {kind}, "sort": {sort}, "limit": {"2"}}
+		var seen []models.Book
+		for {
+			books, meta, code := getBookPage(t, q)
+			require.Equal(t, http.StatusOK, code, sort)
+			seen = append(seen, books...)
+			if meta.NextCursor == "" {
+				break
+			}
+			q.Set("cursor", meta.NextCursor)
+		}
+		require.Len(t, seen, len(seed), sort)
+		loose := 0
+		for i, book := range seen {
+			if book.SeriesID == nil {
+				loose++
+				continue
+			}
+			if sort == "series,volume" {
+				assert.Zero(t, loose, "a series book after a loose one: %d", i)
+			}
+		}
+		if sort == "-series,volume" {
+			assert.Nil(t, seen[0].SeriesID)
+			assert.Nil(t, seen[1].SeriesID)
+		}
+		for i := 1; i < len(seen); i++ {
+			if seen[i].SeriesID != nil && seen[i-1].SeriesID != nil && *seen[i].SeriesID == *seen[i-1].SeriesID {
+				assert.Less(t, *seen[i-1].Volume, *seen[i].Volume)
+			}
+		}
+	}
+}
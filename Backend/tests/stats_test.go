@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/handlers"
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func getStats(t *testing.T, path string, target any) {
+	t.Helper()
+	rec := doRequest(t, "GET", path)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	parseEnvelope(t, rec.Body.Bytes(), target)
+}
+
+func TestStatsReports(t *testing.T) {
+	setupTestDB()
+	kind := "Stats-" + uuid.NewString()[:8]
+	seed := []models.Book{
+		{Title: "A", Author: "X", Type: kind, Year: 2001, Pages: 100},
+		{Title: "B", Author: "Y", Type: kind, Year: 2001, Pages: 300},
+		{Title: "C", Author: "Z", Type: kind},
+	}
+	for i := range seed {
+		require.NoError(t, database.DB.Create(&seed[i]).Error)
+	}
+
+	var overview handlers.StatsOverview
+	getStats(t, "/stats", &overview)
+	assert.GreaterOrEqual(t, overview.Books, int64(3))
+	assert.GreaterOrEqual(t, overview.BooksWithPages, int64(2))
+	assert.Greater(t, overview.AveragePages, 0.0)
+
+	var types handlers.StatDistribution
+	getStats(t, "/stats/types", &types)
+	assert.Contains(t, types.Buckets, handlers.StatCount{Key: kind, Count: 3})
+
+	var years handlers.StatDistribution
+	getStats(t, "/stats/years", &years)
+	assert.Equal(t, years.Total, overview.Books)
+	assert.GreaterOrEqual(t, years.Unknown, int64(1))
+
+	// bu ay eklenenler son kovada
+	var added []handlers.StatCount
+	getStats(t, "/stats/added?months=3", &added)
+	require.Len(t, added, 3)
+	assert.Equal(t, time.Now().Format("2006-01"), added[2].Key)
+	assert.GreaterOrEqual(t, added[2].Count, int64(3))
+
+	var complete handlers.StatsCompleteness
+	getStats(t, "/stats/completeness", &complete)
+	assert.Equal(t, overview.Books, complete.Books)
+	for _, f := range complete.Fields {
+		if f.Field == "year" {
+			assert.Equal(t, years.Unknown, f.Missing)
+		}
+		assert.True(t, f.Complete >= 0 && f.Complete <= 100, f.Field)
+	}
+
+	var authors []handlers.AuthorStat
+	getStats(t, "/stats/authors?limit=2", &authors)
+	assert.LessOrEqual(t, len(authors), 2)
+
+	assert.Equal(t, http.StatusBadRequest, doRequest(t, "GET", "/stats/added?months=0").Code)
+	assert.Equal(t, http.StatusBadRequest, doRequest(t, "GET", "/stats/publishers?limit=x").Code)
+}
+
+func TestStatsAreCacheable(t *testing.T) {
+	setupTestDB()
+	rec := doRequest(t, "GET", "/stats/types")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get("Cache-Control"), "max-age=")
+	etag := rec.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	rec = sendWithHeaders(t, "GET", "/stats/types", "", map[string]string{"If-None-Match": etag})
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+}
@@ -0,0 +1,152 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hasan-kayan/TaskGo/database"
+	"github.com/hasan-kayan/TaskGo/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createTag – POST /tags, parent boş olabilir
+func createTag(t *testing.T, name string, parent *uuid.UUID) models.Tag {
+	t.Helper()
+	body := fmt.Sprintf(`{"name": %q}`, name)
+	if parent != nil {
+		body = fmt.Sprintf(`{"name": %q, "parent_id": %q}`, name, parent)
+	}
+	rec := sendJSON(t, "POST", "/tags", "application/json", body)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var tag models.Tag
+	parseEnvelope(t, rec.Body.Bytes(), &tag)
+	return tag
+}
+
+func setBookTags(t *testing.T, book models.Book, tags ...string) {
+	t.Helper()
+	body := "["
+	for i, tag := range tags {
+		if i > 0 {
+			body += ","
+		}
+		body += fmt.Sprintf("%q", tag)
+	}
+	rec := sendJSON(t, "PUT", "/books/"+book.ID.String()+"/tags", "application/json", body+"]")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+}
+
+func TestTagFilterAnyAllAndHierarchy(t *testing.T) {
+	setupTestDB()
+	suffix := uuid.NewString()[:8]
+	kind := "TagFilter-" + uuid.NewString()
+	programming := createTag(t, "Programming "+suffix, nil)
+	golang := createTag(t, "Go "+suffix, &programming.ID)
+	architecture := createTag(t, "Architecture "+suffix, nil)
+	assert.Equal(t, "programming-"+suffix, programming.Slug)
+
+	both := models.Book{Title: "Both", Author: "A", Type: kind}
+	goOnly := models.Book{Title: "Go only", Author: "B", Type: kind}
+	archOnly := models.Book{Title: "Arch only", Author: "C", Type: kind}
+	for _, b := range []*models.Book{&both, &goOnly, &archOnly} {
+		require.NoError(t, database.DB.Create(b).Error)
+	}
+	setBookTags(t, both, programming.Name, architecture.Slug)
+	setBookTags(t, goOnly, golang.Slug)
+	setBookTags(t, archOnly, architecture.Slug)
+
+	titles := func(q url.Values) []string {
+		q.Set("type", kind)
+		q.Set("sort", "title")
+		books, _, code := getBookPage(t, q)
+		require.Equal(t, http.StatusOK, code)
+		out := []string{}
+		for _, b := range books {
+			out = append(out, b.Title)
+		}
+		return out
+	}
+
+	// üst etiket alt etiketleri de kapsar
+	assert.Equal(t, []string{"Both", "Go only"}, titles(url.Values{"tags": {programming.Slug}}))
+	assert.Equal(t, []string{"Arch only", "Both", "Go only"},
+		titles(url.Values{"tags": {programming.Slug + "," + architecture.Slug}}))
+	assert.Equal(t, []string{"Both"},
+		titles(url.Values{"tags": {programming.Slug + "," + architecture.Slug}, "tag_mode": {"all"}}))
+
+	_, _, code := getBookPage(t, url.Values{"tags": {programming.Slug}, "tag_mode": {"al"}})
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// kullanım sayıları
+	rec := doRequest(t, "GET", "/tags?sort=-usage&name="+suffix)
+	var listed []models.Tag
+	parseEnvelope(t, rec.Body.Bytes(), &listed)
+	require.Len(t, listed, 3)
+	assert.Equal(t, architecture.ID, listed[0].ID)
+	assert.EqualValues(t, 2, listed[0].UsageCount)
+
+	// bilinmeyen etiket → 422, aynı isim → 409, döngü → 422
+	rec = sendJSON(t, "PUT", "/books/"+both.ID.String()+"/tags", "application/json", `["no-such-tag-`+suffix+`"]`)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	rec = sendJSON(t, "POST", "/tags", "application/json", fmt.Sprintf(`{"name": "programming %s"}`, suffix))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	rec = sendJSON(t, "PUT", "/tags/"+programming.ID.String(), "application/json",
+		fmt.Sprintf(`{"name": %q, "parent_id": %q}`, programming.Name, golang.ID))
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	// silme: kitaplardan kalkar, alt etiket bir üst seviyeye çıkar
+	require.Equal(t, http.StatusOK, doRequest(t, "DELETE", "/tags/"+programming.ID.String()).Code)
+	var moved models.Tag
+	database.DB.First(&moved, "id = ?", golang.ID)
+	assert.Nil(t, moved.ParentID)
+	assert.Equal(t, []string{"Arch only", "Both"}, titles(url.Values{"tags": {architecture.Slug}}))
+}
+
+func TestCreateBookTagsItsType(t *testing.T) {
+	setupTestDB()
+	kind := "Science Fiction " + uuid.NewString()[:8]
+	book := models.Book{Title: "Dune", Author: "Frank Herbert", Type: kind}
+	require.NoError(t, database.DB.Create(&book).Error)
+
+	rec := doRequest(t, "GET", "/books/"+book.ID.String()+"/tags")
+	var tags []models.Tag
+	parseEnvelope(t, rec.Body.Bytes(), &tags)
+	require.Len(t, tags, 1)
+	assert.Equal(t, models.TagSlug(kind), tags[0].Slug)
+
+	// eski şemayı taklit et: bağlantı tablosu yok → göç yeniden etiketler
+	require.NoError(t, database.DB.Migrator().DropTable(&models.BookTag{}))
+	require.NoError(t, database.Migrate(database.DB))
+	listed, _, _ := getBookPage(t, url.Values{"tags": {kind}})
+	require.Len(t, listed, 1)
+	assert.Equal(t, book.ID, listed[0].ID)
+}
+
+func TestBookUpdateMovesTypeTag(t *testing.T) {
+	setupTestDB()
+	suffix := uuid.NewString()[:8]
+	fiction, fantasy := "Fiction "+suffix, "Fantasy "+suffix
+	classic := createTag(t, "Classic "+suffix, nil)
+	book := models.Book{Title: "The Hobbit", Author: "J. R. R. Tolkien", Type: fiction}
+	require.NoError(t, database.DB.Create(&book).Error)
+	setBookTags(t, book, models.TagSlug(fiction), classic.Slug)
+
+	rec := sendJSON(t, "PATCH", "/books/"+book.ID.String(), "application/merge-patch+json",
+		fmt.Sprintf(`{"type": %q}`, fantasy))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var tags []models.Tag
+	parseEnvelope(t, doRequest(t, "GET", "/books/"+book.ID.String()+"/tags").Body.Bytes(), &tags)
+	slugs := []string{}
+	for _, tag := range tags {
+		slugs = append(slugs, tag.Slug)
+	}
+	assert.ElementsMatch(t, []string{models.TagSlug(fantasy), classic.Slug}, slugs)
+
+	listed, _, _ := getBookPage(t, url.Values{"tags": {models.TagSlug(fiction)}})
+	assert.Empty(t, listed, "the old type tag is gone")
+}
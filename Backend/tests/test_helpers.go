@@ -3,22 +3,26 @@ package tests
 
 import (
 	"github.com/hasan-kayan/TaskGo/database"
-	"github.com/hasan-kayan/TaskGo/models"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
 func setupTestDB() {
-	if database.DB != nil {
-		return // zaten hazır
+	if database.DB == nil {
+		db, err := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{})
+		if err != nil {
+			panic("❌ TEST DB açılamadı: " + err.Error())
+		}
+		// global DB’yi atayın
+		database.DB = db
 	}
-	db, err := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{})
-	if err != nil {
-		panic("❌ TEST DB açılamadı: " + err.Error())
+	// revizyon geçmişi ve türetilen kayıtlar – production'daki ConnectDB ile aynı
+	if err := database.RegisterCallbacks(database.DB); err != nil {
+		panic("❌ TEST DB callback kaydı başarısız: " + err.Error())
 	}
-	// global DB’yi atayın
-	database.DB = db
 
-	// şema
-	_ = db.AutoMigrate(&models.Book{})
+	// şema – idempotent; bazı testler tabloları düşürüp yeniden kuruyor
+	if err := database.Migrate(database.DB); err != nil {
+		panic("❌ TEST DB migrate edilemedi: " + err.Error())
+	}
 }
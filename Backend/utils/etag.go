@@ -0,0 +1,31 @@
+package utils
+
+import "strings"
+
+// ETagMatches reports whether an If-Match / If-None-Match header value
+// names etag. "*" matches anything. weak selects the weak comparison
+// function (RFC 9110 §8.8.3.2) used by If-None-Match; If-Match must use
+// strong comparison, where W/ tags never match.
+func ETagMatches(header, etag string, weak bool) bool {
+	header = strings.TrimSpace(header)
+	if header == "*" {
+		return true
+	}
+
+	want := etag
+	if weak {
+		want = strings.TrimPrefix(etag, "W/")
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if weak {
+			candidate = strings.TrimPrefix(candidate, "W/")
+		} else if strings.HasPrefix(candidate, "W/") {
+			continue
+		}
+		if candidate == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,324 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+/*───────────────────────────────────────────────────────────────*
+|               Filter expressions (?filter=)                   |
+*───────────────────────────────────────────────────────────────*/
+
+// The grammar, loosest binding first:
+//
+//	expr    = and { "OR" and }
+//	and     = unary { "AND" unary }
+//	unary   = "NOT" unary | "(" expr ")" | compare
+//	compare = field op value
+//	op      = "=" | "!=" | "<>" | "<" | "<=" | ">" | ">=" | "~" | "!~"
+//	value   = "quoted string" | number | bare-word | null
+//
+// Keywords are case-insensitive. ParseFilter only checks the syntax;
+// which fields exist and what values they take is up to the caller.
+
+const (
+	// MaxFilterLength caps the source of a filter, in characters.
+	MaxFilterLength = 2000
+	maxFilterDepth  = 32
+)
+
+// FilterNode is a node of a parsed filter: *FilterLogic, *FilterNot or
+// *FilterCompare.
+type FilterNode interface{ filterNode() }
+
+// FilterLogic joins two expressions with AND or OR.
+type FilterLogic struct {
+	Op          string // "AND" or "OR"
+	Left, Right FilterNode
+}
+
+// FilterNot negates an expression.
+type FilterNot struct {
+	Expr FilterNode
+}
+
+// FilterCompare is a single `field op value` test. Positions are
+// 1-based character offsets into the source, for error messages.
+type FilterCompare struct {
+	Field    string
+	FieldPos int
+	Op       string // "<>" is read as "!="
+	OpPos    int
+	Value    FilterValue
+}
+
+func (*FilterLogic) filterNode()   {}
+func (*FilterNot) filterNode()     {}
+func (*FilterCompare) filterNode() {}
+
+// FilterValueKind tells how a value was written.
+type FilterValueKind int
+
+const (
+	FilterString FilterValueKind = iota // "quoted" or a bare word
+	FilterNumber
+	FilterNull
+)
+
+// FilterValue is the right-hand side of a comparison. Text is unquoted
+// for strings and the source digits for numbers.
+type FilterValue struct {
+	Kind FilterValueKind
+	Text string
+	Pos  int
+}
+
+// FilterError reports where a filter stopped making sense.
+type FilterError struct {
+	Pos int
+	Msg string
+}
+
+func (e *FilterError) Error() string {
+	return fmt.Sprintf("invalid filter at position %d: %s", e.Pos, e.Msg)
+}
+
+// ParseFilter parses src into an expression tree.
+func ParseFilter(src string) (FilterNode, error) {
+	if n := utf8.RuneCountInString(src); n > MaxFilterLength {
+		return nil, &FilterError{Pos: MaxFilterLength + 1, Msg: fmt.Sprintf("filter is longer than %d characters", MaxFilterLength)}
+	}
+	toks, err := lexFilter(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &filterParser{toks: toks}
+	node, err := p.or(0)
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, &FilterError{Pos: t.pos, Msg: "expected AND, OR or end of filter, got " + t.describe()}
+	}
+	return node, nil
+}
+
+/* ───────────── lexer ───────────── */
+
+type filterTokenKind int
+
+const (
+	tokEOF filterTokenKind = iota
+	tokWord
+	tokString
+	tokNumber
+	tokOp
+	tokLParen
+	tokRParen
+	tokAnd
+	tokOr
+	tokNot
+	tokNull
+)
+
+type filterToken struct {
+	kind filterTokenKind
+	text string
+	pos  int
+}
+
+func (t filterToken) describe() string {
+	if t.kind == tokEOF {
+		return "end of filter"
+	}
+	return strconv.Quote(t.text)
+}
+
+var filterKeywords = map[string]filterTokenKind{
+	"AND": tokAnd, "OR": tokOr, "NOT": tokNot, "NULL": tokNull,
+}
+
+func lexFilter(src string) ([]filterToken, error) {
+	rs := []rune(src)
+	var toks []filterToken
+	for i := 0; ; {
+		for i < len(rs) && unicode.IsSpace(rs[i]) {
+			i++
+		}
+		pos := i + 1
+		if i == len(rs) {
+			return append(toks, filterToken{kind: tokEOF, pos: pos}), nil
+		}
+
+		r := rs[i]
+		switch {
+		case r == '(':
+			toks = append(toks, filterToken{tokLParen, "(", pos})
+			i++
+		case r == ')':
+			toks = append(toks, filterToken{tokRParen, ")", pos})
+			i++
+		case r == '"':
+			var b strings.Builder
+			for i++; ; i++ {
+				if i == len(rs) {
+					return nil, &FilterError{Pos: pos, Msg: "unterminated string"}
+				}
+				if rs[i] == '"' {
+					i++
+					break
+				}
+				if rs[i] == '\\' && i+1 < len(rs) {
+					i++
+				}
+				b.WriteRune(rs[i])
+			}
+			toks = append(toks, filterToken{tokString, b.String(), pos})
+		case strings.ContainsRune("=!<>~", r):
+			op := string(r)
+			if i+1 < len(rs) {
+				switch two := string(rs[i : i+2]); two {
+				case "!=", "<>", "<=", ">=", "!~":
+					op = two
+				}
+			}
+			if op == "!" {
+				return nil, &FilterError{Pos: pos, Msg: `"!" must be followed by "=" or "~"`}
+			}
+			i += len(op)
+			if op == "<>" {
+				op = "!="
+			}
+			toks = append(toks, filterToken{tokOp, op, pos})
+		case r == '-' || r == '.' || unicode.IsDigit(r):
+			j := i + 1
+			for j < len(rs) && (rs[j] == '.' || unicode.IsDigit(rs[j])) {
+				j++
+			}
+			text := string(rs[i:j])
+			if _, err := strconv.ParseFloat(text, 64); err != nil {
+				return nil, &FilterError{Pos: pos, Msg: "malformed number " + strconv.Quote(text)}
+			}
+			toks = append(toks, filterToken{tokNumber, text, pos})
+			i = j
+		case r == '_' || unicode.IsLetter(r):
+			j := i + 1
+			for j < len(rs) && (rs[j] == '_' || unicode.IsLetter(rs[j]) || unicode.IsDigit(rs[j])) {
+				j++
+			}
+			text := string(rs[i:j])
+			kind, ok := filterKeywords[strings.ToUpper(text)]
+			if !ok {
+				kind = tokWord
+			}
+			toks = append(toks, filterToken{kind, text, pos})
+			i = j
+		default:
+			return nil, &FilterError{Pos: pos, Msg: "unexpected character " + strconv.QuoteRune(r)}
+		}
+	}
+}
+
+/* ───────────── parser ───────────── */
+
+type filterParser struct {
+	toks []filterToken
+	i    int
+}
+
+func (p *filterParser) peek() filterToken { return p.toks[p.i] }
+
+func (p *filterParser) take() filterToken {
+	t := p.toks[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+func (p *filterParser) or(depth int) (FilterNode, error) {
+	left, err := p.and(depth)
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokOr {
+		p.take()
+		right, err := p.and(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = &FilterLogic{Op: "OR", Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) and(depth int) (FilterNode, error) {
+	left, err := p.unary(depth)
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokAnd {
+		p.take()
+		right, err := p.unary(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = &FilterLogic{Op: "AND", Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) unary(depth int) (FilterNode, error) {
+	t := p.peek()
+	if depth > maxFilterDepth {
+		return nil, &FilterError{Pos: t.pos, Msg: fmt.Sprintf("filter nests deeper than %d levels", maxFilterDepth)}
+	}
+	switch t.kind {
+	case tokNot:
+		p.take()
+		expr, err := p.unary(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return &FilterNot{Expr: expr}, nil
+	case tokLParen:
+		p.take()
+		expr, err := p.or(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if c := p.take(); c.kind != tokRParen {
+			return nil, &FilterError{Pos: c.pos, Msg: "expected \")\", got " + c.describe()}
+		}
+		return expr, nil
+	}
+	return p.compare()
+}
+
+func (p *filterParser) compare() (FilterNode, error) {
+	field := p.take()
+	if field.kind != tokWord {
+		return nil, &FilterError{Pos: field.pos, Msg: "expected a field name, got " + field.describe()}
+	}
+	op := p.take()
+	if op.kind != tokOp {
+		return nil, &FilterError{Pos: op.pos, Msg: "expected an operator after " + strconv.Quote(field.text) + ", got " + op.describe()}
+	}
+
+	v := p.take()
+	value := FilterValue{Text: v.text, Pos: v.pos}
+	switch v.kind {
+	case tokString, tokWord:
+		value.Kind = FilterString
+	case tokNumber:
+		value.Kind = FilterNumber
+	case tokNull:
+		value.Kind = FilterNull
+	default:
+		return nil, &FilterError{Pos: v.pos, Msg: "expected a value after " + strconv.Quote(op.text) + ", got " + v.describe()}
+	}
+	return &FilterCompare{Field: field.text, FieldPos: field.pos, Op: op.text, OpPos: op.pos, Value: value}, nil
+}
@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+/*───────────────────────────────────────────────────────────────*
+|                    ISBN-10 / ISBN-13 helpers                  |
+*───────────────────────────────────────────────────────────────*/
+
+var ErrInvalidISBN = errors.New("invalid ISBN")
+
+// isbnSeparators may appear anywhere in user input and are dropped.
+var isbnSeparators = strings.NewReplacer("-", "", " ", "", "‐", "", "‑", "")
+
+// NormaliseISBN accepts an ISBN-10 or ISBN-13 in any common notation
+// ("978-0-13-235088-4", "0 13 235088 2") and returns the canonical
+// ISBN-13 plus the matching ISBN-10. The ISBN-10 is empty for 979-
+// prefixed numbers, which have no 10-digit form.
+func NormaliseISBN(raw string) (isbn13, isbn10 string, err error) {
+	s := strings.ToUpper(isbnSeparators.Replace(strings.TrimSpace(raw)))
+
+	switch len(s) {
+	case 10:
+		if !validISBN10(s) {
+			return "", "", ErrInvalidISBN
+		}
+		isbn13 = "978" + s[:9]
+		return isbn13 + isbn13CheckDigit(isbn13), s, nil
+	case 13:
+		if !validISBN13(s) {
+			return "", "", ErrInvalidISBN
+		}
+		return s, ISBN10From13(s), nil
+	default:
+		return "", "", ErrInvalidISBN
+	}
+}
+
+// ISBN10From13 derives the ISBN-10 of a valid 978-prefixed ISBN-13.
+func ISBN10From13(isbn13 string) string {
+	if len(isbn13) != 13 || !strings.HasPrefix(isbn13, "978") {
+		return ""
+	}
+	body := isbn13[3:12]
+	return body + isbn10CheckDigit(body)
+}
+
+func validISBN10(s string) bool {
+	for i := 0; i < 9; i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return (s[9] >= '0' && s[9] <= '9' || s[9] == 'X') && isbn10CheckDigit(s[:9]) == s[9:]
+}
+
+func validISBN13(s string) bool {
+	for i := 0; i < 13; i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return (strings.HasPrefix(s, "978") || strings.HasPrefix(s, "979")) &&
+		isbn13CheckDigit(s[:12]) == s[12:]
+}
+
+// isbn10CheckDigit: weights 10..2, mod 11, 10 is written as X.
+func isbn10CheckDigit(body string) string {
+	sum := 0
+	for i := 0; i < 9; i++ {
+		sum += int(body[i]-'0') * (10 - i)
+	}
+	switch check := (11 - sum%11) % 11; check {
+	case 10:
+		return "X"
+	default:
+		return string(rune('0' + check))
+	}
+}
+
+// isbn13CheckDigit: alternating weights 1 and 3, mod 10.
+func isbn13CheckDigit(body string) string {
+	sum := 0
+	for i := 0; i < 12; i++ {
+		d := int(body[i] - '0')
+		if i%2 == 1 {
+			d *= 3
+		}
+		sum += d
+	}
+	return string(rune('0' + (10-sum%10)%10))
+}
+
+// validateISBN backs the `isbn` struct tag (registered in init, it
+// replaces validator's built-in of the same name so hyphens and spaces
+// are accepted).
+func validateISBN(fl validator.FieldLevel) bool {
+	_, _, err := NormaliseISBN(fl.Field().String())
+	return err == nil
+}
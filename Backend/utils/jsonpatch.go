@@ -0,0 +1,344 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+/*───────────────────────────────────────────────────────────────*
+|               JSON Merge Patch (RFC 7396)                     |
+*───────────────────────────────────────────────────────────────*/
+
+// MergePatch applies an RFC 7396 merge patch to target and returns the
+// result. Objects merge recursively, null removes a member and any
+// other value replaces it wholesale. target may be modified in place.
+func MergePatch(target, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = map[string]any{}
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+			continue
+		}
+		t[k] = MergePatch(t[k], v)
+	}
+	return t
+}
+
+/*───────────────────────────────────────────────────────────────*
+|                  JSON Patch (RFC 6902)                        |
+*───────────────────────────────────────────────────────────────*/
+
+// PatchOp is one operation of an RFC 6902 patch document. Value is kept
+// raw so an explicit null can be told apart from a missing value.
+type PatchOp struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// PatchError reports which operation of a JSON Patch failed.
+type PatchError struct {
+	Index int
+	Op    string
+	Err   error
+}
+
+func (e *PatchError) Error() string {
+	return fmt.Sprintf("operation %d (%s): %v", e.Index, e.Op, e.Err)
+}
+
+func (e *PatchError) Unwrap() error { return e.Err }
+
+var (
+	// ErrPatchTestFailed is returned (wrapped) when a "test" op does not match.
+	ErrPatchTestFailed = errors.New("test failed")
+	// ErrPatchInvalid marks malformed operations as opposed to ones that
+	// are well-formed but cannot be applied to this document.
+	ErrPatchInvalid = errors.New("invalid operation")
+	errPathNotFound = errors.New("path not found")
+)
+
+// ApplyJSONPatch applies ops to doc in order and returns the new
+// document. The patch is atomic from the caller's point of view: on
+// error the partially patched result must be discarded.
+func ApplyJSONPatch(doc any, ops []PatchOp) (any, error) {
+	for i, op := range ops {
+		var err error
+		if doc, err = applyOp(doc, op); err != nil {
+			return nil, &PatchError{Index: i, Op: op.Op, Err: err}
+		}
+	}
+	return doc, nil
+}
+
+func applyOp(doc any, op PatchOp) (any, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case "add", "replace", "test":
+		if len(op.Value) == 0 {
+			return nil, fmt.Errorf("%w: %q requires a value", ErrPatchInvalid, op.Op)
+		}
+		value, err := decodeValue(op.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrPatchInvalid, err)
+		}
+		switch op.Op {
+		case "add":
+			return mutate(doc, path, addAt(value))
+		case "replace":
+			return mutate(doc, path, replaceAt(value))
+		default:
+			current, err := pointerGet(doc, path)
+			if err != nil {
+				return nil, err
+			}
+			if !JSONEqual(current, value) {
+				return nil, fmt.Errorf("%w at %q", ErrPatchTestFailed, op.Path)
+			}
+			return doc, nil
+		}
+
+	case "remove":
+		return mutate(doc, path, removeAt)
+
+	case "move", "copy":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		value, err := pointerGet(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		if op.Op == "move" {
+			if strings.HasPrefix(op.Path+"/", op.From+"/") && op.Path != op.From {
+				return nil, fmt.Errorf("%w: cannot move a value into itself", ErrPatchInvalid)
+			}
+			if doc, err = mutate(doc, from, removeAt); err != nil {
+				return nil, err
+			}
+		} else {
+			value = deepCopy(value)
+		}
+		return mutate(doc, path, addAt(value))
+
+	default:
+		return nil, fmt.Errorf("%w: unknown op %q", ErrPatchInvalid, op.Op)
+	}
+}
+
+/*───────────────────────────────────────────────────────────────*
+|                 JSON Pointer (RFC 6901) helpers               |
+*───────────────────────────────────────────────────────────────*/
+
+func parsePointer(p string) ([]string, error) {
+	if p == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(p, "/") {
+		return nil, fmt.Errorf("%w: pointer %q must start with /", ErrPatchInvalid, p)
+	}
+	tokens := strings.Split(p[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func pointerGet(doc any, path []string) (any, error) {
+	for _, tok := range path {
+		switch n := doc.(type) {
+		case map[string]any:
+			v, ok := n[tok]
+			if !ok {
+				return nil, fmt.Errorf("%w: %q", errPathNotFound, tok)
+			}
+			doc = v
+		case []any:
+			i, err := arrayIndex(tok, len(n)-1)
+			if err != nil {
+				return nil, err
+			}
+			doc = n[i]
+		default:
+			return nil, fmt.Errorf("%w: %q", errPathNotFound, tok)
+		}
+	}
+	return doc, nil
+}
+
+// arrayIndex parses tok as an index in [0, max].
+func arrayIndex(tok string, max int) (int, error) {
+	i, err := strconv.Atoi(tok)
+	if err != nil || i < 0 || i > max || (len(tok) > 1 && tok[0] == '0') {
+		return 0, fmt.Errorf("%w: index %q", errPathNotFound, tok)
+	}
+	return i, nil
+}
+
+// containerOp rewrites the container that holds the final path token.
+type containerOp func(container any, key string) (any, error)
+
+// mutate walks to the parent of path and applies op there. Containers
+// are returned back up the chain so slice growth/shrinkage propagates.
+func mutate(node any, path []string, op containerOp) (any, error) {
+	if len(path) == 0 {
+		return op(nil, "")
+	}
+	if len(path) == 1 {
+		return op(node, path[0])
+	}
+
+	switch n := node.(type) {
+	case map[string]any:
+		child, ok := n[path[0]]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", errPathNotFound, path[0])
+		}
+		updated, err := mutate(child, path[1:], op)
+		if err != nil {
+			return nil, err
+		}
+		n[path[0]] = updated
+		return n, nil
+	case []any:
+		i, err := arrayIndex(path[0], len(n)-1)
+		if err != nil {
+			return nil, err
+		}
+		updated, err := mutate(n[i], path[1:], op)
+		if err != nil {
+			return nil, err
+		}
+		n[i] = updated
+		return n, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", errPathNotFound, path[0])
+	}
+}
+
+// addAt and replaceAt treat the empty path (root) as whole-document
+// replacement; mutate signals it with a nil container and empty key.
+func addAt(value any) containerOp {
+	return func(container any, key string) (any, error) {
+		switch n := container.(type) {
+		case nil:
+			return value, nil
+		case map[string]any:
+			n[key] = value
+			return n, nil
+		case []any:
+			if key == "-" {
+				return append(n, value), nil
+			}
+			i, err := arrayIndex(key, len(n))
+			if err != nil {
+				return nil, err
+			}
+			n = append(n, nil)
+			copy(n[i+1:], n[i:])
+			n[i] = value
+			return n, nil
+		default:
+			return nil, fmt.Errorf("%w: %q", errPathNotFound, key)
+		}
+	}
+}
+
+func replaceAt(value any) containerOp {
+	return func(container any, key string) (any, error) {
+		switch n := container.(type) {
+		case nil:
+			return value, nil
+		case map[string]any:
+			if _, ok := n[key]; !ok {
+				return nil, fmt.Errorf("%w: %q", errPathNotFound, key)
+			}
+			n[key] = value
+			return n, nil
+		case []any:
+			i, err := arrayIndex(key, len(n)-1)
+			if err != nil {
+				return nil, err
+			}
+			n[i] = value
+			return n, nil
+		default:
+			return nil, fmt.Errorf("%w: %q", errPathNotFound, key)
+		}
+	}
+}
+
+func removeAt(container any, key string) (any, error) {
+	switch n := container.(type) {
+	case nil:
+		return nil, fmt.Errorf("%w: cannot remove the whole document", ErrPatchInvalid)
+	case map[string]any:
+		if _, ok := n[key]; !ok {
+			return nil, fmt.Errorf("%w: %q", errPathNotFound, key)
+		}
+		delete(n, key)
+		return n, nil
+	case []any:
+		i, err := arrayIndex(key, len(n)-1)
+		if err != nil {
+			return nil, err
+		}
+		return append(n[:i], n[i+1:]...), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", errPathNotFound, key)
+	}
+}
+
+/*───────────────────────────────────────────────────────────────*
+|                     Value helpers                             |
+*───────────────────────────────────────────────────────────────*/
+
+// DecodeJSON unmarshals data keeping numbers as json.Number, so integer
+// fields survive a round-trip through map[string]any untouched.
+func DecodeJSON(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+func decodeValue(raw json.RawMessage) (any, error) {
+	var v any
+	err := DecodeJSON(raw, &v)
+	return v, err
+}
+
+func deepCopy(v any) any {
+	raw, _ := json.Marshal(v)
+	out, _ := decodeValue(raw)
+	return out
+}
+
+// JSONEqual compares two decoded JSON values semantically (1 == 1.0,
+// object key order irrelevant).
+func JSONEqual(a, b any) bool {
+	var x, y any
+	ra, _ := json.Marshal(a)
+	rb, _ := json.Marshal(b)
+	_ = json.Unmarshal(ra, &x)
+	_ = json.Unmarshal(rb, &y)
+	return reflect.DeepEqual(x, y)
+}
@@ -9,9 +9,39 @@ func JSONSuccess(c *gin.Context, status int, data interface{}) {
 	})
 }
 
+// JSONSuccessMeta is JSONSuccess with a sibling "meta" object, used by
+// list endpoints for paging information.
+func JSONSuccessMeta(c *gin.Context, status int, data interface{}, meta interface{}) {
+	c.JSON(status, gin.H{
+		"success": true,
+		"data":    data,
+		"meta":    meta,
+	})
+}
+
 func JSONError(c *gin.Context, status int, message string) {
 	c.JSON(status, gin.H{
 		"success": false,
 		"error":   message,
 	})
 }
+
+// JSONErrorData is JSONError plus a "data" payload, for failures that
+// still report what was attempted, e.g. a rolled-back batch.
+func JSONErrorData(c *gin.Context, status int, message string, data interface{}) {
+	c.JSON(status, gin.H{
+		"success": false,
+		"error":   message,
+		"data":    data,
+	})
+}
+
+// JSONFieldErrors is JSONError plus a per-field breakdown, e.g.
+// {"success":false,"error":"validation failed","fields":{"title":"is required"}}.
+func JSONFieldErrors(c *gin.Context, status int, message string, fields map[string]string) {
+	c.JSON(status, gin.H{
+		"success": false,
+		"error":   message,
+		"fields":  fields,
+	})
+}
@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+/*───────────────────────────────────────────────────────────────*
+|           Fuzzy matching of titles and author names           |
+*───────────────────────────────────────────────────────────────*/
+
+// leading words that do not tell two titles apart
+var titleArticles = map[string]bool{"the": true, "a": true, "an": true}
+
+// words lowercases s and splits it on anything that is not a letter or
+// digit, so punctuation and spacing differences disappear.
+func words(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// NormaliseTitle reduces a title to lower-case words without a leading
+// article: "The Go Programming Language!" → "go programming language".
+func NormaliseTitle(title string) string {
+	w := words(title)
+	if len(w) > 1 && titleArticles[w[0]] {
+		w = w[1:]
+	}
+	return strings.Join(w, " ")
+}
+
+// MainTitle is the part of a title before a subtitle or edition note
+// (":", "(" or " - "), normalised like NormaliseTitle.
+func MainTitle(title string) string {
+	if i := strings.IndexAny(title, ":("); i > 0 {
+		title = title[:i]
+	}
+	if i := strings.Index(title, " - "); i > 0 {
+		title = title[:i]
+	}
+	return NormaliseTitle(title)
+}
+
+// NormaliseAuthor reduces a byline to its sorted name words, dropping
+// initials, so "Martin, Robert C." and "Robert C Martin" agree.
+func NormaliseAuthor(author string) string {
+	var kept []string
+	for _, w := range words(author) {
+		if len([]rune(w)) > 1 {
+			kept = append(kept, w)
+		}
+	}
+	sort.Strings(kept)
+	return strings.Join(kept, " ")
+}
+
+// words that join the names in a byline rather than being one
+var bylineJoiners = map[string]bool{"and": true, "with": true}
+
+// NameKeys is the set of short prefixes of a byline's name words, one
+// per word: "Robert C. Martin" → "mar", "rob". Two bylines within a
+// typo of each other nearly always share one, whatever the order or
+// separator of their authors. Empty when the byline has no name words.
+func NameKeys(author string) []string {
+	const prefix = 3
+	seen := map[string]bool{}
+	var keys []string
+	for _, w := range words(author) {
+		r := []rune(w)
+		if len(r) < 2 || bylineJoiners[w] {
+			continue
+		}
+		k := string(r[:min(prefix, len(r))])
+		if !seen[k] {
+			seen[k] = true
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Similarity is 1 minus the edit distance between a and b over the
+// longer length, in runes: 1 for equal strings, 0 for nothing shared.
+func Similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
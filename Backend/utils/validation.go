@@ -1,6 +1,11 @@
 package utils
 
 import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
 	"github.com/go-playground/validator/v10"
 	"github.com/hasan-kayan/TaskGo/models"
 )
@@ -9,10 +14,125 @@ var validate *validator.Validate
 
 func init() {
 	validate = validator.New()
+
+	// report fields by their JSON name ("cover_image_url", not "CoverImageURL")
+	validate.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name := strings.SplitN(f.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return f.Name
+		}
+		return name
+	})
+
+	_ = validate.RegisterValidation("isbn", validateISBN)
 }
 
 // ValidateBook performs field-level validation for the Book struct.
 // Returns an error if any field fails the defined validation rules.
+// On success the ISBN is rewritten to its canonical ISBN-13 and ISBN10
+// is derived from it, so every write path stores the same form.
 func ValidateBook(book *models.Book) error {
-	return validate.Struct(book)
+	if err := validate.Struct(book); err != nil {
+		return err
+	}
+	book.ISBN10 = ""
+	if book.ISBN != "" {
+		book.ISBN, book.ISBN10, _ = NormaliseISBN(book.ISBN)
+	}
+	return nil
+}
+
+// ValidateAuthor performs field-level validation for the Author struct.
+func ValidateAuthor(author *models.Author) error {
+	return validate.Struct(author)
+}
+
+// ValidatePublisher performs field-level validation for the Publisher struct.
+func ValidatePublisher(publisher *models.Publisher) error {
+	return validate.Struct(publisher)
+}
+
+// ValidateTag performs field-level validation for the Tag struct.
+func ValidateTag(tag *models.Tag) error {
+	return validate.Struct(tag)
+}
+
+// ValidateSeries performs field-level validation for the Series struct.
+func ValidateSeries(series *models.Series) error {
+	return validate.Struct(series)
+}
+
+// ValidateCopy performs field-level validation for the Copy struct.
+func ValidateCopy(item *models.Copy) error {
+	return validate.Struct(item)
+}
+
+// ValidateMember performs field-level validation for the Member struct.
+func ValidateMember(member *models.Member) error {
+	return validate.Struct(member)
+}
+
+// ValidateFinePolicy performs field-level validation for the FinePolicy struct.
+func ValidateFinePolicy(policy *models.FinePolicy) error {
+	return validate.Struct(policy)
+}
+
+// ValidateClosedDay performs field-level validation for the ClosedDay struct.
+func ValidateClosedDay(day *models.ClosedDay) error {
+	return validate.Struct(day)
+}
+
+// ValidateReview performs field-level validation for the Review struct.
+func ValidateReview(review *models.Review) error {
+	return validate.Struct(review)
+}
+
+// ValidateReadingList performs field-level validation for the ReadingList struct.
+func ValidateReadingList(list *models.ReadingList) error {
+	return validate.Struct(list)
+}
+
+// FieldErrors flattens a validation error into JSON field → message.
+// Errors that did not come from the validator are returned under "_".
+func FieldErrors(err error) map[string]string {
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return map[string]string{"_": err.Error()}
+	}
+
+	out := make(map[string]string, len(verrs))
+	for _, fe := range verrs {
+		out[fe.Field()] = describe(fe)
+	}
+	return out
+}
+
+func describe(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "gte":
+		return fmt.Sprintf("must be greater than or equal to %s", fe.Param())
+	case "lte":
+		return fmt.Sprintf("must be less than or equal to %s", fe.Param())
+	case "max":
+		return fmt.Sprintf("must be at most %s characters long", fe.Param())
+	case "url":
+		return "must be a valid URL"
+	case "len":
+		return fmt.Sprintf("must be %s characters long", fe.Param())
+	case "isbn":
+		return "must be a valid ISBN-10 or ISBN-13"
+	case "email":
+		return "must be a valid email address"
+	case "oneof":
+		return "must be one of: " + fe.Param()
+	case "datetime":
+		return "must be a date in the form " + fe.Param()
+	default:
+		return fmt.Sprintf("failed %q validation", fe.Tag())
+	}
 }